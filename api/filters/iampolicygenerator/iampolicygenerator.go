@@ -10,46 +10,118 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+const (
+	awsRoleArnAnnotation        = "eks.amazonaws.com/role-arn"
+	azureClientIdAnnotation     = "azure.workload.identity/client-id"
+	azureTenantIdAnnotation     = "azure.workload.identity/tenant-id"
+	defaultAWSPartition         = "aws"
+	gkeServiceAccountAnnotation = "iam.gke.io/gcp-service-account"
+)
+
 type Filter struct {
 	IAMPolicyGenerator types.IAMPolicyGeneratorArgs `json:",inline,omitempty" yaml:",inline,omitempty"`
 }
 
-// Filter adds a GKE service account object to nodes
+// Filter adds a service account object bound to a cloud provider identity to nodes
 func (f Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	var IAMPolicyResources []*yaml.RNode
+	var err error
 	switch f.IAMPolicyGenerator.Cloud {
 	case types.GKE:
-		IAMPolicyResources, err := f.generateGkeIAMPolicyResources()
-		if err != nil {
-			return nil, err
-		}
-		nodes = append(nodes, IAMPolicyResources...)
+		IAMPolicyResources, err = f.generateGkeIAMPolicyResources()
+	case types.EKS:
+		IAMPolicyResources, err = f.generateEksIAMPolicyResources()
+	case types.AKS:
+		IAMPolicyResources, err = f.generateAksIAMPolicyResources()
 	default:
 		return nil, fmt.Errorf("cloud provider %s not supported yet", f.IAMPolicyGenerator.Cloud)
 	}
-	return nodes, nil
+	if err != nil {
+		return nil, err
+	}
+	return append(nodes, IAMPolicyResources...), nil
 }
 
 func (f Filter) generateGkeIAMPolicyResources() ([]*yaml.RNode, error) {
-	var result []*yaml.RNode
-	input := fmt.Sprintf(`
-apiVersion: v1
-kind: ServiceAccount
-metadata:
-  annotations:
-    iam.gke.io/gcp-service-account: %s@%s.iam.gserviceaccount.com
-  name: %s
-`, f.IAMPolicyGenerator.ServiceAccount.Name,
-		f.IAMPolicyGenerator.ProjectId,
-		f.IAMPolicyGenerator.KubernetesService.Name)
+	sa, err := f.serviceAccount(map[string]string{
+		gkeServiceAccountAnnotation: fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
+			f.IAMPolicyGenerator.ServiceAccount.Name,
+			f.IAMPolicyGenerator.ProjectId),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*yaml.RNode{sa}, nil
+}
 
-	if f.IAMPolicyGenerator.Namespace != "" {
-		input += fmt.Sprintf("\n  namespace: %s", f.IAMPolicyGenerator.Namespace)
+// generateEksIAMPolicyResources returns a service account assuming an
+// IAM role, with IAM roles for service accounts.
+func (f Filter) generateEksIAMPolicyResources() ([]*yaml.RNode, error) {
+	role := f.IAMPolicyGenerator.AWS
+	if role == nil || role.AccountId == "" || role.RoleName == "" {
+		return nil, fmt.Errorf("cloud provider %s requires aws.accountId and aws.roleName",
+			f.IAMPolicyGenerator.Cloud)
+	}
+	partition := role.Partition
+	if partition == "" {
+		partition = defaultAWSPartition
+	}
+	sa, err := f.serviceAccount(map[string]string{
+		awsRoleArnAnnotation: fmt.Sprintf("arn:%s:iam::%s:role/%s",
+			partition, role.AccountId, role.RoleName),
+	})
+	if err != nil {
+		return nil, err
 	}
+	return []*yaml.RNode{sa}, nil
+}
 
-	sa, err := yaml.Parse(input)
+// generateAksIAMPolicyResources returns a service account using a managed
+// identity, with Azure Workload Identity.  The webhook of Azure Workload
+// Identity reads the azure.workload.identity/use label from the pods, not
+// from their service account, and the generator doesn't see the workloads,
+// so the pods using the service account must be labeled separately.
+func (f Filter) generateAksIAMPolicyResources() ([]*yaml.RNode, error) {
+	identity := f.IAMPolicyGenerator.Azure
+	if identity == nil || identity.ClientId == "" {
+		return nil, fmt.Errorf("cloud provider %s requires azure.clientId",
+			f.IAMPolicyGenerator.Cloud)
+	}
+	annotations := map[string]string{
+		azureClientIdAnnotation: identity.ClientId,
+	}
+	if identity.TenantId != "" {
+		annotations[azureTenantIdAnnotation] = identity.TenantId
+	}
+	sa, err := f.serviceAccount(annotations)
 	if err != nil {
 		return nil, err
 	}
+	return []*yaml.RNode{sa}, nil
+}
 
-	return append(result, sa), nil
+// serviceAccount returns the Kubernetes service account with the given
+// annotations.
+func (f Filter) serviceAccount(annotations map[string]string) (*yaml.RNode, error) {
+	sa, err := yaml.Parse(`
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations: {}
+`)
+	if err != nil {
+		return nil, err
+	}
+	if err = sa.SetAnnotations(annotations); err != nil {
+		return nil, err
+	}
+	if err = sa.SetName(f.IAMPolicyGenerator.KubernetesService.Name); err != nil {
+		return nil, err
+	}
+	if f.IAMPolicyGenerator.Namespace != "" {
+		if err = sa.SetNamespace(f.IAMPolicyGenerator.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	return sa, nil
 }
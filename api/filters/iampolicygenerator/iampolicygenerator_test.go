@@ -57,6 +57,72 @@ metadata:
   annotations:
     iam.gke.io/gcp-service-account: gsa-name@project-id.iam.gserviceaccount.com
   name: k8s-sa-name
+`,
+		},
+		"eks": {
+			args: types.IAMPolicyGeneratorArgs{
+				Cloud: types.EKS,
+				KubernetesService: types.KubernetesService{
+					Namespace: "k8s-namespace",
+					Name:      "k8s-sa-name",
+				},
+				AWS: &types.AWSRole{
+					AccountId: "111122223333",
+					RoleName:  "app/s3-reader",
+				},
+			},
+			expected: `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations:
+    eks.amazonaws.com/role-arn: arn:aws:iam::111122223333:role/app/s3-reader
+  name: k8s-sa-name
+  namespace: k8s-namespace
+`,
+		},
+		"eks with partition": {
+			args: types.IAMPolicyGeneratorArgs{
+				Cloud: types.EKS,
+				KubernetesService: types.KubernetesService{
+					Name: "k8s-sa-name",
+				},
+				AWS: &types.AWSRole{
+					AccountId: "111122223333",
+					RoleName:  "s3-reader",
+					Partition: "aws-cn",
+				},
+			},
+			expected: `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations:
+    eks.amazonaws.com/role-arn: arn:aws-cn:iam::111122223333:role/s3-reader
+  name: k8s-sa-name
+`,
+		},
+		"aks": {
+			args: types.IAMPolicyGeneratorArgs{
+				Cloud: types.AKS,
+				KubernetesService: types.KubernetesService{
+					Namespace: "k8s-namespace",
+					Name:      "k8s-sa-name",
+				},
+				Azure: &types.AzureIdentity{
+					ClientId: "00000000-1111-2222-3333-444444444444",
+					TenantId: "55555555-6666-7777-8888-999999999999",
+				},
+			},
+			expected: `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations:
+    azure.workload.identity/client-id: 00000000-1111-2222-3333-444444444444
+    azure.workload.identity/tenant-id: 55555555-6666-7777-8888-999999999999
+  name: k8s-sa-name
+  namespace: k8s-namespace
 `,
 		},
 	}
@@ -73,3 +139,33 @@ metadata:
 		})
 	}
 }
+
+func TestFilterErrors(t *testing.T) {
+	testCases := map[string]struct {
+		args     types.IAMPolicyGeneratorArgs
+		expected string
+	}{
+		"unknown cloud": {
+			args:     types.IAMPolicyGeneratorArgs{Cloud: "other"},
+			expected: "cloud provider other not supported yet",
+		},
+		"eks without role": {
+			args: types.IAMPolicyGeneratorArgs{
+				Cloud: types.EKS,
+				AWS:   &types.AWSRole{AccountId: "111122223333"},
+			},
+			expected: "cloud provider eks requires aws.accountId and aws.roleName",
+		},
+		"aks without identity": {
+			args:     types.IAMPolicyGeneratorArgs{Cloud: types.AKS},
+			expected: "cloud provider aks requires azure.clientId",
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := filtertest.RunFilterE(t, "", Filter{IAMPolicyGenerator: tc.args})
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
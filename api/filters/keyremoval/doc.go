@@ -0,0 +1,6 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package keyremoval contains a kio.Filter implementation of the kustomize
+// label and annotation removal transformer.
+package keyremoval
@@ -0,0 +1,156 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package keyremoval
+
+import (
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/filters/fsslice"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Filter removes the selected keys from the maps found at the FsSlice
+// fields, e.g. from labels, selectors or annotations.
+// Labels and annotations of object metadata left empty are removed.
+type Filter struct {
+	// Removal selects the keys to remove.
+	Removal types.KeyRemoval `yaml:"removal,omitempty"`
+
+	// FsSlice identifies the map fields.
+	FsSlice types.FsSlice
+}
+
+var _ kio.Filter = Filter{}
+
+func (f Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if f.Removal.IsEmpty() {
+		return nodes, nil
+	}
+	matches, err := matcher(f.Removal)
+	if err != nil {
+		return nil, err
+	}
+	return kio.FilterAll(yaml.FilterFunc(
+		func(node *yaml.RNode) (*yaml.RNode, error) {
+			emptied := map[*yaml.Node]bool{}
+			if err := node.PipeE(fsslice.Filter{
+				FsSlice:  f.FsSlice,
+				SetValue: removeMatching(matches, emptied),
+			}); err != nil {
+				return nil, err
+			}
+			if len(emptied) > 0 {
+				clearEmptied(node.YNode(), emptied)
+			}
+			return node, nil
+		})).Filter(nodes)
+}
+
+// removeMatching returns a SetFn removing the matching keys from a map, and
+// recording the maps it empties.
+func removeMatching(matches func(string) bool, emptied map[*yaml.Node]bool) func(*yaml.RNode) error {
+	return func(node *yaml.RNode) error {
+		if node.YNode().Kind != yaml.MappingNode || len(node.Content()) == 0 {
+			return nil
+		}
+		content := node.Content()
+		var kept []*yaml.Node
+		for i := 0; i < len(content); i += 2 {
+			if !matches(content[i].Value) {
+				kept = append(kept, content[i], content[i+1])
+			}
+		}
+		node.YNode().Content = kept
+		if len(kept) == 0 {
+			emptied[node.YNode()] = true
+		}
+		return nil
+	}
+}
+
+// clearEmptied removes the labels and annotations of the object metadata
+// found in node, e.g. in pod templates, that were emptied by the removal.
+func clearEmptied(node *yaml.Node, emptied map[*yaml.Node]bool) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			if node.Content[i].Value == yaml.MetadataField {
+				clearEmptiedFields(node.Content[i+1], emptied)
+			}
+			clearEmptied(node.Content[i+1], emptied)
+		}
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			clearEmptied(n, emptied)
+		}
+	}
+}
+
+func clearEmptiedFields(meta *yaml.Node, emptied map[*yaml.Node]bool) {
+	if meta.Kind != yaml.MappingNode {
+		return
+	}
+	var kept []*yaml.Node
+	for i := 0; i < len(meta.Content); i += 2 {
+		name, value := meta.Content[i].Value, meta.Content[i+1]
+		if (name == yaml.LabelsField || name == yaml.AnnotationsField) && emptied[value] {
+			continue
+		}
+		kept = append(kept, meta.Content[i], value)
+	}
+	meta.Content = kept
+}
+
+// matcher returns a function reporting whether a key is selected by r.
+func matcher(r types.KeyRemoval) (func(string) bool, error) {
+	keys := make(map[string]bool, len(r.Keys))
+	for _, k := range r.Keys {
+		keys[k] = true
+	}
+	regexes := make([]*regexp.Regexp, 0, len(r.Regexes))
+	for _, s := range r.Regexes {
+		re, err := regexp.Compile("^(?:" + s + ")$")
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "invalid regex %q", s)
+		}
+		regexes = append(regexes, re)
+	}
+	return func(key string) bool {
+		if keys[key] {
+			return true
+		}
+		for _, p := range r.Prefixes {
+			if strings.HasPrefix(key, p) {
+				return true
+			}
+		}
+		for _, re := range regexes {
+			if re.MatchString(key) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// ValidateRemoval returns an error if r has an invalid regex or an empty
+// key or prefix.
+func ValidateRemoval(r types.KeyRemoval) error {
+	for _, k := range r.Keys {
+		if k == "" {
+			return errors.Errorf("keys must not be empty")
+		}
+	}
+	for _, p := range r.Prefixes {
+		if p == "" {
+			return errors.Errorf("prefixes must not be empty")
+		}
+	}
+	_, err := matcher(r)
+	return err
+}
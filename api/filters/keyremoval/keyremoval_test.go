@@ -0,0 +1,189 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package keyremoval
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	filtertest_test "sigs.k8s.io/kustomize/api/testutils/filtertest"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+func TestKeyRemoval_Filter(t *testing.T) {
+	testCases := map[string]struct {
+		input          string
+		expectedOutput string
+		filter         Filter
+	}{
+		"keys prefixes and regexes": {
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+  labels:
+    app: web
+    app.kubernetes.io/managed-by: Helm
+    helm.sh/chart: web-1.0.0
+    team.example.com/owner: alice
+`,
+			expectedOutput: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+  labels:
+    app: web
+`,
+			filter: Filter{
+				Removal: types.KeyRemoval{
+					Keys:     []string{"app.kubernetes.io/managed-by"},
+					Prefixes: []string{"helm.sh/"},
+					Regexes:  []string{`.*\.example\.com/.*`},
+				},
+				FsSlice: []types.FieldSpec{{Path: "metadata/labels"}},
+			},
+		},
+		"regexes match whole keys": {
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+  labels:
+    chart: web
+    helm-chart: web
+`,
+			expectedOutput: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+  labels:
+    helm-chart: web
+`,
+			filter: Filter{
+				Removal: types.KeyRemoval{Regexes: []string{"chart"}},
+				FsSlice: []types.FieldSpec{{Path: "metadata/labels"}},
+			},
+		},
+		"selectors and templates": {
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: instance
+  labels:
+    app: web
+    helm.sh/chart: web-1.0.0
+spec:
+  selector:
+    matchLabels:
+      app: web
+      helm.sh/chart: web-1.0.0
+  template:
+    metadata:
+      labels:
+        app: web
+        helm.sh/chart: web-1.0.0
+`,
+			expectedOutput: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: instance
+  labels:
+    app: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+`,
+			filter: Filter{
+				Removal: types.KeyRemoval{Prefixes: []string{"helm.sh/"}},
+				FsSlice: []types.FieldSpec{
+					{Path: "metadata/labels"},
+					{Path: "spec/selector/matchLabels", Gvk: resid.Gvk{Kind: "Deployment"}},
+					{Path: "spec/template/metadata/labels", Gvk: resid.Gvk{Kind: "Deployment"}},
+				},
+			},
+		},
+		"emptied metadata is removed": {
+			input: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+  labels:
+    helm.sh/chart: web-1.0.0
+  annotations:
+    meta.helm.sh/release-name: web
+`,
+			expectedOutput: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: instance
+`,
+			filter: Filter{
+				Removal: types.KeyRemoval{Regexes: []string{`(meta\.)?helm\.sh/.*`}},
+				FsSlice: []types.FieldSpec{
+					{Path: "metadata/labels"},
+					{Path: "metadata/annotations"},
+				},
+			},
+		},
+		"missing fields": {
+			input: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: instance
+  labels: {}
+`,
+			expectedOutput: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: instance
+  labels: {}
+`,
+			filter: Filter{
+				Removal: types.KeyRemoval{Keys: []string{"app"}},
+				FsSlice: []types.FieldSpec{
+					{Path: "metadata/labels"},
+					{Path: "spec/selector", Gvk: resid.Gvk{Kind: "Service"}},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			if !assert.Equal(t,
+				strings.TrimSpace(tc.expectedOutput),
+				strings.TrimSpace(filtertest_test.RunFilter(t, tc.input, tc.filter))) {
+				t.FailNow()
+			}
+		})
+	}
+}
+
+func TestValidateRemoval(t *testing.T) {
+	require.NoError(t, ValidateRemoval(types.KeyRemoval{
+		Keys: []string{"app"}, Prefixes: []string{"helm.sh/"}, Regexes: []string{".*"},
+	}))
+	require.ErrorContains(t,
+		ValidateRemoval(types.KeyRemoval{Regexes: []string{"("}}), `invalid regex "("`)
+	require.ErrorContains(t,
+		ValidateRemoval(types.KeyRemoval{Prefixes: []string{""}}), "prefixes must not be empty")
+}
@@ -30,9 +30,22 @@ type Filter struct {
 	// - none: all subjects will be skipped.
 	SetRoleBindingSubjects RoleBindingSubjectMode `json:"setRoleBindingSubjects" yaml:"setRoleBindingSubjects"`
 
+	// Mapping, if set, moves resources between namespaces instead of setting
+	// Namespace on all of them.  Only resources and references in a namespace
+	// listed as the From of a Mapping are changed; Namespace and UnsetOnly are
+	// ignored.  References rewritten by the mapping include the FsSlice fields,
+	// (cluster) role binding subjects and webhook configuration services.
+	Mapping []Mapping `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+
 	trackableSetter filtersutil.TrackableSetter
 }
 
+// Mapping moves resources in namespace From to namespace To.
+type Mapping struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
 type RoleBindingSubjectMode string
 
 const (
@@ -56,6 +69,9 @@ func (ns Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 
 // Run runs the filter on a single node rather than a slice
 func (ns Filter) run(node *yaml.RNode) (*yaml.RNode, error) {
+	if len(ns.Mapping) > 0 {
+		return ns.runMapping(node)
+	}
 	// Special handling for metadata.namespace and metadata.name -- :(
 	// never let SetEntry handle metadata.namespace--it will incorrectly include cluster-scoped resources
 	// only update metadata.name if api version is expected one--so-as it leaves other resources of kind namespace alone
@@ -88,6 +104,83 @@ func (ns Filter) run(node *yaml.RNode) (*yaml.RNode, error) {
 	return node, errors.WrapPrefixf(err, "namespace transformation failed")
 }
 
+// runMapping runs the filter in mapping mode on a single node.  Every
+// namespace field known to the filter is rewritten if its value is mapped,
+// regardless of the namespace of the node holding it.
+func (ns Filter) runMapping(node *yaml.RNode) (*yaml.RNode, error) {
+	if err := ValidateMapping(ns.Mapping); err != nil {
+		return nil, err
+	}
+	gvk := resid.GvkFromNode(node)
+	var fsSlice types.FsSlice
+	for _, fs := range ns.removeUnneededMetaFieldSpecs(node.GetApiVersion(), ns.FsSlice) {
+		// only existing references are mapped
+		fs.CreateIfNotPresent = false
+		fsSlice = append(fsSlice, fs)
+	}
+	if !gvk.IsClusterScoped() {
+		fsSlice = append(fsSlice, types.FieldSpec{Path: types.MetadataNamespacePath})
+	}
+	if isRoleBinding(gvk.Kind) {
+		fsSlice = ns.removeRoleBindingSubjectFieldSpecs(fsSlice)
+		if ns.SetRoleBindingSubjects != NoSubjects {
+			fsSlice = append(fsSlice, types.FieldSpec{Gvk: gvk, Path: subjectsNamespacePath})
+		}
+	}
+	fsSlice = append(fsSlice, webhookServiceFieldSpecs...)
+
+	err := node.PipeE(fsslice.Filter{
+		FsSlice:  fsSlice,
+		SetValue: ns.mappingSetter(),
+	})
+	invalidKindErr := &yaml.InvalidNodeKindError{}
+	if err != nil && errors.As(err, &invalidKindErr) && invalidKindErr.ActualNodeKind() != yaml.ScalarNode {
+		return nil, errors.WrapPrefixf(err, "namespace field specs must target scalar nodes")
+	}
+	return node, errors.WrapPrefixf(err, "namespace transformation failed")
+}
+
+// mappingSetter returns a SetFn replacing a mapped namespace.
+func (ns *Filter) mappingSetter() filtersutil.SetFn {
+	return func(node *yaml.RNode) error {
+		for _, m := range ns.Mapping {
+			if yaml.GetValue(node) == m.From {
+				return ns.trackableSetter.SetEntry("", m.To, yaml.NodeTagString)(node)
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateMapping returns an error if mapping is ambiguous or incomplete.
+func ValidateMapping(mapping []Mapping) error {
+	from := map[string]bool{}
+	for _, m := range mapping {
+		if m.From == "" || m.To == "" {
+			return errors.Errorf("namespace mapping %q -> %q must set both from and to", m.From, m.To)
+		}
+		if from[m.From] {
+			return errors.Errorf("namespace %q is mapped more than once", m.From)
+		}
+		from[m.From] = true
+	}
+	return nil
+}
+
+// webhookServiceFieldSpecs locate the namespace of the services called by
+// webhook configurations.  They are only rewritten in mapping mode, since the
+// services are usually not in the namespace of the build.
+var webhookServiceFieldSpecs = []types.FieldSpec{
+	{
+		Gvk:  resid.Gvk{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+		Path: "webhooks/clientConfig/service/namespace",
+	},
+	{
+		Gvk:  resid.Gvk{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+		Path: "webhooks/clientConfig/service/namespace",
+	},
+}
+
 // metaNamespaceHack is a hack for implementing the namespace transform
 // for the metadata.namespace field on namespace scoped resources.
 func (ns Filter) metaNamespaceHack(obj *yaml.RNode, gvk resid.Gvk) error {
@@ -386,6 +386,179 @@ metadata:
 `,
 		filter: namespace.Filter{Namespace: "01234"},
 	},
+	{
+		name: "mapping",
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: moved
+  namespace: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kept
+  namespace: c
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unset
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: a
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: crb
+subjects:
+- kind: ServiceAccount
+  name: moved
+  namespace: a
+- kind: ServiceAccount
+  name: kept
+  namespace: c
+- kind: User
+  name: someone
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: webhook
+webhooks:
+- name: moved.example.com
+  clientConfig:
+    service:
+      name: moved
+      namespace: a
+- name: kept.example.com
+  clientConfig:
+    service:
+      name: kept
+      namespace: c
+---
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1.example.com
+spec:
+  service:
+    name: moved
+    namespace: a
+---
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1.local.example.com
+spec: {}
+`,
+		expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: moved
+  namespace: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kept
+  namespace: c
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unset
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: b
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: crb
+subjects:
+- kind: ServiceAccount
+  name: moved
+  namespace: b
+- kind: ServiceAccount
+  name: kept
+  namespace: c
+- kind: User
+  name: someone
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: webhook
+webhooks:
+- name: moved.example.com
+  clientConfig:
+    service:
+      name: moved
+      namespace: b
+- name: kept.example.com
+  clientConfig:
+    service:
+      name: kept
+      namespace: c
+---
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1.example.com
+spec:
+  service:
+    name: moved
+    namespace: b
+---
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1.local.example.com
+spec: {}
+`,
+		filter: namespace.Filter{
+			Namespace: "ignored",
+			Mapping:   []namespace.Mapping{{From: "a", To: "b"}},
+		},
+	},
+
+	{
+		name: "mapping_no_subjects",
+		input: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: rb
+  namespace: a
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: a
+`,
+		expected: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: rb
+  namespace: b
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: a
+`,
+		filter: namespace.Filter{
+			Mapping:                []namespace.Mapping{{From: "a", To: "b"}},
+			SetRoleBindingSubjects: namespace.NoSubjects,
+		},
+	},
 }
 
 type TestCase struct {
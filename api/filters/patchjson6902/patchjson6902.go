@@ -4,22 +4,47 @@
 package patchjson6902
 
 import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"regexp"
+	"strconv"
 	"strings"
 
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 	k8syaml "sigs.k8s.io/yaml"
 )
 
+// Filter applies a JSON6902 patch to each resource.
+//
+// In addition to indices, the elements of lists can be addressed
+// by the value of one of their fields, e.g.
+// `/spec/containers/[name=app]/image` is the image of the container
+// named app.  Where the value isn't a list, such a segment is an
+// ordinary key.
 type Filter struct {
 	Patch string
 
+	// SkipOnFailedTest leaves a resource unchanged, rather than failing,
+	// when a test operation of the patch fails for it, or when a path of
+	// the patch matches no list element of it.  This lets a single patch
+	// target many resources, and change only those passing its tests.
+	SkipOnFailedTest bool
+
 	decodedPatch jsonpatch.Patch
 }
 
 var _ kio.Filter = Filter{}
 
+// keySelector matches a path segment selecting a list element by key, e.g. [name=app].
+var keySelector = regexp.MustCompile(`^\[([^=\]]+)=(.*)\]$`)
+
+// errSkip is returned when the patch is skipped for a resource.
+var errSkip = stderrors.New("patch skipped")
+
 func (pf Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	decodedPatch, err := pf.decodePatch()
 	if err != nil {
@@ -56,10 +81,165 @@ func (pf Filter) run(node *yaml.RNode) (*yaml.RNode, error) {
 	if err != nil {
 		return nil, err
 	}
-	res, err := pf.decodedPatch.Apply(b)
+	var res []byte
+	if pf.SkipOnFailedTest || hasKeySelectors(pf.decodedPatch) {
+		res, err = pf.applyEach(b)
+	} else {
+		res, err = pf.decodedPatch.Apply(b)
+	}
+	if stderrors.Is(err, errSkip) {
+		return node, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 	err = node.UnmarshalJSON(res)
 	return node, err
 }
+
+// applyEach applies the operations of the patch one by one, so that the
+// list elements selected by key are found in the document as patched by
+// the previous operations.
+func (pf Filter) applyEach(doc []byte) ([]byte, error) {
+	for _, op := range pf.decodedPatch {
+		resolved, err := resolveKeySelectors(doc, op)
+		if err != nil {
+			if pf.SkipOnFailedTest {
+				return nil, errSkip
+			}
+			return nil, err
+		}
+		doc, err = jsonpatch.Patch{resolved}.Apply(doc)
+		if err != nil {
+			if pf.SkipOnFailedTest && op.Kind() == "test" {
+				return nil, errSkip
+			}
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func hasKeySelectors(patch jsonpatch.Patch) bool {
+	for _, op := range patch {
+		for _, field := range []string{"path", "from"} {
+			if p, err := operationPath(op, field); err == nil && strings.Contains(p, "/[") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func operationPath(op jsonpatch.Operation, field string) (string, error) {
+	raw, found := op[field]
+	if !found || raw == nil {
+		return "", errors.Errorf("operation has no %s", field)
+	}
+	var p string
+	if err := json.Unmarshal(*raw, &p); err != nil {
+		return "", errors.WrapPrefixf(err, "decoding the %s of an operation", field)
+	}
+	return p, nil
+}
+
+// resolveKeySelectors returns a copy of op whose path and from select
+// list elements by index rather than by key, as found in doc.
+func resolveKeySelectors(doc []byte, op jsonpatch.Operation) (jsonpatch.Operation, error) {
+	var decoded interface{}
+	resolved := make(jsonpatch.Operation, len(op))
+	for k, v := range op {
+		resolved[k] = v
+	}
+	for _, field := range []string{"path", "from"} {
+		p, err := operationPath(op, field)
+		if err != nil || !strings.Contains(p, "/[") {
+			continue
+		}
+		if decoded == nil {
+			d := json.NewDecoder(bytes.NewReader(doc))
+			d.UseNumber()
+			if err = d.Decode(&decoded); err != nil {
+				return nil, errors.Wrap(err)
+			}
+		}
+		if p, err = resolvePath(decoded, p); err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(p)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		raw := json.RawMessage(b)
+		resolved[field] = &raw
+	}
+	return resolved, nil
+}
+
+// resolvePath replaces the key selectors of the JSON pointer p
+// by the indices of the list elements of doc they select.  A segment
+// like a key selector is a key selector only where doc has a list,
+// and an ordinary key elsewhere, e.g. of an annotation.
+func resolvePath(doc interface{}, p string) (string, error) {
+	segments := strings.Split(p, "/")
+	current := doc
+	for i := 1; i < len(segments); i++ {
+		segment := unescapePointer(segments[i])
+		list, isList := current.([]interface{})
+		if match := keySelector.FindStringSubmatch(segment); match != nil && isList {
+			index := indexOfElement(list, match[1], match[2])
+			if index < 0 {
+				return "", errors.Errorf(
+					"unable to resolve %s in %s: no element of %s matches",
+					segment, p, strings.Join(segments[:i], "/"))
+			}
+			segments[i] = strconv.Itoa(index)
+			current = list[index]
+			continue
+		}
+		switch c := current.(type) {
+		case map[string]interface{}:
+			current = c[segment]
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(c) {
+				current = nil
+			} else {
+				current = c[index]
+			}
+		default:
+			current = nil
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// indexOfElement returns the index of the first element of list
+// whose field key has the given value, or -1.
+func indexOfElement(list []interface{}, key, value string) int {
+	for i, element := range list {
+		m, ok := element.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch v := m[key].(type) {
+		case string:
+			if v == value {
+				return i
+			}
+		case json.Number:
+			if v.String() == value {
+				return i
+			}
+		case bool:
+			if strconv.FormatBool(v) == value {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func unescapePointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+}
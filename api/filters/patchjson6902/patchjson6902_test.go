@@ -156,6 +156,97 @@ spec:
         - arg3
         image: nginx
         name: my-nginx
+`,
+		},
+		{
+			testName: "list element by key",
+			input:    input,
+			filter: Filter{
+				Patch: `
+- op: replace
+  path: /spec/template/spec/containers/[name=nginx]/image
+  value: nginx:1.27
+- op: add
+  path: /spec/template/spec/containers/[name=nginx]/args
+  value: [--debug]
+`,
+			},
+			expectedOutput: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  replica: 2
+  template:
+    metadata:
+      labels:
+        old-label: old-value
+    spec:
+      containers:
+      - args:
+        - --debug
+        image: nginx:1.27
+        name: nginx
+`,
+		},
+		{
+			testName: "skip on failed test",
+			input:    input,
+			filter: Filter{
+				Patch: `
+- op: test
+  path: /spec/replica
+  value: 3
+- op: replace
+  path: /spec/replica
+  value: 5
+`,
+				SkipOnFailedTest: true,
+			},
+			expectedOutput: input,
+		},
+		{
+			testName: "skip on missing list element",
+			input:    input,
+			filter: Filter{
+				Patch: `
+- op: remove
+  path: /spec/template/spec/containers/[name=sidecar]
+`,
+				SkipOnFailedTest: true,
+			},
+			expectedOutput: input,
+		},
+		{
+			testName: "passed test",
+			input:    input,
+			filter: Filter{
+				Patch: `
+- op: test
+  path: /spec/template/spec/containers/[name=nginx]/image
+  value: nginx
+- op: replace
+  path: /spec/replica
+  value: 5
+`,
+				SkipOnFailedTest: true,
+			},
+			expectedOutput: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+spec:
+  replica: 5
+  template:
+    metadata:
+      labels:
+        old-label: old-value
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
 `,
 		},
 	}
@@ -171,3 +262,47 @@ spec:
 		})
 	}
 }
+
+func TestMissingListElement(t *testing.T) {
+	_, err := filtertest.RunFilterE(t, input, Filter{
+		Patch: `
+- op: remove
+  path: /spec/template/spec/containers/[name=sidecar]
+`,
+	})
+	assert.EqualError(t, err,
+		"unable to resolve [name=sidecar] in /spec/template/spec/containers/[name=sidecar]: "+
+			"no element of /spec/template/spec/containers matches")
+}
+
+func TestKeySelectorLikeKeys(t *testing.T) {
+	output := filtertest.RunFilter(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeploy
+  annotations:
+    "[env=prod]": old
+`, Filter{
+		Patch: `
+- op: replace
+  path: /metadata/annotations/[env=prod]
+  value: new
+- op: add
+  path: /metadata/labels
+  value: {}
+- op: add
+  path: /metadata/labels/[tier=web]
+  value: "true"
+`,
+	})
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    '[env=prod]': new
+  labels:
+    '[tier=web]': "true"
+  name: myDeploy
+`, output)
+}
@@ -110,24 +110,28 @@ func applyReplacement(nodes []*yaml.RNode, value *yaml.RNode, targetSelectors []
 		if len(selector.FieldPaths) == 0 {
 			selector.FieldPaths = []string{types.DefaultReplacementFieldPath}
 		}
+		originPaths, err := originPathRegexes(selector)
+		if err != nil {
+			return nil, err
+		}
 		for _, possibleTarget := range nodes {
 			ids, err := utils.MakeResIds(possibleTarget)
 			if err != nil {
 				return nil, err
 			}
 
-			// filter targets by label and annotation selectors
-			selectByAnnoAndLabel, err := selectByAnnoAndLabel(possibleTarget, selector)
+			// filter targets by label, annotation, field and origin selectors
+			selectByConditions, err := selectByConditions(possibleTarget, ids, selector, originPaths)
 			if err != nil {
 				return nil, err
 			}
-			if !selectByAnnoAndLabel {
+			if !selectByConditions {
 				continue
 			}
 
 			// filter targets by matching resource IDs
 			for _, id := range ids {
-				if id.IsSelectedBy(selector.Select.ResId) && !containsRejectId(selector.Reject, ids) {
+				if id.IsSelectedBy(selector.Select.ResId) {
 					err := copyValueToTarget(possibleTarget, value, selector)
 					if err != nil {
 						return nil, err
@@ -140,22 +144,57 @@ func applyReplacement(nodes []*yaml.RNode, value *yaml.RNode, targetSelectors []
 	return nodes, nil
 }
 
-func selectByAnnoAndLabel(n *yaml.RNode, t *types.TargetSelector) (bool, error) {
-	if matchesSelect, err := matchesAnnoAndLabelSelector(n, t.Select); !matchesSelect || err != nil {
+// originPathRegexes returns the compiled origin path conditions of the
+// selectors of the target selector, and of the selectors they negate.
+func originPathRegexes(t *types.TargetSelector) (map[*types.Selector]*types.SelectorRegex, error) {
+	regexes := map[*types.Selector]*types.SelectorRegex{}
+	for _, s := range append([]*types.Selector{t.Select}, t.Reject...) {
+		for ; s != nil; s = s.Not {
+			if s.OriginPath == "" {
+				continue
+			}
+			sr, err := types.NewSelectorRegex(&types.Selector{OriginPath: s.OriginPath})
+			if err != nil {
+				return nil, err
+			}
+			regexes[s] = sr
+		}
+	}
+	return regexes, nil
+}
+
+func selectByConditions(n *yaml.RNode, ids []resid.ResId, t *types.TargetSelector,
+	originPaths map[*types.Selector]*types.SelectorRegex) (bool, error) {
+	if matchesSelect, err := matchesConditions(n, ids, t.Select, originPaths); !matchesSelect || err != nil {
 		return false, err
 	}
 	for _, reject := range t.Reject {
-		if reject.AnnotationSelector == "" && reject.LabelSelector == "" {
+		if reject.ResId.IsEmpty() && reject.AnnotationSelector == "" && reject.LabelSelector == "" &&
+			reject.FieldSelector == "" && reject.OriginPath == "" && reject.Not == nil {
 			continue
 		}
-		if m, err := matchesAnnoAndLabelSelector(n, reject); m || err != nil {
+		if m, err := matchesSelector(n, ids, reject, originPaths); m || err != nil {
 			return false, err
 		}
 	}
 	return true, nil
 }
 
-func matchesAnnoAndLabelSelector(n *yaml.RNode, selector *types.Selector) (bool, error) {
+// matchesSelector returns true if one of the ids matches the id of the
+// selector, and the node matches its other conditions.
+func matchesSelector(n *yaml.RNode, ids []resid.ResId, selector *types.Selector,
+	originPaths map[*types.Selector]*types.SelectorRegex) (bool, error) {
+	if !selector.ResId.IsEmpty() && !isSelectedBy(ids, selector.ResId) {
+		return false, nil
+	}
+	return matchesConditions(n, ids, selector, originPaths)
+}
+
+// matchesConditions returns true if the node matches the label, annotation,
+// field and origin path conditions of the selector, and not its negated
+// selector.  The origin path conditions are compiled in originPaths.
+func matchesConditions(n *yaml.RNode, ids []resid.ResId, selector *types.Selector,
+	originPaths map[*types.Selector]*types.SelectorRegex) (bool, error) {
 	r := resource.Resource{RNode: *n}
 	annoMatch, err := r.MatchesAnnotationSelector(selector.AnnotationSelector)
 	if err != nil {
@@ -165,18 +204,28 @@ func matchesAnnoAndLabelSelector(n *yaml.RNode, selector *types.Selector) (bool,
 	if err != nil {
 		return false, err
 	}
-	return annoMatch && labelMatch, nil
+	fieldMatch, err := r.MatchesFieldSelector(selector.FieldSelector)
+	if err != nil {
+		return false, err
+	}
+	originMatch := true
+	if sr, found := originPaths[selector]; found {
+		originMatch = sr.MatchOriginPath(r.GetOriginPath())
+	}
+	if !annoMatch || !labelMatch || !fieldMatch || !originMatch {
+		return false, nil
+	}
+	if selector.Not == nil {
+		return true, nil
+	}
+	excluded, err := matchesSelector(n, ids, selector.Not, originPaths)
+	return !excluded && err == nil, err
 }
 
-func containsRejectId(rejects []*types.Selector, ids []resid.ResId) bool {
-	for _, r := range rejects {
-		if r.ResId.IsEmpty() {
-			continue
-		}
-		for _, id := range ids {
-			if id.IsSelectedBy(r.ResId) {
-				return true
-			}
+func isSelectedBy(ids []resid.ResId, selector resid.ResId) bool {
+	for _, id := range ids {
+		if id.IsSelectedBy(selector) {
+			return true
 		}
 	}
 	return false
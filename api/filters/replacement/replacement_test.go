@@ -2830,6 +2830,91 @@ spec:
 `,
 			expectedErr: "unable to find or create field \"spec.tls.5.hosts.5\" in replacement target: index 5 specified but only 0 elements found",
 		},
+		"reject with id and conditions": {
+			input: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: cache
+spec:
+  type: ClusterIP
+`,
+			replacements: `replacements:
+- source:
+    kind: Service
+    name: db
+    fieldPath: spec.type
+  targets:
+  - select:
+      kind: Service
+    reject:
+    - name: web
+      fieldSelector: spec.type=LoadBalancer
+    - name: db
+      not:
+        fieldSelector: spec.type=ClusterIP
+    - name: cache
+      fieldSelector: spec.type=LoadBalancer
+    fieldPaths:
+    - metadata.annotations.peer-type
+    options:
+      create: true
+`,
+			expected: `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  annotations:
+    peer-type: ClusterIP
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  annotations:
+    peer-type: ClusterIP
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: cache
+  annotations:
+    peer-type: ClusterIP
+spec:
+  type: ClusterIP
+`,
+		},
 	}
 
 	for tn, tc := range testCases {
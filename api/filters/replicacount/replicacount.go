@@ -5,10 +5,12 @@ package replicacount
 
 import (
 	"strconv"
+	"strings"
 
 	"sigs.k8s.io/kustomize/api/filters/fieldspec"
 	"sigs.k8s.io/kustomize/api/filters/filtersutil"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
@@ -34,8 +36,13 @@ func (rc Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 }
 
 func (rc Filter) run(node *yaml.RNode) (*yaml.RNode, error) {
+	fs := rc.FieldSpec
+	if rc.Replica.Adjust != "" {
+		// there is no count to adjust in a missing field
+		fs.CreateIfNotPresent = false
+	}
 	err := node.PipeE(fieldspec.Filter{
-		FieldSpec:  rc.FieldSpec,
+		FieldSpec:  fs,
 		SetValue:   rc.set,
 		CreateKind: yaml.ScalarNode, // replicas is a ScalarNode
 		CreateTag:  yaml.NodeTagInt,
@@ -44,5 +51,69 @@ func (rc Filter) run(node *yaml.RNode) (*yaml.RNode, error) {
 }
 
 func (rc Filter) set(node *yaml.RNode) error {
-	return rc.trackableSetter.SetEntry("", strconv.FormatInt(rc.Replica.Count, 10), yaml.NodeTagInt)(node)
+	count := rc.Replica.Count
+	if rc.Replica.Adjust != "" {
+		current, err := strconv.ParseInt(node.YNode().Value, 10, 64)
+		if err != nil {
+			return errors.WrapPrefixf(err, "unable to adjust the replica count %q", node.YNode().Value)
+		}
+		count, err = Adjust(current, rc.Replica.Adjust)
+		if err != nil {
+			return err
+		}
+	}
+	return rc.trackableSetter.SetEntry("", strconv.FormatInt(count, 10), yaml.NodeTagInt)(node)
+}
+
+const percent = 100
+
+// Adjust returns count changed by adjustment, which is one of "+N", "-N",
+// "*N" or "N%", see types.Replica.
+func Adjust(count int64, adjustment string) (int64, error) {
+	op, n, err := parseAdjustment(adjustment)
+	if err != nil {
+		return 0, err
+	}
+	var result int64
+	switch op {
+	case '*':
+		result = count * n
+	case '%':
+		// rounded up
+		result = (count*n + percent - 1) / percent
+	default:
+		result = count + n
+	}
+	if result < 0 {
+		return 0, errors.Errorf("adjusting the replica count %d by %s gives a negative count", count, adjustment)
+	}
+	return result, nil
+}
+
+// ValidateAdjustment returns an error if adjustment is not one of
+// "+N", "-N", "*N" or "N%".
+func ValidateAdjustment(adjustment string) error {
+	_, _, err := parseAdjustment(adjustment)
+	return err
+}
+
+// parseAdjustment returns the operation of adjustment, one of '+', '*'
+// or '%', and its operand, negative to remove replicas.
+func parseAdjustment(adjustment string) (byte, int64, error) {
+	var op byte
+	var operand string
+	switch {
+	case strings.HasPrefix(adjustment, "+"), strings.HasPrefix(adjustment, "-"):
+		op, operand = '+', adjustment
+	case strings.HasPrefix(adjustment, "*"):
+		op, operand = '*', adjustment[1:]
+	case strings.HasSuffix(adjustment, "%"):
+		op, operand = '%', strings.TrimSuffix(adjustment, "%")
+	}
+	n, err := strconv.ParseInt(operand, 10, 64)
+	if op == 0 || err != nil || (op != '+' && n < 0) {
+		return 0, 0, errors.Errorf(
+			"invalid replica count adjustment %q, must be one of +N, -N, *N or N%%", adjustment)
+	}
+	return op, n, nil
 }
@@ -4,6 +4,7 @@
 package replicacount
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -200,6 +201,52 @@ spec:
 				},
 			},
 		},
+		"adjust field": {
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 3
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 5
+`,
+			filter: Filter{
+				Replica: types.Replica{
+					Name:   "dep",
+					Adjust: "150%",
+				},
+				FieldSpec: types.FieldSpec{Path: "spec/replicas", CreateIfNotPresent: true},
+			},
+		},
+		"adjust missing field": {
+			input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+`,
+			expected: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+`,
+			filter: Filter{
+				Replica: types.Replica{
+					Name:   "dep",
+					Adjust: "+1",
+				},
+				FieldSpec: types.FieldSpec{Path: "spec/replicas", CreateIfNotPresent: true},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -216,3 +263,23 @@ spec:
 		})
 	}
 }
+
+func TestAdjust(t *testing.T) {
+	for adjustment, expected := range map[string]int64{
+		"+2":   6,
+		"-1":   3,
+		"*3":   12,
+		"50%":  2,
+		"110%": 5,
+	} {
+		count, err := Adjust(4, adjustment)
+		if assert.NoError(t, err, adjustment) {
+			assert.Equal(t, expected, count, adjustment)
+		}
+	}
+	for _, adjustment := range []string{"", "2", "*-1", "x%", "/2"} {
+		_, err := Adjust(4, adjustment)
+		assert.EqualError(t, err, fmt.Sprintf(
+			"invalid replica count adjustment %q, must be one of +N, -N, *N or N%%", adjustment))
+	}
+}
@@ -3,6 +3,7 @@ module sigs.k8s.io/kustomize/api
 go 1.22.7
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/blang/semver/v4 v4.0.0
 	github.com/go-errors/errors v1.4.2
 	github.com/google/shlex v0.0.0-20191202100458-e7afc7fbc510
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 
+	"sigs.k8s.io/kustomize/kyaml/utils"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -75,6 +76,70 @@ func (h *Hasher) Hash(node *yaml.RNode) (r string, err error) {
 	return encode(hex256(encoded))
 }
 
+// HashFields returns a hash of the kind and name of node, and of the
+// fields at paths.  Without paths, all the fields but apiVersion, kind,
+// metadata and status are hashed.
+func (h *Hasher) HashFields(node *yaml.RNode, paths []string) (string, error) {
+	encoded, err := encodeFields(node, paths)
+	if err != nil {
+		return "", err
+	}
+	return encode(hex256(encoded))
+}
+
+// encodeFields encodes the kind, name and fields at paths of node.
+// Fields that are not found are left out.
+func encodeFields(node *yaml.RNode, paths []string) (string, error) {
+	if len(paths) == 0 {
+		names, err := node.Fields()
+		if err != nil {
+			return "", err
+		}
+		for _, name := range names {
+			switch name {
+			case yaml.APIVersionField, yaml.KindField, yaml.MetadataField, "status":
+			default:
+				paths = append(paths, name)
+			}
+		}
+	}
+	fields := make(map[string]interface{})
+	for _, p := range paths {
+		vn, err := node.Pipe(yaml.Lookup(utils.SmarterPathSplitter(p, "/")...))
+		if err != nil {
+			return "", err
+		}
+		if vn == nil {
+			continue
+		}
+		if vn.YNode().Kind == yaml.ScalarNode {
+			fields[p] = vn.YNode().Value
+			continue
+		}
+		vs, err := vn.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		var v interface{}
+		if err = json.Unmarshal(vs, &v); err != nil {
+			return "", err
+		}
+		fields[p] = v
+	}
+	m := map[string]interface{}{
+		"kind":   node.GetKind(),
+		"name":   node.GetName(),
+		"fields": fields,
+	}
+
+	// json.Marshal sorts the keys in a stable order in the encoding
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func getNodeValues(
 	node *yaml.RNode, paths []string) (map[string]interface{}, error) {
 	values := make(map[string]interface{})
@@ -209,6 +209,70 @@ spec:
 	}
 }
 
+func TestEncodeFields(t *testing.T) {
+	const job = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  labels:
+    app: db
+spec:
+  backoffLimit: 2
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate:v1
+status:
+  active: 1
+`
+	cases := map[string]struct {
+		paths    []string
+		expected string
+	}{
+		"all fields": {
+			expected: `{"fields":{"spec":{"backoffLimit":2,"template":{"spec":{"containers":[{"image":"migrate:v1","name":"migrate"}]}}}},"kind":"Job","name":"migrate"}`,
+		},
+		"selected fields": {
+			paths:    []string{"spec/template/spec/containers/[name=migrate]/image", "metadata/labels", "spec/missing"},
+			expected: `{"fields":{"metadata/labels":{"app":"db"},"spec/template/spec/containers/[name=migrate]/image":"migrate:v1"},"kind":"Job","name":"migrate"}`,
+		},
+	}
+	node, err := yaml.Parse(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			s, err := encodeFields(node, c.paths)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != c.expected {
+				t.Errorf("expected encoding\n%s\nbut got\n%s", c.expected, s)
+			}
+		})
+	}
+
+	// metadata other than the name is not hashed by default
+	h := &Hasher{}
+	hash, err := h.HashFields(node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = node.PipeE(yaml.SetLabel("app", "other")); err != nil {
+		t.Fatal(err)
+	}
+	relabeled, err := h.HashFields(node, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != relabeled {
+		t.Errorf("expected the same hash after relabeling, got %q and %q", hash, relabeled)
+	}
+}
+
 func TestEncodeConfigMap(t *testing.T) {
 	cases := []struct {
 		desc   string
@@ -52,5 +52,12 @@ type KustHasher interface {
 	Hash(*yaml.RNode) (string, error)
 }
 
+// KustFieldHasher is a KustHasher that can hash the kind, name
+// and selected fields of the argument.
+type KustFieldHasher interface {
+	KustHasher
+	HashFields(node *yaml.RNode, paths []string) (string, error)
+}
+
 // See core.v1.SecretTypeOpaque
 const SecretTypeOpaque = "Opaque"
@@ -0,0 +1,121 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package accumulator
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// UnfixedHashedNames returns a warning for each field that still holds
+// the name a generated object had before its hash suffix was added.
+// Such a field is likely a reference kustomize doesn't know about, which
+// must be declared to follow the hashed name, e.g. in the openapi schema
+// or the name references annotation of a CRD.
+func (ra *ResAccumulator) UnfixedHashedNames() []types.Diagnostic {
+	var diagnostics []types.Diagnostic
+	for _, w := range unfixedHashedNames(ra.resMap) {
+		diagnostics = append(diagnostics, types.Diagnostic{
+			Severity: types.DiagnosticWarning,
+			Message:  w,
+		})
+	}
+	return diagnostics
+}
+
+// unfixedHashedNames returns a message for each reference-like field of
+// the resources of m that holds the name of a generated object before its
+// hash suffix was added.
+func unfixedHashedNames(m resmap.ResMap) []string {
+	hashed := make(map[string][]*resource.Resource)
+	for _, r := range m.Resources() {
+		if !r.NeedHashSuffix() {
+			continue
+		}
+		names := map[string]bool{r.OrgId().Name: true}
+		for _, id := range r.PrevIds() {
+			names[id.Name] = true
+		}
+		for name := range names {
+			if name != r.GetName() {
+				hashed[name] = append(hashed[name], r)
+			}
+		}
+	}
+	if len(hashed) == 0 {
+		return nil
+	}
+	var warnings []string
+	for _, referrer := range m.Resources() {
+		walkReferenceLikeFields(referrer.YNode(), nil, "", func(path []string, value string) {
+			for _, target := range hashed[value] {
+				if target == referrer {
+					continue
+				}
+				warnings = append(warnings, fmt.Sprintf(
+					"%s has %q at %s, the name of %s before its hash suffix "+
+						"was added; if this field refers to it, declare it as a name reference",
+					referrer.CurId(), value, strings.Join(path, "/"), target.CurId()))
+			}
+		})
+	}
+	return warnings
+}
+
+// fieldsWithoutReferences are the fields whose subtrees are not searched
+// for references, since they map label and annotation keys to values.
+var fieldsWithoutReferences = map[string]bool{ //nolint:gochecknoglobals
+	"labels":       true,
+	"annotations":  true,
+	"selector":     true,
+	"matchLabels":  true,
+	"nodeSelector": true,
+}
+
+// walkReferenceLikeFields calls fn for each scalar field below n that looks
+// like a reference by name, i.e. a field named like `secretName`, or a
+// `name` field of an object named like `configMapRef`.
+func walkReferenceLikeFields(
+	n *yaml.Node, path []string, parent string, fn func(path []string, value string)) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			if fieldsWithoutReferences[key] {
+				continue
+			}
+			fieldPath := append(path[:len(path):len(path)], key)
+			if value.Kind == yaml.ScalarNode {
+				if isReferenceLikeField(key, parent) {
+					fn(fieldPath, value.Value)
+				}
+				continue
+			}
+			walkReferenceLikeFields(value, fieldPath, key, fn)
+		}
+	case yaml.SequenceNode:
+		// the elements of a list are named by the field holding the list
+		for _, c := range n.Content {
+			walkReferenceLikeFields(c, path, parent, fn)
+		}
+	}
+}
+
+func isReferenceLikeField(key, parent string) bool {
+	if strings.HasSuffix(key, "Name") {
+		return true
+	}
+	if key != "name" {
+		return false
+	}
+	parent = strings.ToLower(parent)
+	return strings.Contains(parent, "ref") ||
+		strings.Contains(parent, "configmap") ||
+		strings.Contains(parent, "secret")
+}
@@ -10,9 +10,11 @@ import (
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
 	"sigs.k8s.io/kustomize/kyaml/resid"
 	"sigs.k8s.io/yaml"
 )
@@ -53,6 +55,23 @@ func LoadConfigFromCRDs(
 	return tc, nil
 }
 
+// LoadSchemaFromCRDs adds the schemas of the custom resources declared by
+// the CustomResourceDefinitions in m to the schema of the build, so that
+// strategic merge patches merge their lists by the merge keys declared
+// with x-kubernetes-list-type and x-kubernetes-list-map-keys.  They're
+// dropped when the build releases its schema.
+func LoadSchemaFromCRDs(m resmap.ResMap, schema *openapi.BuildSchema) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		if err := schema.AddCRDDefinitions(&r.RNode); err != nil {
+			return errors.WrapPrefixf(err, "loading the schema of %s", r.CurId())
+		}
+	}
+	return nil
+}
+
 func makeNameToApiMap(content []byte) (result nameToApiMap, err error) {
 	if content[0] == '{' {
 		err = json.Unmarshal(content, &result)
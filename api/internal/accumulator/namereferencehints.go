@@ -0,0 +1,239 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package accumulator
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// "x-kubernetes-group-version-kind": [{group: <group>, version: <version>, kind: <kind>}]
+	xGroupVersionKind = "x-kubernetes-group-version-kind"
+
+	definitionsRefPrefix = "#/definitions/"
+)
+
+// LoadConfigFromOpenAPI reads the name references declared by the OpenAPI
+// schema at path into a TransformerConfig.  As in a CRD, a property of a
+// schema is a reference to an object of the kind named by its
+// "x-kubernetes-object-ref-kind" extension.  References are collected for
+// each definition having an "x-kubernetes-group-version-kind" extension.
+func LoadConfigFromOpenAPI(
+	ldr ifc.Loader, path string) (*builtinconfig.TransformerConfig, error) {
+	tc := builtinconfig.MakeEmptyConfig()
+	if path == "" {
+		return tc, nil
+	}
+	content, err := ldr.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "unable to parse open API schema from '%s'", path)
+	}
+	var swagger spec.Swagger
+	if err = swagger.UnmarshalJSON(b); err != nil {
+		return nil, errors.WrapPrefixf(err, "unable to parse open API schema from '%s'", path)
+	}
+	f := &openAPIRefFinder{
+		definitions: swagger.Definitions,
+		refs:        make(map[string][]objectRef),
+	}
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		gvks := definitionGvks(swagger.Definitions[name])
+		if len(gvks) == 0 {
+			continue
+		}
+		for _, ref := range f.definitionRefs(name) {
+			for _, gvk := range gvks {
+				err = tc.AddNamereferenceFieldSpec(builtinconfig.NameBackReferences{
+					Gvk: ref.gvk,
+					Referrers: []types.FieldSpec{
+						makeFs(gvk, ref.path)},
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return tc, nil
+}
+
+// definitionGvks returns the GVKs of the x-kubernetes-group-version-kind
+// extension of s.
+func definitionGvks(s spec.Schema) []resid.Gvk {
+	list, ok := s.Extensions[xGroupVersionKind].([]interface{})
+	if !ok {
+		return nil
+	}
+	var gvks []resid.Gvk
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := m["group"].(string)
+		version, _ := m["version"].(string)
+		kind, _ := m["kind"].(string)
+		if kind != "" {
+			gvks = append(gvks, resid.Gvk{Group: group, Version: version, Kind: kind})
+		}
+	}
+	return gvks
+}
+
+// objectRef is a reference found in a schema: the path to the
+// field holding the name, and the kind of the object named.
+type objectRef struct {
+	path []string
+	gvk  resid.Gvk
+}
+
+// openAPIRefFinder finds the references in the definitions of a schema,
+// following $refs between definitions.
+type openAPIRefFinder struct {
+	definitions spec.Definitions
+	// refs caches the references found in each definition.
+	refs map[string][]objectRef
+}
+
+func (f *openAPIRefFinder) definitionRefs(name string) []objectRef {
+	if refs, found := f.refs[name]; found {
+		return refs
+	}
+	def, found := f.definitions[name]
+	if !found {
+		return nil
+	}
+	// break cycles between definitions
+	f.refs[name] = nil
+	refs := f.schemaRefs(def)
+	f.refs[name] = refs
+	return refs
+}
+
+func (f *openAPIRefFinder) schemaRefs(s spec.Schema) []objectRef {
+	var refs []objectRef
+	if ref := s.Ref.String(); strings.HasPrefix(ref, definitionsRefPrefix) {
+		refs = append(refs, f.definitionRefs(strings.TrimPrefix(ref, definitionsRefPrefix))...)
+	}
+	for _, sub := range s.AllOf {
+		refs = append(refs, f.schemaRefs(sub)...)
+	}
+	// the elements of a list are at the path of the list
+	if s.Items != nil {
+		if s.Items.Schema != nil {
+			refs = append(refs, f.schemaRefs(*s.Items.Schema)...)
+		}
+		for _, item := range s.Items.Schemas {
+			refs = append(refs, f.schemaRefs(item)...)
+		}
+	}
+	propNames := make([]string, 0, len(s.Properties))
+	for propName := range s.Properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+	for _, propName := range propNames {
+		property := s.Properties[propName]
+		if kind, ok := property.Extensions.GetString(xKind); ok {
+			version, _ := property.Extensions.GetString(xVersion)
+			nameKey, ok := property.Extensions.GetString(xNameKey)
+			if !ok {
+				nameKey = "name"
+			}
+			refs = append(refs, objectRef{
+				path: []string{propName, nameKey},
+				gvk:  resid.Gvk{Kind: kind, Version: version},
+			})
+		}
+		for _, ref := range f.schemaRefs(property) {
+			refs = append(refs, objectRef{
+				path: append([]string{propName}, ref.path...),
+				gvk:  ref.gvk,
+			})
+		}
+	}
+	return refs
+}
+
+// nameReferenceHint is an entry of the NameReferencesAnnotation.
+type nameReferenceHint struct {
+	// Group, Version and Kind of the object referred to.
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind"`
+
+	// Path of the field of the custom resource holding the name.
+	Path string `json:"path"`
+}
+
+// loadConfigFromCRDAnnotations returns the name references declared by the
+// NameReferencesAnnotation of the CustomResourceDefinitions in m, e.g.
+//
+//	metadata:
+//	  annotations:
+//	    kustomize.config.k8s.io/name-references: |
+//	      - kind: ConfigMap
+//	        path: spec/configMapRef/name
+//	      - kind: Secret
+//	        version: v1
+//	        path: spec/auth/secretName
+func loadConfigFromCRDAnnotations(m resmap.ResMap) (*builtinconfig.TransformerConfig, error) {
+	tc := builtinconfig.MakeEmptyConfig()
+	for _, r := range m.Resources() {
+		value, found := r.GetAnnotations()[konfig.NameReferencesAnnotation]
+		if !found || r.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		var hints []nameReferenceHint
+		if err := yaml.UnmarshalStrict([]byte(value), &hints); err != nil {
+			return nil, errors.WrapPrefixf(err, "invalid %s annotation on %s",
+				konfig.NameReferencesAnnotation, r.CurId())
+		}
+		group, _ := r.GetString("spec.group")
+		kind, _ := r.GetString("spec.names.kind")
+		if kind == "" {
+			return nil, errors.Errorf(
+				"%s annotation on %s requires spec.names.kind",
+				konfig.NameReferencesAnnotation, r.CurId())
+		}
+		for _, h := range hints {
+			if h.Kind == "" || h.Path == "" {
+				return nil, errors.Errorf(
+					"invalid %s annotation on %s: each entry requires a kind and a path",
+					konfig.NameReferencesAnnotation, r.CurId())
+			}
+			err := tc.AddNamereferenceFieldSpec(builtinconfig.NameBackReferences{
+				Gvk: resid.Gvk{Group: h.Group, Version: h.Version, Kind: h.Kind},
+				Referrers: []types.FieldSpec{{
+					Gvk:  resid.Gvk{Group: group, Kind: kind},
+					Path: h.Path,
+				}},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tc, nil
+}
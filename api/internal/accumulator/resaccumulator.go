@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/resid"
 )
 
@@ -161,6 +162,13 @@ func (ra *ResAccumulator) ResolveVars() error {
 }
 
 func (ra *ResAccumulator) FixBackReferences() (err error) {
+	crdTc, err := loadConfigFromCRDAnnotations(ra.resMap)
+	if err != nil {
+		return err
+	}
+	if err = ra.MergeConfig(crdTc); err != nil {
+		return errors.WrapPrefixf(err, "merging name references of CRDs")
+	}
 	if ra.tConfig.NameReference == nil {
 		return nil
 	}
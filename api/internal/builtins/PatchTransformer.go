@@ -133,7 +133,8 @@ func (p *PatchTransformerPlugin) transformJson6902(m resmap.ResMap) error {
 		res.StorePreviousId()
 		internalAnnotations := kioutil.GetInternalAnnotations(&res.RNode)
 		err = res.ApplyFilter(patchjson6902.Filter{
-			Patch: p.patchText,
+			Patch:            p.patchText,
+			SkipOnFailedTest: p.Options["skipOnFailedTest"],
 		})
 		if err != nil {
 			return err
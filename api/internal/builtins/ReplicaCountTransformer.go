@@ -5,11 +5,15 @@ package builtins
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"sigs.k8s.io/kustomize/api/filters/replicacount"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/resid"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/yaml"
 )
 
@@ -28,10 +32,24 @@ func (p *ReplicaCountTransformerPlugin) Config(
 }
 
 func (p *ReplicaCountTransformerPlugin) Transform(m resmap.ResMap) error {
+	if p.Replica.Adjust != "" {
+		if p.Replica.Count != 0 {
+			return fmt.Errorf("replica count %d and adjustment %s are mutually exclusive",
+				p.Replica.Count, p.Replica.Adjust)
+		}
+		if err := replicacount.ValidateAdjustment(p.Replica.Adjust); err != nil {
+			return err
+		}
+	}
 	found := false
-	for _, fs := range p.FieldSpecs {
-		matcher := p.createMatcher(fs)
-		resList := m.GetMatchingResourcesByAnyId(matcher)
+	var scaled []scaledResource
+	var autoscalers []*resource.Resource
+	targets := p.targets()
+	for _, target := range targets {
+		resList, err := p.selectResources(m, target.fieldSpec)
+		if err != nil {
+			return err
+		}
 		if len(resList) > 0 {
 			found = true
 			for _, r := range resList {
@@ -39,32 +57,229 @@ func (p *ReplicaCountTransformerPlugin) Transform(m resmap.ResMap) error {
 				// that we'll live with until resolution of
 				// https://github.com/nholuongut/kustomize/issues/2506
 				err := r.ApplyFilter(replicacount.Filter{
-					Replica:   p.Replica,
-					FieldSpec: fs,
+					Replica:   target.replica,
+					FieldSpec: target.fieldSpec,
 				})
 				if err != nil {
 					return err
 				}
+				if target.fieldSpec.Kind == autoscalerKind {
+					autoscalers = append(autoscalers, r)
+				} else {
+					scaled = append(scaled, scaledResource{r, target.fieldSpec.Path})
+				}
 			}
 		}
 	}
 
 	if !found {
-		gvks := make([]string, len(p.FieldSpecs))
-		for i, replicaSpec := range p.FieldSpecs {
-			gvks[i] = replicaSpec.Gvk.String()
+		gvks := make([]string, len(targets))
+		for i, target := range targets {
+			gvks[i] = target.fieldSpec.Gvk.String()
+		}
+		if p.Replica.Selector != nil {
+			return fmt.Errorf("resources selected by %s do not match a config with the following GVK %v",
+				p.Replica.Selector, gvks)
 		}
 		return fmt.Errorf("resource with name %s does not match a config with the following GVK %v",
 			p.Replica.Name, gvks)
 	}
 
+	if err := validateAutoscalers(autoscalers); err != nil {
+		return err
+	}
+	return validateDisruptionBudgets(m, scaled)
+}
+
+const autoscalerKind = "HorizontalPodAutoscaler"
+
+// replicaTarget is a field holding a replica count, and the replica to set it with.
+type replicaTarget struct {
+	replica   types.Replica
+	fieldSpec types.FieldSpec
+}
+
+// targets returns the fields to set, i.e. the FieldPaths of the replica or
+// else the configured fieldSpecs, and the fields of HorizontalPodAutoscalers.
+func (p *ReplicaCountTransformerPlugin) targets() []replicaTarget {
+	var result []replicaTarget
+	if p.Replica.SetsCount() {
+		fieldSpecs := p.FieldSpecs
+		if len(p.Replica.FieldPaths) > 0 {
+			fieldSpecs = make([]types.FieldSpec, len(p.Replica.FieldPaths))
+			for i, path := range p.Replica.FieldPaths {
+				fieldSpecs[i] = types.FieldSpec{Path: path}
+			}
+		}
+		for _, fs := range fieldSpecs {
+			result = append(result, replicaTarget{replica: p.Replica, fieldSpec: fs})
+		}
+	}
+	for _, field := range []struct {
+		path  string
+		count *int64
+	}{
+		{"spec/minReplicas", p.Replica.MinCount},
+		{"spec/maxReplicas", p.Replica.MaxCount},
+	} {
+		replica := p.Replica
+		if replica.Adjust == "" {
+			if field.count == nil {
+				continue
+			}
+			replica.Count = *field.count
+		}
+		result = append(result, replicaTarget{
+			replica:   replica,
+			fieldSpec: types.FieldSpec{Gvk: resid.Gvk{Kind: autoscalerKind}, Path: field.path},
+		})
+	}
+	return result
+}
+
+// scaledResource is a resource, and the path of its replica count field.
+type scaledResource struct {
+	resource *resource.Resource
+	path     string
+}
+
+// validateAutoscalers returns an error if the minReplicas of a
+// HorizontalPodAutoscaler is more than its maxReplicas.
+func validateAutoscalers(autoscalers []*resource.Resource) error {
+	for _, r := range autoscalers {
+		minReplicas, okMin := intField(r, "spec/minReplicas")
+		maxReplicas, okMax := intField(r, "spec/maxReplicas")
+		if okMin && okMax && minReplicas > maxReplicas {
+			return fmt.Errorf("%s has minReplicas %d, more than its maxReplicas %d",
+				r.CurId(), minReplicas, maxReplicas)
+		}
+	}
+	return nil
+}
+
+// validateDisruptionBudgets returns an error if a PodDisruptionBudget
+// requires more pods to be available than the replicas of a scaled
+// resource whose pods it selects.
+func validateDisruptionBudgets(m resmap.ResMap, scaled []scaledResource) error {
+	var budgets []*resource.Resource
+	for _, r := range m.Resources() {
+		if r.GetKind() == "PodDisruptionBudget" {
+			budgets = append(budgets, r)
+		}
+	}
+	for _, s := range scaled {
+		replicas, ok := intField(s.resource, s.path)
+		if !ok || replicas == 0 {
+			// no pods to keep available
+			continue
+		}
+		template, err := s.resource.Pipe(kyaml.Lookup("spec", "template"))
+		if err != nil || template == nil {
+			continue
+		}
+		for _, budget := range budgets {
+			// a percentage always fits
+			minAvailable, ok := intField(budget, "spec/minAvailable")
+			if !ok || minAvailable <= replicas ||
+				budget.GetNamespace() != s.resource.GetNamespace() {
+				continue
+			}
+			selector, ok, err := podSelector(budget)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			matches, err := template.MatchesLabelSelector(selector)
+			if err != nil {
+				return fmt.Errorf("invalid selector of %s: %w", budget.CurId(), err)
+			}
+			if matches {
+				return fmt.Errorf("%s requires %d available pods, more than the %d replicas of %s",
+					budget.CurId(), minAvailable, replicas, s.resource.CurId())
+			}
+		}
+	}
 	return nil
 }
 
-// Match Replica.Name and FieldSpec
+// intField returns the value of the integer field at path.
+func intField(r *resource.Resource, path string) (int64, bool) {
+	value, err := r.GetFieldValue(strings.ReplaceAll(path, "/", "."))
+	if err != nil {
+		return 0, false
+	}
+	n, ok := value.(int)
+	return int64(n), ok
+}
+
+// podSelector returns the spec.selector of a PodDisruptionBudget as a label
+// selector string, or false if it has none and so selects no pod.
+func podSelector(budget *resource.Resource) (string, bool, error) {
+	node, err := budget.Pipe(kyaml.Lookup("spec", "selector"))
+	if err != nil || node == nil {
+		return "", false, nil
+	}
+	var selector struct {
+		MatchLabels      map[string]string `yaml:"matchLabels"`
+		MatchExpressions []struct {
+			Key      string   `yaml:"key"`
+			Operator string   `yaml:"operator"`
+			Values   []string `yaml:"values"`
+		} `yaml:"matchExpressions"`
+	}
+	if err = node.YNode().Decode(&selector); err != nil {
+		return "", false, fmt.Errorf("invalid selector of %s: %w", budget.CurId(), err)
+	}
+	var requirements []string
+	for key, value := range selector.MatchLabels {
+		requirements = append(requirements, key+"="+value)
+	}
+	sort.Strings(requirements)
+	for _, e := range selector.MatchExpressions {
+		values := strings.Join(e.Values, ",")
+		switch e.Operator {
+		case "In":
+			requirements = append(requirements, fmt.Sprintf("%s in (%s)", e.Key, values))
+		case "NotIn":
+			requirements = append(requirements, fmt.Sprintf("%s notin (%s)", e.Key, values))
+		case "Exists":
+			requirements = append(requirements, e.Key)
+		case "DoesNotExist":
+			requirements = append(requirements, "!"+e.Key)
+		default:
+			return "", false, fmt.Errorf("invalid selector of %s: unknown operator %q",
+				budget.CurId(), e.Operator)
+		}
+	}
+	return strings.Join(requirements, ","), true, nil
+}
+
+// Select the resources matching Replica.Selector, Replica.Name and FieldSpec
+func (p *ReplicaCountTransformerPlugin) selectResources(m resmap.ResMap, fs types.FieldSpec) ([]*resource.Resource, error) {
+	if p.Replica.Selector == nil {
+		return m.GetMatchingResourcesByAnyId(p.createMatcher(fs)), nil
+	}
+	selected, err := m.Select(*p.Replica.Selector)
+	if err != nil {
+		return nil, err
+	}
+	var result []*resource.Resource
+	for _, r := range selected {
+		matcher := p.createMatcher(fs)
+		if matcher(r.OrgId()) || matcher(r.CurId()) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// Match Replica.Name, if set, and FieldSpec
 func (p *ReplicaCountTransformerPlugin) createMatcher(fs types.FieldSpec) resmap.IdMatcher {
 	return func(r resid.ResId) bool {
-		return r.Name == p.Replica.Name && r.Gvk.IsSelected(&fs.Gvk)
+		return (r.Name == p.Replica.Name || p.Replica.Name == "" && p.Replica.Selector != nil) &&
+			r.Gvk.IsSelected(&fs.Gvk)
 	}
 }
 
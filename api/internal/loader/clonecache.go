@@ -0,0 +1,74 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// CloneCache shares the clones of remote repositories between the
+// loaders using it, e.g. of the targets of a multi-target build.
+// The loaders don't remove the clones on Cleanup; the cache does.
+// It's safe for concurrent use.
+type CloneCache struct {
+	mu     sync.Mutex
+	clones map[string]*cachedClone
+}
+
+// cachedClone is a clone of a repository, made once.
+type cachedClone struct {
+	once    sync.Once
+	dir     filesys.ConfirmedDir
+	err     error
+	cleaner func() error
+}
+
+// NewCloneCache returns an empty CloneCache.
+func NewCloneCache() *CloneCache {
+	return &CloneCache{clones: map[string]*cachedClone{}}
+}
+
+// clone sets the directory of repoSpec to the clone of its
+// repository at its ref, cloning it with cloner unless cached.
+// A failed clone is cached too.
+func (c *CloneCache) clone(
+	cloner git.Cloner, fSys filesys.FileSystem, repoSpec *git.RepoSpec) error {
+	key := fmt.Sprintf("%s?ref=%s&submodules=%t",
+		repoSpec.CloneSpec(), repoSpec.Ref, repoSpec.Submodules)
+	c.mu.Lock()
+	cc, ok := c.clones[key]
+	if !ok {
+		cc = &cachedClone{}
+		c.clones[key] = cc
+	}
+	c.mu.Unlock()
+	cc.once.Do(func() {
+		rs := *repoSpec
+		cc.err = cloner(&rs)
+		cc.dir = rs.Dir
+		cc.cleaner = rs.Cleaner(fSys)
+	})
+	repoSpec.Dir = cc.dir
+	return cc.err
+}
+
+// Cleanup removes the clones, returning the first error.
+func (c *CloneCache) Cleanup() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var firstErr error
+	for key, cc := range c.clones {
+		if cc.cleaner != nil && cc.dir != "" {
+			if err := cc.cleaner(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		delete(c.clones, key)
+	}
+	return firstErr
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestCloneCache(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.MkdirAll("/clone/foo/base"))
+	require.NoError(t, fSys.MkdirAll("/clone/foo/overlay"))
+	require.NoError(t, fSys.MkdirAll("/other"))
+	clones := 0
+	cloner := func(rs *git.RepoSpec) error {
+		clones++
+		if rs.Ref == "other" {
+			rs.Dir = "/other"
+		} else {
+			rs.Dir = "/clone"
+		}
+		return nil
+	}
+	cache := NewCloneCache()
+	newLoader := func(url string) *FileLoader {
+		t.Helper()
+		repoSpec, err := git.NewRepoSpecFromURL(url)
+		require.NoError(t, err)
+		ldr, err := newLoaderAtGitClone(repoSpec, fSys, nil, cloner, cache)
+		require.NoError(t, err)
+		return ldr.(*FileLoader)
+	}
+
+	base := newLoader("github.com/org/repo/foo/base?ref=v1")
+	assert.Equal(t, "/clone/foo/base", base.Root())
+	overlay := newLoader("github.com/org/repo/foo/overlay?ref=v1")
+	assert.Equal(t, "/clone/foo/overlay", overlay.Root())
+	assert.Equal(t, 1, clones)
+	other := newLoader("github.com/org/repo?ref=other")
+	assert.Equal(t, "/other", other.Root())
+	assert.Equal(t, 2, clones)
+
+	// The loaders made by the loaders share the cache too.
+	sub, err := base.New("github.com/org/repo/foo/overlay?ref=v1")
+	require.NoError(t, err)
+	assert.Equal(t, "/clone/foo/overlay", sub.Root())
+	assert.Equal(t, 2, clones)
+
+	require.NoError(t, base.Cleanup())
+	assert.True(t, fSys.Exists("/clone"))
+	require.NoError(t, cache.Cleanup())
+	assert.False(t, fSys.Exists("/clone"))
+	assert.False(t, fSys.Exists("/other"))
+}
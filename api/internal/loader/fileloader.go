@@ -102,6 +102,10 @@ type FileLoader struct {
 	// Used to clone repositories.
 	cloner git.Cloner
 
+	// If this is non-nil, the clones of repositories
+	// are shared through it.
+	clones *CloneCache
+
 	// Used to clean up, as needed.
 	cleaner func() error
 }
@@ -129,20 +133,21 @@ func NewLoaderOrDie(
 		log.Fatalf("unable to make loader at '%s'; %v", path, err)
 	}
 	return newLoaderAtConfirmedDir(
-		lr, root, fSys, nil, git.ClonerUsingGitExec)
+		lr, root, fSys, nil, git.ClonerUsingGitExec, nil)
 }
 
 // newLoaderAtConfirmedDir returns a new FileLoader with given root.
 func newLoaderAtConfirmedDir(
 	lr LoadRestrictorFunc,
 	root filesys.ConfirmedDir, fSys filesys.FileSystem,
-	referrer *FileLoader, cloner git.Cloner) *FileLoader {
+	referrer *FileLoader, cloner git.Cloner, clones *CloneCache) *FileLoader {
 	return &FileLoader{
 		loadRestrictor: lr,
 		root:           root,
 		referrer:       referrer,
 		fSys:           fSys,
 		cloner:         cloner,
+		clones:         clones,
 		cleaner:        func() error { return nil },
 	}
 }
@@ -161,7 +166,7 @@ func (fl *FileLoader) New(path string) (ifc.Loader, error) {
 			return nil, err
 		}
 		return newLoaderAtGitClone(
-			repoSpec, fl.fSys, fl, fl.cloner)
+			repoSpec, fl.fSys, fl, fl.cloner, fl.clones)
 	}
 
 	if filepath.IsAbs(path) {
@@ -178,16 +183,24 @@ func (fl *FileLoader) New(path string) (ifc.Loader, error) {
 		return nil, err
 	}
 	return newLoaderAtConfirmedDir(
-		fl.loadRestrictor, root, fl.fSys, fl, fl.cloner), nil
+		fl.loadRestrictor, root, fl.fSys, fl, fl.cloner, fl.clones), nil
 }
 
 // newLoaderAtGitClone returns a new Loader pinned to a temporary
-// directory holding a cloned git repo.
+// directory holding a cloned git repo, shared through clones
+// if it's non-nil.
 func newLoaderAtGitClone(
 	repoSpec *git.RepoSpec, fSys filesys.FileSystem,
-	referrer *FileLoader, cloner git.Cloner) (ifc.Loader, error) {
+	referrer *FileLoader, cloner git.Cloner, clones *CloneCache) (ifc.Loader, error) {
 	cleaner := repoSpec.Cleaner(fSys)
-	err := cloner(repoSpec)
+	var err error
+	if clones != nil {
+		// The cache removes the clone.
+		cleaner = func() error { return nil }
+		err = clones.clone(cloner, fSys, repoSpec)
+	} else {
+		err = cloner(repoSpec)
+	}
 	if err != nil {
 		cleaner()
 		return nil, err
@@ -222,6 +235,7 @@ func newLoaderAtGitClone(
 		repoSpec:       repoSpec,
 		fSys:           fSys,
 		cloner:         cloner,
+		clones:         clones,
 		cleaner:        cleaner,
 	}, nil
 }
@@ -405,7 +405,7 @@ whatever
 
 	l, err := newLoaderAtGitClone(
 		repoSpec, fSys, nil,
-		git.DoNothingCloner(filesys.ConfirmedDir(coRoot)))
+		git.DoNothingCloner(filesys.ConfirmedDir(coRoot)), nil)
 	require.NoError(err)
 	repo := l.Repo()
 	require.Equal(coRoot, repo)
@@ -472,7 +472,7 @@ func TestLoaderDisallowsLocalBaseFromRemoteOverlay(t *testing.T) {
 
 	l1, err = newLoaderAtGitClone(
 		repoSpec, fSys, nil,
-		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)))
+		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)), nil)
 	require.NoError(err)
 	require.Equal(cloneRoot+"/foo/overlay", l1.Root())
 
@@ -502,7 +502,7 @@ func TestLoaderDisallowsRemoteBaseExitRepo(t *testing.T) {
 	repoSpec, err := git.NewRepoSpecFromURL("https://github.com/org/repo/base")
 	require.NoError(t, err)
 
-	_, err = newLoaderAtGitClone(repoSpec, fSys, nil, git.DoNothingCloner(filesys.ConfirmedDir(repo)))
+	_, err = newLoaderAtGitClone(repoSpec, fSys, nil, git.DoNothingCloner(filesys.ConfirmedDir(repo)), nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), fmt.Sprintf("%q refers to directory outside of repo %q", base, repo))
 }
@@ -518,7 +518,7 @@ func TestLocalLoaderReferencingGitBase(t *testing.T) {
 
 	l1 := newLoaderAtConfirmedDir(
 		RestrictionRootOnly, filesys.ConfirmedDir(topDir), fSys, nil,
-		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)))
+		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)), nil)
 	require.Equal(topDir, l1.Root())
 
 	l2, err := l1.New("github.com/someOrg/someRepo/foo/base")
@@ -539,7 +539,7 @@ func TestRepoDirectCycleDetection(t *testing.T) {
 
 	l1 := newLoaderAtConfirmedDir(
 		RestrictionRootOnly, filesys.ConfirmedDir(topDir), fSys, nil,
-		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)))
+		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)), nil)
 	p1 := "github.com/someOrg/someRepo/foo"
 	rs1, err := git.NewRepoSpecFromURL(p1)
 	require.NoError(err)
@@ -561,7 +561,7 @@ func TestRepoIndirectCycleDetection(t *testing.T) {
 
 	l0 := newLoaderAtConfirmedDir(
 		RestrictionRootOnly, filesys.ConfirmedDir(topDir), fSys, nil,
-		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)))
+		git.DoNothingCloner(filesys.ConfirmedDir(cloneRoot)), nil)
 
 	p1 := "github.com/someOrg/someRepo1"
 	p2 := "github.com/someOrg/someRepo2"
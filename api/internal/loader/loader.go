@@ -20,16 +20,24 @@ import (
 func NewLoader(
 	lr LoadRestrictorFunc,
 	target string, fSys filesys.FileSystem) (ifc.Loader, error) {
+	return NewLoaderWithCloneCache(lr, target, fSys, nil)
+}
+
+// NewLoaderWithCloneCache returns a Loader like NewLoader, sharing
+// the clones of remote repositories through clones if it's non-nil.
+func NewLoaderWithCloneCache(
+	lr LoadRestrictorFunc, target string,
+	fSys filesys.FileSystem, clones *CloneCache) (ifc.Loader, error) {
 	repoSpec, err := git.NewRepoSpecFromURL(target)
 	if err == nil {
 		// The target qualifies as a remote git target.
 		return newLoaderAtGitClone(
-			repoSpec, fSys, nil, git.ClonerUsingGitExec)
+			repoSpec, fSys, nil, git.ClonerUsingGitExec, clones)
 	}
 	root, err := filesys.ConfirmDir(fSys, target)
 	if err != nil {
 		return nil, errors.WrapPrefixf(err, ErrRtNotDir.Error())
 	}
 	return newLoaderAtConfirmedDir(
-		lr, root, fSys, nil, git.ClonerUsingGitExec), nil
+		lr, root, fSys, nil, git.ClonerUsingGitExec, clones), nil
 }
@@ -0,0 +1,76 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+)
+
+// RecordingLoader is a Loader recording the absolute paths of
+// the local files it, and the loaders it makes, load successfully.
+// The files of remote repositories and remote files aren't recorded.
+type RecordingLoader struct {
+	ifc.Loader
+	files *fileSet
+}
+
+var _ ifc.Loader = &RecordingLoader{}
+
+// fileSet is a set of paths shared by loaders.
+type fileSet struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// NewRecordingLoader returns a RecordingLoader wrapping ldr.
+func NewRecordingLoader(ldr ifc.Loader) *RecordingLoader {
+	return &RecordingLoader{
+		Loader: ldr,
+		files:  &fileSet{paths: map[string]bool{}},
+	}
+}
+
+// New returns a RecordingLoader wrapping the Loader at newRoot,
+// recording in the same set of files.
+func (rl *RecordingLoader) New(newRoot string) (ifc.Loader, error) {
+	ldr, err := rl.Loader.New(newRoot)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the wrapped loader's error is sufficient
+	}
+	return &RecordingLoader{Loader: ldr, files: rl.files}, nil
+}
+
+// Load records and returns the bytes read from location.
+func (rl *RecordingLoader) Load(location string) ([]byte, error) {
+	content, err := rl.Loader.Load(location)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the wrapped loader's error is sufficient
+	}
+	if rl.Repo() == "" && !IsRemoteFile(location) {
+		path := location
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rl.Root(), path)
+		}
+		rl.files.mu.Lock()
+		rl.files.paths[path] = true
+		rl.files.mu.Unlock()
+	}
+	return content, nil
+}
+
+// Files returns the paths of the files loaded so far, sorted.
+func (rl *RecordingLoader) Files() []string {
+	rl.files.mu.Lock()
+	defer rl.files.mu.Unlock()
+	files := make([]string, 0, len(rl.files.paths))
+	for path := range rl.files.paths {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
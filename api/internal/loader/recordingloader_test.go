@@ -0,0 +1,45 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestRecordingLoader(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	for _, path := range []string{
+		"/app/overlay/patch.yaml",
+		"/app/base/service.yaml",
+		"/app/values.yaml",
+	} {
+		require.NoError(t, fSys.WriteFile(path, []byte("content")))
+	}
+	ldr, err := NewLoader(RestrictionNone, "/app/overlay", fSys)
+	require.NoError(t, err)
+	rl := NewRecordingLoader(ldr)
+
+	_, err = rl.Load("patch.yaml")
+	require.NoError(t, err)
+	_, err = rl.Load("missing.yaml")
+	require.Error(t, err)
+	base, err := rl.New("../base")
+	require.NoError(t, err)
+	_, err = base.Load("service.yaml")
+	require.NoError(t, err)
+	_, err = base.Load("/app/values.yaml")
+	require.NoError(t, err)
+	_, err = rl.Load("patch.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/app/base/service.yaml",
+		"/app/overlay/patch.yaml",
+		"/app/values.yaml",
+	}, rl.Files())
+}
@@ -104,8 +104,7 @@ func (lc *localizer) load() (*types.Kustomization, string, error) {
 		return nil, "", errors.Wrap(err)
 	}
 
-	var kust types.Kustomization
-	err = (&kust).Unmarshal(content)
+	kust, _, err := types.DecodeKustomization(content, filepath.Join(lc.ldr.Root(), kustFileName))
 	if err != nil {
 		return nil, "", errors.Wrap(err)
 	}
@@ -115,7 +114,7 @@ func (lc *localizer) load() (*types.Kustomization, string, error) {
 	// Localize also intentionally does not enforce fields, as localize does not wish to unnecessarily
 	// repeat the responsibilities of kustomize build.
 
-	return &kust, kustFileName, nil
+	return kust, kustFileName, nil
 }
 
 // localizeNativeFields localizes paths on kustomize-native fields, like configMapGenerator, that kustomize has a
@@ -226,6 +225,12 @@ func (lc *localizer) localizeGenerator(generator *types.GeneratorArgs) error {
 			return err
 		}
 	}
+	for i, structured := range generator.StructuredSources {
+		generator.StructuredSources[i].Path, err = lc.localizeFile(structured.Path)
+		if err != nil {
+			return errors.WrapPrefixf(err, "unable to localize generator structured file")
+		}
+	}
 	generator.EnvSource = locEnvSrc
 	generator.EnvSources = locEnvs
 	generator.FileSources = locFiles
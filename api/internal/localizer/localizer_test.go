@@ -263,7 +263,7 @@ suffix: invalid`,
 
 	_, err := Run("/a", "", "", fSysTest)
 	require.EqualError(t, err,
-		`unable to localize target "/a": invalid Kustomization: json: unknown field "suffix"`)
+		`unable to localize target "/a": invalid Kustomization: /a/kustomization.yaml:2:1: unknown field "suffix", did you mean "nameSuffix"?`)
 
 	checkFSys(t, fSysExpected, fSysTest)
 }
@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 	"sigs.k8s.io/yaml"
 )
 
@@ -171,22 +172,38 @@ func (p *ExecPlugin) invokePlugin(input []byte) ([]byte, error) {
 		p.path, append([]string{f.Name()}, p.args...)...)
 	cmd.Env = p.getEnv()
 	cmd.Stdin = bytes.NewReader(input)
-	var stdErr bytes.Buffer
+	var stdOut, stdErr bytes.Buffer
+	cmd.Stdout = &stdOut
 	cmd.Stderr = &stdErr
 	if _, err := os.Stat(p.h.Loader().Root()); err == nil {
 		cmd.Dir = p.h.Loader().Root()
 	}
-	result, err := cmd.Output()
+	if sb := p.sandbox(); sb != nil {
+		err = sb.Run(cmd)
+	} else {
+		err = cmd.Run()
+	}
 	if err != nil {
 		return nil, errors.WrapPrefixf(
 			fmt.Errorf("failure in plugin configured via %s; %w",
 				f.Name(), err), stdErr.String())
 	}
-	return result, os.Remove(f.Name())
+	return stdOut.Bytes(), os.Remove(f.Name())
+}
+
+// sandbox returns the restrictions to run the plugin with, if any.
+func (p *ExecPlugin) sandbox() *sandbox.Config {
+	if p.h == nil || p.h.GeneralConfig() == nil {
+		return nil
+	}
+	return p.h.GeneralConfig().FnpLoadingOptions.ExecSandbox
 }
 
 func (p *ExecPlugin) getEnv() []string {
 	env := os.Environ()
+	if sb := p.sandbox(); sb != nil {
+		env = sb.Environ()
+	}
 	pluginConfigString := "KUSTOMIZE_PLUGIN_CONFIG_STRING=" + string(p.cfg)
 	if len(pluginConfigString) <= maxArgStringLength {
 		env = append(env, pluginConfigString)
@@ -14,6 +14,9 @@ import (
 	"sigs.k8s.io/kustomize/api/internal/plugins/execplugin"
 	"sigs.k8s.io/kustomize/api/internal/plugins/fnplugin"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/pkg/pluginmanifest"
+	"sigs.k8s.io/kustomize/api/pkg/rpcplugin"
+	"sigs.k8s.io/kustomize/api/provenance"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
@@ -31,11 +34,20 @@ type Loader struct {
 	// absolutePluginHome caches the location of a valid plugin root directory.
 	// It should only be set once the directory's existence has been confirmed.
 	absolutePluginHome string
+
+	// rpc holds the connections to RPC plugins, shared by the
+	// loaders with another working directory.
+	rpc *rpcConns
 }
 
 func NewLoader(
 	pc *types.PluginConfig, rf *resmap.Factory, fs filesys.FileSystem) *Loader {
-	return &Loader{pc: pc, rf: rf, fs: fs}
+	return &Loader{pc: pc, rf: rf, fs: fs, rpc: &rpcConns{}}
+}
+
+// Cleanup stops the RPC plugins started by the loader.
+func (l *Loader) Cleanup() error {
+	return l.rpc.closeAll()
 }
 
 // LoaderWithWorkingDir returns loader after setting its working directory.
@@ -48,7 +60,7 @@ func (l *Loader) LoaderWithWorkingDir(wd string) *Loader {
 		HelmConfig:         l.pc.HelmConfig,
 	}
 	lpc.FnpLoadingOptions.WorkingDir = wd
-	return &Loader{pc: lpc, rf: l.rf, fs: l.fs}
+	return &Loader{pc: lpc, rf: l.rf, fs: l.fs, rpc: l.rpc}
 }
 
 // Config provides the global (not plugin specific) PluginConfig data.
@@ -131,7 +143,7 @@ func (l *Loader) AbsolutePluginPath(id resid.ResId) (string, error) {
 	return filepath.Join(pluginHome, relativePluginPath(id), id.Kind), nil
 }
 
-// absPluginHome is the home of kustomize Exec and Go plugins.
+// absPluginHome is the home of kustomize Exec, RPC and Go plugins.
 // Kustomize plugin configuration files are k8s-style objects
 // containing the fields 'apiVersion' and 'kind', e.g.
 //
@@ -140,9 +152,9 @@ func (l *Loader) AbsolutePluginPath(id resid.ResId) (string, error) {
 //
 // kustomize reads plugin configuration data from a file path
 // specified in the 'generators:' or 'transformers:' field of a
-// kustomization file.  For Exec and Go plugins, kustomize
+// kustomization file.  For Exec, RPC and Go plugins, kustomize
 // uses this data to both locate the plugin and configure it.
-// Each Exec or Go plugin (its code, its tests, its supporting data
+// Each Exec, RPC or Go plugin (its code, its tests, its supporting data
 // files, etc.) must be housed in its own directory at
 //
 //	${absPluginHome}/${pluginApiVersion}/LOWERCASE(${pluginKind})
@@ -263,6 +275,9 @@ func (l *Loader) loadExecOrGoPlugin(resId resid.ResId) (resmap.Configurable, err
 	// First try to load the plugin as an executable.
 	p := execplugin.NewExecPlugin(absPluginPath)
 	if err = p.ErrIfNotExecutable(); err == nil {
+		if err = l.verifyPlugin(resId, absPluginPath); err != nil {
+			return nil, err
+		}
 		return p, nil
 	}
 	if !os.IsNotExist(err) {
@@ -275,11 +290,49 @@ func (l *Loader) loadExecOrGoPlugin(resId resid.ResId) (resmap.Configurable, err
 		// in an obscure message.
 		return nil, err
 	}
+	// Then as an RPC plugin.
+	rpcPath := absPluginPath + rpcplugin.FileSuffix
+	info, err := os.Stat(rpcPath)
+	if err == nil {
+		if l.pc.FnpLoadingOptions.ExecSandbox != nil {
+			return nil, fmt.Errorf(
+				"RPC plugin %s can't run in the exec sandbox", rpcPath)
+		}
+		if err = l.verifyPlugin(resId, rpcPath); err != nil {
+			return nil, err
+		}
+		conn, err := l.rpc.get(rpcPath, info)
+		if err != nil {
+			return nil, err
+		}
+		return newRPCPlugin(conn), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
 	// Failing the above, try loading it as a Go plugin.
-	c, err := l.loadGoPlugin(resId, absPluginPath+".so")
+	goPluginPath := absPluginPath + ".so"
+	if l.fs.Exists(goPluginPath) {
+		if err = l.verifyPlugin(resId, goPluginPath); err != nil {
+			return nil, err
+		}
+	}
+	c, err := l.loadGoPlugin(resId, goPluginPath)
 	if err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+// verifyPlugin returns an error if the plugin file at path doesn't
+// match the manifest in its directory, if any.
+func (l *Loader) verifyPlugin(resId resid.ResId, path string) error {
+	m, err := pluginmanifest.Read(l.fs, filepath.Dir(path))
+	if err != nil || m == nil {
+		return err
+	}
+	return errors.WrapPrefixf(
+		pluginmanifest.Verify(l.fs, m, path, resId.Kind, provenance.GetProvenance().Semver()),
+		"refusing plugin %s", resId)
+}
+
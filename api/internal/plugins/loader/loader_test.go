@@ -4,11 +4,17 @@
 package loader_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/api/internal/loader"
 	. "sigs.k8s.io/kustomize/api/internal/plugins/loader"
+	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/provider"
 	"sigs.k8s.io/kustomize/api/resmap"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
@@ -96,3 +102,69 @@ func TestLoaderWithWorkingDir(t *testing.T) {
 		npLdr.Config().FnpLoadingOptions.WorkingDir,
 		"the plugin working dir is not updated")
 }
+
+func TestLoaderVerifiesPluginManifest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no shell scripts")
+	}
+	home := t.TempDir()
+	t.Setenv(konfig.KustomizePluginHomeEnv, home)
+	dir := filepath.Join(home, "example.com", "v1", "somegenerator")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	plugin := []byte("#!/bin/sh\necho '{kind: ConfigMap, apiVersion: v1, metadata: {name: cm}}'\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "SomeGenerator"), plugin, 0700))
+	sum := sha256.Sum256(plugin)
+	checksum := types.PluginManifestChecksumPrefix + hex.EncodeToString(sum[:])
+
+	p := provider.NewDefaultDepProvider()
+	rmF := resmap.NewFactory(p.GetResourceFactory())
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, filesys.Separator, filesys.MakeFsInMemory())
+	require.NoError(t, err)
+	configs, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: example.com/v1
+kind: SomeGenerator
+metadata:
+  name: some
+`))
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		manifest      string
+		expectedError string
+	}{
+		"no manifest": {},
+		"matching manifest": {
+			manifest: "name: some-generator\nkinds: [SomeGenerator]\nchecksum: " + checksum + "\n",
+		},
+		"unsupported kind": {
+			manifest:      "name: some-generator\nkinds: [OtherGenerator]\n",
+			expectedError: "plugin some-generator supports kinds [OtherGenerator], not SomeGenerator",
+		},
+		"tampered": {
+			manifest:      "name: some-generator\nchecksum: sha256:0123\n",
+			expectedError: "the plugin may have been tampered with",
+		},
+		"invalid manifest": {
+			manifest:      "version: v1\n",
+			expectedError: "missing name",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			manifest := filepath.Join(dir, types.PluginManifestFileName)
+			require.NoError(t, os.RemoveAll(manifest))
+			if tc.manifest != "" {
+				require.NoError(t, os.WriteFile(manifest, []byte(tc.manifest), 0600))
+			}
+			pLdr := NewLoader(
+				types.EnabledPluginConfig(types.BploUseStaticallyLinked), rmF, filesys.MakeFsOnDisk())
+			_, err := pLdr.LoadGenerators(fLdr, valtest_test.MakeFakeValidator(), configs)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
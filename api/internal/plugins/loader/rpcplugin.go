@@ -0,0 +1,239 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/internal/plugins/utils"
+	"sigs.k8s.io/kustomize/api/pkg/rpcplugin"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// rpcConn is a connection to an RPC plugin, shared by all
+// the instances of the plugin in a build.
+type rpcConn struct {
+	// mu serializes the requests, answered in order.
+	mu   sync.Mutex
+	path string
+	enc  *json.Encoder
+	dec  *json.Decoder
+
+	// closer closes the socket, or the stdin of the process.
+	closer io.Closer
+	// cmd is the process serving the plugin, if not a socket.
+	cmd *exec.Cmd
+
+	lastInstance int
+}
+
+// dialRPCPlugin connects to the RPC plugin at path, a Unix socket
+// or an executable, and agrees on the protocol version.
+func dialRPCPlugin(path string, info os.FileInfo) (*rpcConn, error) {
+	c := &rpcConn{path: path}
+	if info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "connecting to RPC plugin %s", path)
+		}
+		c.enc, c.dec, c.closer = json.NewEncoder(conn), json.NewDecoder(conn), conn
+	} else {
+		if err := errIfNotExecutable(path, info); err != nil {
+			return nil, err
+		}
+		//nolint:gosec
+		c.cmd = exec.Command(path)
+		c.cmd.Stderr = os.Stderr
+		stdin, err := c.cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := c.cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err = c.cmd.Start(); err != nil {
+			return nil, errors.WrapPrefixf(err, "starting RPC plugin %s", path)
+		}
+		c.enc, c.dec, c.closer = json.NewEncoder(stdin), json.NewDecoder(stdout), stdin
+	}
+	resp, err := c.call(&rpcplugin.Request{
+		Method:          rpcplugin.MethodHandshake,
+		ProtocolVersion: rpcplugin.ProtocolVersion,
+	})
+	if err == nil && resp.ProtocolVersion != rpcplugin.ProtocolVersion {
+		err = fmt.Errorf("RPC plugin %s speaks protocol version %q, expected %q",
+			path, resp.ProtocolVersion, rpcplugin.ProtocolVersion)
+	}
+	if err != nil {
+		if c.cmd != nil {
+			_ = c.cmd.Process.Kill()
+		}
+		_ = c.close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func errIfNotExecutable(path string, info os.FileInfo) error {
+	if info.Mode()&0111 == 0000 && runtime.GOOS != "windows" {
+		return fmt.Errorf("unexecutable plugin at: %s", path)
+	}
+	return nil
+}
+
+// call sends req to the plugin and returns its response.
+func (c *rpcConn) call(req *rpcplugin.Request) (*rpcplugin.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.enc.Encode(req); err != nil {
+		return nil, errors.WrapPrefixf(err, "sending %s request to RPC plugin %s", req.Method, c.path)
+	}
+	var resp rpcplugin.Response
+	if err := c.dec.Decode(&resp); err != nil {
+		return nil, errors.WrapPrefixf(err, "reading %s response of RPC plugin %s", req.Method, c.path)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("failure in RPC plugin %s; %s", c.path, resp.Error)
+	}
+	return &resp, nil
+}
+
+// newInstance returns the identifier of a new plugin instance.
+func (c *rpcConn) newInstance() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastInstance++
+	return c.lastInstance
+}
+
+// close closes the connection, and waits for the process to exit.
+func (c *rpcConn) close() error {
+	err := c.closer.Close()
+	if c.cmd != nil {
+		if waitErr := c.cmd.Wait(); err == nil && waitErr != nil {
+			err = errors.WrapPrefixf(waitErr, "RPC plugin %s", c.path)
+		}
+	}
+	return err
+}
+
+// rpcConns holds the connections to the RPC plugins of a build,
+// so that each plugin process is started once.
+type rpcConns struct {
+	mu    sync.Mutex
+	conns map[string]*rpcConn
+}
+
+// get returns the connection to the RPC plugin at path,
+// connecting on first use.
+func (cs *rpcConns) get(path string, info os.FileInfo) (*rpcConn, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if c, found := cs.conns[path]; found {
+		return c, nil
+	}
+	c, err := dialRPCPlugin(path, info)
+	if err != nil {
+		return nil, err
+	}
+	if cs.conns == nil {
+		cs.conns = map[string]*rpcConn{}
+	}
+	cs.conns[path] = c
+	return c, nil
+}
+
+// closeAll closes all the connections, returning the first error.
+func (cs *rpcConns) closeAll() error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	var result error
+	for path, c := range cs.conns {
+		if err := c.close(); err != nil && result == nil {
+			result = err
+		}
+		delete(cs.conns, path)
+	}
+	return result
+}
+
+// rpcPlugin is an instance of an RPC plugin.
+type rpcPlugin struct {
+	conn     *rpcConn
+	instance int
+	h        *resmap.PluginHelpers
+
+	generator, transformer bool
+}
+
+func newRPCPlugin(conn *rpcConn) *rpcPlugin {
+	return &rpcPlugin{conn: conn, instance: conn.newInstance()}
+}
+
+func (p *rpcPlugin) Config(h *resmap.PluginHelpers, config []byte) error {
+	p.h = h
+	resp, err := p.conn.call(&rpcplugin.Request{
+		Method:   rpcplugin.MethodConfig,
+		Instance: p.instance,
+		Root:     h.Loader().Root(),
+		Config:   string(config),
+	})
+	if err != nil {
+		return err
+	}
+	p.generator, p.transformer = resp.Generator, resp.Transformer
+	return nil
+}
+
+func (p *rpcPlugin) Generate() (resmap.ResMap, error) {
+	if !p.generator {
+		return nil, fmt.Errorf("RPC plugin %s not a generator", p.conn.path)
+	}
+	resp, err := p.conn.call(&rpcplugin.Request{
+		Method:   rpcplugin.MethodGenerate,
+		Instance: p.instance,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rm, err := p.h.ResmapFactory().NewResMapFromBytes([]byte(resp.Resources))
+	if err != nil {
+		return nil, err
+	}
+	return utils.UpdateResourceOptions(rm)
+}
+
+func (p *rpcPlugin) Transform(rm resmap.ResMap) error {
+	if !p.transformer {
+		return fmt.Errorf("RPC plugin %s not a transformer", p.conn.path)
+	}
+	// add ResIds as annotations to all objects so that we can add them back
+	inputRM, err := utils.GetResMapWithIDAnnotation(rm)
+	if err != nil {
+		return err
+	}
+	resources, err := inputRM.AsYaml()
+	if err != nil {
+		return err
+	}
+	resp, err := p.conn.call(&rpcplugin.Request{
+		Method:    rpcplugin.MethodTransform,
+		Instance:  p.instance,
+		Resources: string(resources),
+	})
+	if err != nil {
+		return err
+	}
+	// update the original ResMap based on the output
+	return utils.UpdateResMapValues(p.conn.path, p.h, []byte(resp.Resources), rm)
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/internal/loader"
+	. "sigs.k8s.io/kustomize/api/internal/plugins/loader"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/pkg/rpcplugin"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// serveRPCPluginEnv makes the test binary serve testRPCPlugin
+// over stdin and stdout, to be run as a plugin.
+const serveRPCPluginEnv = "KUSTOMIZE_TEST_SERVE_RPC_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(serveRPCPluginEnv) != "" {
+		if err := rpcplugin.Serve(newTestRPCPlugin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// testRPCPlugin generates a ConfigMap holding the pid of its process,
+// and labels the resources it transforms.
+type testRPCPlugin struct {
+	name string
+}
+
+func newTestRPCPlugin() rpcplugin.Plugin {
+	return &testRPCPlugin{}
+}
+
+func (p *testRPCPlugin) Config(_ string, config *yaml.RNode) error {
+	p.name = config.GetName()
+	return nil
+}
+
+func (p *testRPCPlugin) Generate() ([]*yaml.RNode, error) {
+	cm, err := yaml.FromMap(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": p.name},
+		"data":       map[string]interface{}{"pid": strconv.Itoa(os.Getpid())},
+	})
+	return []*yaml.RNode{cm}, err
+}
+
+func (p *testRPCPlugin) Transform(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	for _, n := range nodes {
+		if err := n.PipeE(yaml.SetLabel("transformed-by", p.name)); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+const rpcPluginConfigs = `
+apiVersion: example.com/v1
+kind: RPCPlugin
+metadata:
+  name: first
+---
+apiVersion: example.com/v1
+kind: RPCPlugin
+metadata:
+  name: second
+`
+
+// makeRPCPluginPath returns the path of the RPCPlugin in a new plugin home.
+func makeRPCPluginPath(t *testing.T) string {
+	t.Helper()
+	home, err := os.MkdirTemp("", "rpc")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(home) })
+	t.Setenv(konfig.KustomizePluginHomeEnv, home)
+	dir := filepath.Join(home, "example.com", "v1", "rpcplugin")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	return filepath.Join(dir, "RPCPlugin"+rpcplugin.FileSuffix)
+}
+
+// checkRPCPlugin runs the RPCPlugin, and returns the pid of its process.
+func checkRPCPlugin(t *testing.T) int {
+	t.Helper()
+	p := provider.NewDefaultDepProvider()
+	rmF := resmap.NewFactory(p.GetResourceFactory())
+	fLdr, err := loader.NewLoader(
+		loader.RestrictionRootOnly, filesys.Separator, filesys.MakeFsInMemory())
+	require.NoError(t, err)
+	pLdr := NewLoader(
+		types.EnabledPluginConfig(types.BploUseStaticallyLinked), rmF, filesys.MakeFsOnDisk())
+	configs, err := rmF.NewResMapFromBytes([]byte(rpcPluginConfigs))
+	require.NoError(t, err)
+
+	generators, err := pLdr.LoadGenerators(
+		fLdr, valtest_test.MakeFakeValidator(), configs)
+	require.NoError(t, err)
+	require.Len(t, generators, 2)
+	var pids []string
+	for i, name := range []string{"first", "second"} {
+		rm, err := generators[i].Generate()
+		require.NoError(t, err)
+		require.Equal(t, 1, rm.Size())
+		cm := rm.Resources()[0]
+		assert.Equal(t, name, cm.GetName())
+		data := cm.GetDataMap()
+		pids = append(pids, data["pid"])
+	}
+	// both configurations are served by the same process
+	assert.Equal(t, pids[0], pids[1])
+
+	transformers, err := pLdr.LoadTransformers(
+		fLdr, valtest_test.MakeFakeValidator(), configs)
+	require.NoError(t, err)
+	rm, err := rmF.NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`))
+	require.NoError(t, err)
+	require.NoError(t, transformers[1].Transform(rm))
+	assert.Equal(t, map[string]string{"transformed-by": "second"},
+		rm.Resources()[0].GetLabels())
+
+	require.NoError(t, pLdr.Cleanup())
+	pid, err := strconv.Atoi(pids[0])
+	require.NoError(t, err)
+	return pid
+}
+
+func TestRPCPluginOverSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix sockets")
+	}
+	path := makeRPCPluginPath(t)
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer l.Close()
+	go func() { _ = rpcplugin.ServeListener(l, newTestRPCPlugin) }()
+
+	assert.Equal(t, os.Getpid(), checkRPCPlugin(t))
+}
+
+func TestRPCPluginOverStdio(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no shell scripts")
+	}
+	path := makeRPCPluginPath(t)
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(
+		"#!/bin/sh\n%s=true exec %q\n", serveRPCPluginEnv, exe)), 0700))
+
+	assert.NotEqual(t, os.Getpid(), checkRPCPlugin(t))
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package schemagen
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// KustomizationSchemas returns the JSON Schemas of the kustomization
+// files of kinds Kustomization and Component, with the descriptions
+// taken from the sources of the types package in typesDir.
+func KustomizationSchemas(typesDir string) (kustomization, component []byte, err error) {
+	t := reflect.TypeOf(types.Kustomization{})
+	g := NewGenerator()
+	if err = g.AddDocs(t.PkgPath(), typesDir); err != nil {
+		return nil, nil, err
+	}
+	if kustomization, err = kustomizationSchema(g, t, types.KustomizationKind,
+		types.KustomizationVersion, false); err != nil {
+		return nil, nil, err
+	}
+	if component, err = kustomizationSchema(g, t, types.ComponentKind,
+		types.ComponentVersion, true); err != nil {
+		return nil, nil, err
+	}
+	return kustomization, component, nil
+}
+
+// kustomizationSchema returns the schema of a kustomization file of
+// the given kind and apiVersion.  The kind of a Component is required,
+// since a kustomization file without kind is a Kustomization.
+func kustomizationSchema(
+	g *Generator, t reflect.Type, kind, apiVersion string, kindRequired bool) ([]byte, error) {
+	s, err := g.Generate(t, kind)
+	if err != nil {
+		return nil, err
+	}
+	s.Properties["kind"] = &Schema{
+		Description: "Kind of the kustomization file.",
+		Type:        "string",
+		Const:       kind,
+	}
+	s.Properties["apiVersion"] = &Schema{
+		Description: "Version of the schema of the kustomization file.",
+		Type:        "string",
+		Const:       apiVersion,
+	}
+	if kindRequired {
+		s.Description = "Component is a Kustomization of kind Component, " +
+			"which other kustomizations reuse by listing it in their components."
+		s.Required = []string{"kind"}
+	}
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
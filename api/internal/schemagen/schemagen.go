@@ -0,0 +1,259 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemagen generates JSON Schemas of Go types, as encoded by
+// encoding/json, with the descriptions of types and fields taken from
+// their Go doc comments.
+package schemagen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// SchemaVersion is the JSON Schema dialect of the schemas.
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Deprecated  bool               `json:"deprecated,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Const       string             `json:"const,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+
+	// AdditionalProperties is a *Schema, or false.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Generator generates the JSON Schemas of Go types.
+type Generator struct {
+	// docs are the doc comments of types and struct fields,
+	// by package path, then by type or "Type.Field".
+	docs map[string]map[string]string
+
+	definitions map[string]*Schema
+	// definedTypes are the types of the definitions.
+	definedTypes map[string]reflect.Type
+}
+
+// NewGenerator returns a Generator.
+func NewGenerator() *Generator {
+	return &Generator{docs: map[string]map[string]string{}}
+}
+
+// AddDocs reads the doc comments of the types of the Go package
+// pkgPath in dir.  Without them, the schemas have no descriptions.
+func (g *Generator) AddDocs(pkgPath, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	docs := map[string]string{}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" ||
+			strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				docs[ts.Name.Name] = doc.Text()
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					doc := field.Doc
+					if doc == nil {
+						doc = field.Comment
+					}
+					for _, name := range field.Names {
+						docs[ts.Name.Name+"."+name.Name] = doc.Text()
+					}
+				}
+			}
+		}
+	}
+	g.docs[pkgPath] = docs
+	return nil
+}
+
+// Generate returns the JSON Schema of the Go type t, a struct,
+// with the schemas of the structs it refers to in its definitions.
+func (g *Generator) Generate(t reflect.Type, title string) (*Schema, error) {
+	g.definitions = map[string]*Schema{}
+	g.definedTypes = map[string]reflect.Type{}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+	if _, err := g.schemaOf(t); err != nil {
+		return nil, err
+	}
+	root := g.definitions[t.Name()]
+	delete(g.definitions, t.Name())
+	root.Schema = SchemaVersion
+	root.Title = title
+	if len(g.definitions) > 0 {
+		root.Definitions = g.definitions
+	}
+	return root, nil
+}
+
+func (g *Generator) schemaOf(t reflect.Type) (*Schema, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Interface:
+		// any value
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key of %s", t)
+		}
+		values, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.define(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// define adds the schema of the struct t to the definitions,
+// returning a reference to it.
+func (g *Generator) define(t reflect.Type) (*Schema, error) {
+	name := t.Name()
+	if name == "" {
+		return nil, fmt.Errorf("unsupported anonymous struct %s", t)
+	}
+	ref := &Schema{Ref: "#/definitions/" + name}
+	if defined, found := g.definedTypes[name]; found {
+		if defined != t {
+			return nil, fmt.Errorf("types %s and %s have the same name", defined, t)
+		}
+		return ref, nil
+	}
+	s := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	s.Description, s.Deprecated = g.doc(t.PkgPath(), name)
+	// define before adding the fields, which may refer to t
+	g.definitions[name] = s
+	g.definedTypes[name] = t
+	if err := g.addFields(s, t); err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// addFields adds the fields of the struct t to the properties of s,
+// following the rules of encoding/json: the fields of embedded structs
+// without a JSON name are promoted, unless shadowed.
+func (g *Generator) addFields(s *Schema, t reflect.Type) error {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, shadowed := s.Properties[name]; shadowed {
+			continue
+		}
+		p, err := g.schemaOf(f.Type)
+		if err != nil {
+			return fmt.Errorf("field %s of %s: %w", f.Name, t, err)
+		}
+		p.Description, p.Deprecated = g.doc(t.PkgPath(), t.Name()+"."+f.Name)
+		s.Properties[name] = p
+	}
+	for _, e := range embedded {
+		if err := g.addFields(s, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doc returns the description of the type or field key in the
+// package pkgPath, and whether it's deprecated.
+func (g *Generator) doc(pkgPath, key string) (string, bool) {
+	text := strings.TrimSpace(g.docs[pkgPath][key])
+	if text == "" {
+		return "", false
+	}
+	deprecated := false
+	var paragraphs []string
+	for _, p := range strings.Split(text, "\n\n") {
+		lines := strings.Split(p, "\n")
+		for _, l := range lines {
+			if strings.HasPrefix(l, "Deprecated") {
+				deprecated = true
+			}
+		}
+		paragraphs = append(paragraphs, strings.Join(lines, " "))
+	}
+	return strings.Join(paragraphs, "\n\n"), deprecated
+}
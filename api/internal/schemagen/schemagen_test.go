@@ -0,0 +1,142 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package schemagen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sources = `package example
+
+// Meta is embedded.
+type Meta struct {
+	Name string ` + "`json:\"name\"`" + `
+	// Kind is shadowed by Outer.Kind.
+	Kind string ` + "`json:\"kind\"`" + `
+}
+
+// Outer is the root.
+//
+// It has two paragraphs.
+type Outer struct {
+	Meta ` + "`json:\",inline\"`" + `
+
+	// Kind of the outer.
+	Kind string ` + "`json:\"kind\"`" + `
+
+	// Deprecated: use Items.
+	Old []Inner ` + "`json:\"old,omitempty\"`" + `
+
+	Items map[string]*Inner ` + "`json:\"items\"`" + `
+	Count int // the count
+	Skipped string ` + "`json:\"-\"`" + `
+}
+
+// Inner is referred to.
+type Inner struct {
+	Any interface{} ` + "`json:\"any\"`" + `
+	Next *Inner ` + "`json:\"next\"`" + `
+}
+`
+
+type Meta struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type Outer struct {
+	Meta `json:",inline"`
+
+	Kind    string            `json:"kind"`
+	Old     []Inner           `json:"old,omitempty"`
+	Items   map[string]*Inner `json:"items"`
+	Count   int
+	Skipped string `json:"-"`
+	ignored bool
+}
+
+type Inner struct {
+	Any  interface{} `json:"any"`
+	Next *Inner      `json:"next"`
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.go"), []byte(sources), 0600))
+	g := NewGenerator()
+	outer := reflect.TypeOf(Outer{ignored: true})
+	require.NoError(t, g.AddDocs(outer.PkgPath(), dir))
+	s, err := g.Generate(outer, "Outer")
+	require.NoError(t, err)
+	out, err := json.MarshalIndent(s, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Outer",
+  "description": "Outer is the root.\n\nIt has two paragraphs.",
+  "type": "object",
+  "properties": {
+    "Count": {
+      "description": "the count",
+      "type": "integer"
+    },
+    "items": {
+      "type": "object",
+      "additionalProperties": {
+        "$ref": "#/definitions/Inner"
+      }
+    },
+    "kind": {
+      "description": "Kind of the outer.",
+      "type": "string"
+    },
+    "name": {
+      "type": "string"
+    },
+    "old": {
+      "description": "Deprecated: use Items.",
+      "deprecated": true,
+      "type": "array",
+      "items": {
+        "$ref": "#/definitions/Inner"
+      }
+    }
+  },
+  "additionalProperties": false,
+  "definitions": {
+    "Inner": {
+      "description": "Inner is referred to.",
+      "type": "object",
+      "properties": {
+        "any": {},
+        "next": {
+          "$ref": "#/definitions/Inner"
+        }
+      },
+      "additionalProperties": false
+    }
+  }
+}`, string(out))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := NewGenerator().Generate(reflect.TypeOf(""), "String")
+	require.EqualError(t, err, "string is not a struct")
+	_, err = NewGenerator().Generate(reflect.TypeOf(struct {
+		Keys map[int]string
+	}{}), "Anonymous")
+	require.ErrorContains(t, err, "unsupported anonymous struct")
+	type WithChan struct {
+		C chan int
+	}
+	_, err = NewGenerator().Generate(reflect.TypeOf(WithChan{}), "WithChan")
+	require.EqualError(t, err, "field C of schemagen.WithChan: unsupported type chan int")
+}
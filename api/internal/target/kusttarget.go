@@ -6,7 +6,8 @@ package target
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"sigs.k8s.io/kustomize/api/ifc"
@@ -36,6 +37,12 @@ type KustTarget struct {
 	rFactory      *resmap.Factory
 	pLdr          *loader.Loader
 	origin        *resource.Origin
+	// schema is the schema of the build, shared with the
+	// targets of the kustomizations this one refers to.
+	schema *openapi.BuildSchema
+	// diagnostics are shared with the targets of
+	// the kustomizations this one refers to.
+	diagnostics *[]types.Diagnostic
 }
 
 // NewKustTarget returns a new instance of KustTarget.
@@ -45,10 +52,11 @@ func NewKustTarget(
 	rFactory *resmap.Factory,
 	pLdr *loader.Loader) *KustTarget {
 	return &KustTarget{
-		ldr:       ldr,
-		validator: validator,
-		rFactory:  rFactory,
-		pLdr:      pLdr.LoaderWithWorkingDir(ldr.Root()),
+		ldr:         ldr,
+		validator:   validator,
+		rFactory:    rFactory,
+		pLdr:        pLdr.LoaderWithWorkingDir(ldr.Root()),
+		diagnostics: &[]types.Diagnostic{},
 	}
 }
 
@@ -59,18 +67,13 @@ func (kt *KustTarget) Load() error {
 		return err
 	}
 
-	var k types.Kustomization
-	if err := k.Unmarshal(content); err != nil {
+	k, diagnostics, err := types.DecodeKustomization(
+		content, filepath.Join(kt.ldr.Root(), kustFileName))
+	*kt.diagnostics = append(*kt.diagnostics, diagnostics...)
+	if err != nil {
 		return err
 	}
 
-	// show warning message when using deprecated fields.
-	if warningMessages := k.CheckDeprecatedFields(); warningMessages != nil {
-		for _, msg := range *warningMessages {
-			fmt.Fprintf(os.Stderr, "%v\n", msg)
-		}
-	}
-
 	k.FixKustomization()
 
 	// check that Kustomization is empty
@@ -84,11 +87,25 @@ func (kt *KustTarget) Load() error {
 			"Failed to read kustomization file under %s:\n"+
 				strings.Join(errs, "\n"), kt.ldr.Root())
 	}
-	kt.kustomization = &k
+	kt.kustomization = k
 	kt.kustFileName = kustFileName
 	return nil
 }
 
+// Diagnostics returns the warnings about the kustomization files
+// loaded by the target and the targets it refers to, e.g. about
+// deprecated fields, and about the resources it built.
+func (kt *KustTarget) Diagnostics() []types.Diagnostic {
+	return *kt.diagnostics
+}
+
+// SetSchema sets the schema of the build, which the target and the
+// targets of the kustomizations it refers to set and add the definitions
+// of their CRDs to.  Without one they change the global schema.
+func (kt *KustTarget) SetSchema(schema *openapi.BuildSchema) {
+	kt.schema = schema
+}
+
 // Kustomization returns a copy of the immutable, internal kustomization object.
 func (kt *KustTarget) Kustomization() types.Kustomization {
 	var result types.Kustomization
@@ -126,12 +143,16 @@ func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
 	return kt.makeCustomizedResMap()
 }
 
+// annotatesOrigin returns true if the origin of resources and transformers
+// is to be recorded in annotations, as requested by build metadata.
+func (kt *KustTarget) annotatesOrigin() bool {
+	return kt.origin != nil && len(kt.kustomization.BuildMetadata) != 0
+}
+
 func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
-	var origin *resource.Origin
-	if len(kt.kustomization.BuildMetadata) != 0 {
-		origin = &resource.Origin{}
-	}
-	kt.origin = origin
+	// The origin is always tracked, for selectors matching the origin path
+	// of resources, but only recorded in annotations if requested.
+	kt.origin = &resource.Origin{}
 	ra, err := kt.AccumulateTarget()
 	if err != nil {
 		return nil, err
@@ -151,6 +172,7 @@ func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
 	if err != nil {
 		return nil, err
 	}
+	*kt.diagnostics = append(*kt.diagnostics, ra.UnfixedHashedNames()...)
 
 	// With all the back references fixed, it's OK to resolve Vars.
 	err = ra.ResolveVars()
@@ -222,10 +244,25 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 		return nil, errors.WrapPrefixf(
 			err, "merging CRDs %v", crdTc)
 	}
+	openAPITc, err := accumulator.LoadConfigFromOpenAPI(
+		kt.ldr, kt.kustomization.OpenAPI["path"])
+	if err != nil {
+		return nil, errors.WrapPrefixf(
+			err, "loading name references from openapi %v", kt.kustomization.OpenAPI)
+	}
+	err = ra.MergeConfig(openAPITc)
+	if err != nil {
+		return nil, errors.WrapPrefixf(
+			err, "merging openapi name references %v", openAPITc)
+	}
 	err = kt.runGenerators(ra)
 	if err != nil {
 		return nil, err
 	}
+	err = kt.enableHashSuffixes(ra)
+	if err != nil {
+		return nil, err
+	}
 
 	// components are expected to execute after reading resources and adding generators ,before applying transformers and validation.
 	// https://github.com/nholuongut/kustomize/pull/5170#discussion_r1212101287
@@ -234,6 +271,10 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 		return nil, errors.WrapPrefixf(err, "accumulating components")
 	}
 
+	err = accumulator.LoadSchemaFromCRDs(ra.ResMap(), kt.schema)
+	if err != nil {
+		return nil, err
+	}
 	err = kt.runTransformers(ra)
 	if err != nil {
 		return nil, err
@@ -250,6 +291,70 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	return ra, nil
 }
 
+// enableHashSuffixes marks the resources opted into content hash suffixes,
+// by the HashSuffixAnnotation or the hashSuffixTargets of generatorOptions.
+// The hashes are added to the names once all resources are accumulated.
+func (kt *KustTarget) enableHashSuffixes(ra *accumulator.ResAccumulator) error {
+	m := ra.ResMap()
+	for _, r := range m.Resources() {
+		annotations := r.GetAnnotations()
+		value, found := annotations[konfig.HashSuffixAnnotation]
+		fields, hasFields := annotations[konfig.HashFieldsAnnotation]
+		if !found && !hasFields {
+			continue
+		}
+		if !found {
+			return fmt.Errorf("the annotation %q of %s requires the annotation %q",
+				konfig.HashFieldsAnnotation, r.CurId(), konfig.HashSuffixAnnotation)
+		}
+		needsHash, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("the annotation %q of %s contains an invalid value (%q)",
+				konfig.HashSuffixAnnotation, r.CurId(), value)
+		}
+		delete(annotations, konfig.HashSuffixAnnotation)
+		delete(annotations, konfig.HashFieldsAnnotation)
+		if err = r.SetAnnotations(annotations); err != nil {
+			return err
+		}
+		if !needsHash {
+			continue
+		}
+		if err = r.EnableHashSuffixOfFields(splitFieldPaths(fields)); err != nil {
+			return err
+		}
+	}
+	if kt.kustomization.GeneratorOptions == nil {
+		return nil
+	}
+	for _, t := range kt.kustomization.GeneratorOptions.HashSuffixTargets {
+		if t.Target == nil {
+			return fmt.Errorf("each entry of hashSuffixTargets requires a target")
+		}
+		selected, err := m.Select(*t.Target)
+		if err != nil {
+			return errors.WrapPrefixf(err, "selecting hashSuffixTargets")
+		}
+		for _, r := range selected {
+			if err = r.EnableHashSuffixOfFields(t.FieldPaths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitFieldPaths splits a comma separated list of field paths.
+func splitFieldPaths(s string) []string {
+	var paths []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
 // IgnoreLocal drops the local resource by checking the annotation "config.kubernetes.io/local-config".
 func (kt *KustTarget) IgnoreLocal(ra *accumulator.ResAccumulator) error {
 	rf := kt.rFactory.RF()
@@ -287,6 +392,13 @@ func (kt *KustTarget) runGenerators(
 			if err != nil {
 				return errors.WrapPrefixf(err, "adding origin annotations for generator %v", g)
 			}
+			if kt.origin != nil {
+				err = resMap.AnnotateAll(
+					utils.BuildAnnotationOriginPath, kt.origin.Append(kt.kustFileName).Path)
+				if err != nil {
+					return errors.WrapPrefixf(err, "adding origin path annotations for generator %v", g)
+				}
+			}
 		}
 		err = ra.AbsorbAll(resMap)
 		if err != nil {
@@ -309,7 +421,7 @@ func (kt *KustTarget) configureExternalGenerators() (
 			continue
 		}
 		// inline config, track the origin
-		if kt.origin != nil {
+		if kt.annotatesOrigin() {
 			resources := rm.Resources()
 			for _, r := range resources {
 				r.SetOrigin(kt.origin.Append(kt.kustFileName))
@@ -355,7 +467,7 @@ func (kt *KustTarget) configureExternalTransformers(transformers []string) ([]*r
 			continue
 		}
 		// inline config, track the origin
-		if kt.origin != nil {
+		if kt.annotatesOrigin() {
 			resources := rm.Resources()
 			for _, r := range resources {
 				r.SetOrigin(kt.origin.Append(kt.kustFileName))
@@ -489,6 +601,7 @@ func (kt *KustTarget) accumulateDirectory(
 	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool) (*accumulator.ResAccumulator, error) {
 	defer ldr.Cleanup()
 	subKt := NewKustTarget(ldr, kt.validator, kt.rFactory, kt.pLdr)
+	subKt.diagnostics = kt.diagnostics
 	err := subKt.Load()
 	if err != nil {
 		return nil, errors.WrapPrefixf(
@@ -503,7 +616,8 @@ func (kt *KustTarget) accumulateDirectory(
 			return nil, err
 		}
 	}
-	err = openapi.SetSchema(subKt.Kustomization().OpenAPI, bytes, false)
+	subKt.schema = kt.schema
+	err = kt.schema.SetSchemaIfUnset(subKt.Kustomization().OpenAPI, bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -544,6 +658,12 @@ func (kt *KustTarget) accumulateFile(
 		return errors.WrapPrefixf(err, "accumulating resources from '%s'", path)
 	}
 	if kt.origin != nil {
+		err = resources.AnnotateAll(utils.BuildAnnotationOriginPath, kt.origin.Append(path).Path)
+		if err != nil {
+			return errors.WrapPrefixf(err, "cannot add origin path annotation for '%s'", path)
+		}
+	}
+	if kt.annotatesOrigin() {
 		originAnno, err := kt.origin.Append(path).String()
 		if err != nil {
 			return errors.WrapPrefixf(err, "cannot add path annotation for '%s'", path)
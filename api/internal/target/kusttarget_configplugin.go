@@ -45,7 +45,7 @@ func (kt *KustTarget) configureBuiltinGenerators() (
 		}
 
 		var generatorOrigin *resource.Origin
-		if kt.origin != nil {
+		if kt.annotatesOrigin() {
 			generatorOrigin = &resource.Origin{
 				Repo:         kt.origin.Repo,
 				Ref:          kt.origin.Ref,
@@ -75,6 +75,7 @@ func (kt *KustTarget) configureBuiltinTransformers(
 		builtinhelpers.NamespaceTransformer,
 		builtinhelpers.PrefixTransformer,
 		builtinhelpers.SuffixTransformer,
+		builtinhelpers.MetadataRemovalTransformer,
 		builtinhelpers.LabelTransformer,
 		builtinhelpers.AnnotationsTransformer,
 		builtinhelpers.PatchJson6902Transformer,
@@ -88,7 +89,7 @@ func (kt *KustTarget) configureBuiltinTransformers(
 			return nil, err
 		}
 		var transformerOrigin *resource.Origin
-		if kt.origin != nil {
+		if kt.annotatesOrigin() {
 			transformerOrigin = &resource.Origin{
 				Repo:         kt.origin.Repo,
 				Ref:          kt.origin.Ref,
@@ -332,6 +333,32 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		result = append(result, p)
 		return
 	},
+	builtinhelpers.MetadataRemovalTransformer: func(
+		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, tc *builtinconfig.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if kt.kustomization.RemoveLabels.IsEmpty() && kt.kustomization.RemoveAnnotations.IsEmpty() {
+			return
+		}
+		var c struct {
+			Labels      *types.KeyRemoval
+			Annotations *types.KeyRemoval
+		}
+		// remove the keys from the fields the common labels and
+		// annotations are added to, including selectors and templates
+		if c.Labels, err = withFieldSpecs(kt.kustomization.RemoveLabels, tc.CommonLabels); err != nil {
+			return nil, errors.WrapPrefixf(err, "failed to merge removeLabels fieldSpecs")
+		}
+		if c.Annotations, err = withFieldSpecs(kt.kustomization.RemoveAnnotations, tc.CommonAnnotations); err != nil {
+			return nil, errors.WrapPrefixf(err, "failed to merge removeAnnotations fieldSpecs")
+		}
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
 	builtinhelpers.AnnotationsTransformer: func(
 		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, tc *builtinconfig.TransformerConfig) (
 		result []resmap.Transformer, err error) {
@@ -455,3 +482,27 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		return nil, fmt.Errorf("valueadd keyword not yet defined")
 	},
 }
+
+// withFieldSpecs returns a copy of r with its FieldSpecs merged with the
+// builtin fieldSpecs, or nil if r selects no keys.  Removal never creates
+// fields, so the create flag of the fieldSpecs is ignored.
+func withFieldSpecs(r *types.KeyRemoval, builtin types.FsSlice) (*types.KeyRemoval, error) {
+	if r.IsEmpty() {
+		return nil, nil
+	}
+	withoutCreate := func(in []types.FieldSpec) types.FsSlice {
+		out := make(types.FsSlice, len(in))
+		for i := range in {
+			out[i] = in[i]
+			out[i].CreateIfNotPresent = false
+		}
+		return out
+	}
+	fss, err := withoutCreate(r.FieldSpecs).MergeAll(withoutCreate(builtin))
+	if err != nil {
+		return nil, err
+	}
+	result := *r
+	result.FieldSpecs = fss
+	return &result, nil
+}
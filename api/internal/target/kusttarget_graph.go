@@ -0,0 +1,226 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/generators"
+	"sigs.k8s.io/kustomize/api/internal/git"
+	load "sigs.k8s.io/kustomize/api/internal/loader"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// Functions dedicated to the dependency graph of a kustomization.
+//
+// Unlike a build, walking the graph reads the kustomization files,
+// but neither the files they refer to nor remote repositories, and
+// runs no generators or transformers.
+
+// Graph adds the loaded kustomization of the target, and the nodes
+// it refers to, directly or not, to the graph g.
+func (kt *KustTarget) Graph(g *types.Graph) error {
+	root := kt.ldr.Root()
+	if _, found := g.Node(root); found {
+		return nil
+	}
+	t := types.GraphNodeKustomization
+	if kt.kustomization.Kind == types.ComponentKind {
+		t = types.GraphNodeComponent
+	}
+	g.AddNode(root, t)
+
+	k := kt.kustomization
+	if path, exists := k.OpenAPI["path"]; exists {
+		kt.graphFile(g, "openapi", path)
+	}
+	for _, path := range k.Resources {
+		if err := kt.graphResource(g, "resources", path); err != nil {
+			return err
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		paths []string
+	}{
+		{"generators", k.Generators},
+		{"transformers", k.Transformers},
+		{"validators", k.Validators},
+	} {
+		for _, path := range field.paths {
+			// like the build, first try the entry as an inline
+			// configuration, which refers to no file
+			if _, err := kt.rFactory.NewResMapFromBytes([]byte(path)); err == nil {
+				continue
+			}
+			if err := kt.graphResource(g, field.name, path); err != nil {
+				return err
+			}
+		}
+	}
+	for _, path := range k.Components {
+		if err := kt.graphComponent(g, path); err != nil {
+			return err
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		paths []string
+	}{
+		{"crds", k.Crds},
+		{"configurations", k.Configurations},
+	} {
+		for _, path := range field.paths {
+			kt.graphFile(g, field.name, path)
+		}
+	}
+	for _, p := range k.Patches {
+		kt.graphFile(g, "patches", p.Path)
+	}
+	for _, p := range k.PatchesJson6902 {
+		kt.graphFile(g, "patchesJson6902", p.Path)
+	}
+	for _, p := range k.PatchesStrategicMerge {
+		// like the transformer, first try the entry as an inline patch
+		if _, err := kt.rFactory.RF().SliceFromBytes([]byte(p)); err != nil {
+			kt.graphFile(g, "patchesStrategicMerge", string(p))
+		}
+	}
+	for _, r := range k.Replacements {
+		kt.graphFile(g, "replacements", r.Path)
+	}
+	for i := range k.ConfigMapGenerator {
+		kt.graphGenerator(g, "configMapGenerator", &k.ConfigMapGenerator[i].GeneratorArgs)
+	}
+	for i := range k.SecretGenerator {
+		kt.graphGenerator(g, "secretGenerator", &k.SecretGenerator[i].GeneratorArgs)
+	}
+	for i := range k.HelmCharts {
+		kt.graphHelmChart(g, &k.HelmCharts[i])
+	}
+	return nil
+}
+
+// graphResource adds the node of the path of a resource, or of a
+// generator, transformer or validator configuration, which is a
+// file, a kustomization or a remote file or repository.
+func (kt *KustTarget) graphResource(g *types.Graph, field, path string) error {
+	if isRemote(path) {
+		kt.graphRemote(g, field, path)
+		return nil
+	}
+	ldr, err := kt.ldr.New(path)
+	if err != nil {
+		// not a directory, so a file
+		kt.graphFile(g, field, path)
+		return nil
+	}
+	return kt.graphDirectory(g, field, ldr)
+}
+
+// graphComponent adds the node of the path of a component,
+// which is a directory or a remote repository.
+func (kt *KustTarget) graphComponent(g *types.Graph, path string) error {
+	if isRemote(path) {
+		kt.graphRemote(g, "components", path)
+		return nil
+	}
+	ldr, err := kt.ldr.New(path)
+	if err != nil {
+		return errors.WrapPrefixf(err, "component %q", path)
+	}
+	return kt.graphDirectory(g, "components", ldr)
+}
+
+// graphDirectory adds the kustomization at the root of ldr,
+// and the nodes it refers to.
+func (kt *KustTarget) graphDirectory(g *types.Graph, field string, ldr ifc.Loader) error {
+	defer ldr.Cleanup()
+	g.AddEdge(kt.ldr.Root(), ldr.Root(), field)
+	if _, found := g.Node(ldr.Root()); found {
+		return nil
+	}
+	subKt := NewKustTarget(ldr, kt.validator, kt.rFactory, kt.pLdr)
+	subKt.diagnostics = kt.diagnostics
+	if err := subKt.Load(); err != nil {
+		return errors.WrapPrefixf(
+			err, "couldn't make target for path '%s'", ldr.Root())
+	}
+	return subKt.Graph(g)
+}
+
+// graphFile adds the node of the path of a local or remote file,
+// if set.
+func (kt *KustTarget) graphFile(g *types.Graph, field, path string) {
+	if path == "" {
+		return
+	}
+	if load.IsRemoteFile(path) {
+		kt.graphRemote(g, field, path)
+		return
+	}
+	id := filepath.Join(kt.ldr.Root(), path)
+	g.AddNode(id, types.GraphNodeFile)
+	g.AddEdge(kt.ldr.Root(), id, field)
+}
+
+func (kt *KustTarget) graphRemote(g *types.Graph, field, path string) {
+	g.AddNode(path, types.GraphNodeRemote)
+	g.AddEdge(kt.ldr.Root(), path, field)
+}
+
+// graphGenerator adds the nodes of the files read by a generator.
+func (kt *KustTarget) graphGenerator(g *types.Graph, field string, args *types.GeneratorArgs) {
+	for _, source := range args.FileSources {
+		if _, path, err := generators.ParseFileSource(source); err == nil {
+			kt.graphFile(g, field, path)
+		}
+	}
+	for _, path := range args.EnvSources {
+		kt.graphFile(g, field, path)
+	}
+	kt.graphFile(g, field, args.EnvSource)
+	for _, s := range args.StructuredSources {
+		kt.graphFile(g, field, s.Path)
+	}
+}
+
+// graphHelmChart adds the node of a Helm chart, in its repository
+// or in the chart home, and of its values files.
+func (kt *KustTarget) graphHelmChart(g *types.Graph, chart *types.HelmChart) {
+	var id string
+	if chart.Repo != "" {
+		id = chart.Repo + "/" + chart.Name
+		if chart.Version != "" {
+			id += "@" + chart.Version
+		}
+	} else {
+		home := types.HelmDefaultHome
+		if kt.kustomization.HelmGlobals != nil && kt.kustomization.HelmGlobals.ChartHome != "" {
+			home = kt.kustomization.HelmGlobals.ChartHome
+		}
+		if !filepath.IsAbs(home) {
+			home = filepath.Join(kt.ldr.Root(), home)
+		}
+		id = filepath.Join(home, chart.Name)
+	}
+	g.AddNode(id, types.GraphNodeHelmChart)
+	g.AddEdge(kt.ldr.Root(), id, "helmCharts")
+	kt.graphFile(g, "helmCharts", chart.ValuesFile)
+	for _, path := range chart.AdditionalValuesFiles {
+		kt.graphFile(g, "helmCharts", path)
+	}
+}
+
+// isRemote returns whether the path is a remote file or repository,
+// which the graph doesn't follow.
+func isRemote(path string) bool {
+	if load.IsRemoteFile(path) {
+		return true
+	}
+	_, err := git.NewRepoSpecFromURL(path)
+	return err == nil
+}
@@ -598,7 +598,7 @@ func TestErrorMessageForMalformedYAMLAndInvalidBase(t *testing.T) {
 	errString := err.Error()
 	assert.Contains(t, errString, "accumulating resources from '"+svr.URL+"'")
 	assert.Contains(t, errString, "MalformedYAMLError: yaml: line 3: mapping values are not allowed in this context")
-	assert.Contains(t, errString, `invalid Kustomization: json: unknown field "this"`)
+	assert.Contains(t, errString, `invalid Kustomization: /should-fail/remote-repo/kustomization.yml:1:1: unknown field "this"`)
 }
 
 // loaderWithRenamedRoots is a loader that can map New() roots to some other name
@@ -630,3 +630,80 @@ func (l loaderWithRenamedRoots) Load(path string) ([]byte, error) {
 func (l loaderWithRenamedRoots) Cleanup() error {
 	return l.baseLoader.Cleanup() //nolint:wrapcheck // baseLoader's error is sufficient
 }
+
+func TestOriginPathAnnotations(t *testing.T) {
+	for name, tc := range map[string]struct {
+		overlay   string
+		component string
+		label     string
+	}{
+		"no selector": {
+			overlay: `
+resources:
+- ../base
+`,
+		},
+		"patch target": {
+			overlay: `
+resources:
+- ../base
+patches:
+- target:
+    originPath: base/.*
+  patch: |
+    - op: add
+      path: /metadata/labels
+      value:
+        from: base
+`,
+			label: "base",
+		},
+		"component": {
+			overlay: `
+resources:
+- ../base
+components:
+- ../component
+`,
+			component: `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patches:
+- target:
+    not:
+      originPath: other.yaml
+  patch: |
+    - op: add
+      path: /metadata/labels
+      value:
+        from: component
+`,
+			label: "component",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			th := kusttest_test.MakeHarness(t)
+			th.WriteF("/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+			th.WriteK("/base", `
+resources:
+- service.yaml
+`)
+			th.WriteK("/overlay", tc.overlay)
+			if tc.component != "" {
+				th.WriteF("/component/kustomization.yaml", tc.component)
+			}
+			kt := makeKustTargetWithRf(t, th.GetFSys(), "/overlay", provider.NewDefaultDepProvider())
+			require.NoError(t, kt.Load())
+			m, err := kt.MakeCustomizedResMap()
+			require.NoError(t, err)
+			r := m.Resources()[0]
+			assert.Equal(t, "../base/service.yaml", r.GetAnnotations()[utils.BuildAnnotationOriginPath])
+			assert.Equal(t, tc.label, r.GetLabels()["from"])
+		})
+	}
+}
@@ -15,6 +15,8 @@ const (
 	BuildAnnotationsRefBy             = konfig.ConfigAnnoDomain + "/refBy"
 	BuildAnnotationsGenBehavior       = konfig.ConfigAnnoDomain + "/generatorBehavior"
 	BuildAnnotationsGenAddHashSuffix  = konfig.ConfigAnnoDomain + "/needsHashSuffix"
+	BuildAnnotationHashFields         = konfig.ConfigAnnoDomain + "/hashFields"
+	BuildAnnotationOriginPath         = konfig.ConfigAnnoDomain + "/originPath"
 
 	// the following are only for patches, to specify whether they can change names
 	// and kinds of their targets
@@ -38,6 +38,20 @@ const (
 	// If a resource has this annotation, kustomize will drop it.
 	IgnoredByKustomizeAnnotation = "config.kubernetes.io/local-config"
 
+	// A CustomResourceDefinition with this annotation declares the fields of
+	// its custom resources that refer to other objects by name, so that the
+	// references follow changes to those names, e.g. hash suffixes.
+	NameReferencesAnnotation = "kustomize.config.k8s.io/name-references"
+
+	// If a resource has this annotation set to "true", a hash of its content
+	// is appended to its name, as for generated ConfigMaps and Secrets.
+	HashSuffixAnnotation = "kustomize.config.k8s.io/needs-hash"
+
+	// The comma separated paths of the fields covered by the hash requested
+	// by HashSuffixAnnotation, e.g. "spec/template,data".  By default all the
+	// fields other than metadata and status are hashed.
+	HashFieldsAnnotation = "kustomize.config.k8s.io/hash-fields"
+
 	// Label key that indicates the resources are built from Kustomize
 	ManagedbyLabelKey = "app.kubernetes.io/managed-by"
 
@@ -0,0 +1,33 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	fLdr "sigs.k8s.io/kustomize/api/internal/loader"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// Cache shares the resources parsed from files, and the remote
+// repositories cloned, between the Runs of the Kustomizers whose
+// Options hold it, e.g. of many targets sharing bases.  It's safe
+// for concurrent use.  Cleanup removes the clones once the Runs
+// are done.
+type Cache struct {
+	resources *resmap.ResourceCache
+	clones    *fLdr.CloneCache
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		resources: resmap.NewResourceCache(),
+		clones:    fLdr.NewCloneCache(),
+	}
+}
+
+// Cleanup removes the cloned repositories.
+func (c *Cache) Cleanup() error {
+	//nolint:wrapcheck // the loader error is sufficient
+	return c.clones.Cleanup()
+}
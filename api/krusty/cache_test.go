@@ -0,0 +1,51 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+func TestCache(t *testing.T) {
+	fSys := makeInputsFileSystem(t)
+	targets := []string{"/repo/base", "/repo/overlays/dev", "/repo/overlays/prod"}
+	expected := make([]string, len(targets))
+	for i, target := range targets {
+		m, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, target)
+		require.NoError(t, err)
+		yml, err := m.AsYaml()
+		require.NoError(t, err)
+		expected[i] = string(yml)
+	}
+
+	cache := krusty.NewCache()
+	defer func() { require.NoError(t, cache.Cleanup()) }()
+	// Build every target twice, concurrently, sharing the cache.
+	actual := make([]string, 2*len(targets))
+	var wg sync.WaitGroup
+	for i := range actual {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			opts := krusty.MakeDefaultOptions()
+			opts.Cache = cache
+			m, err := krusty.MakeKustomizer(opts).Run(fSys, targets[i%len(targets)])
+			if !assert.NoError(t, err) {
+				return
+			}
+			yml, err := m.AsYaml()
+			assert.NoError(t, err)
+			actual[i] = string(yml)
+		}(i)
+	}
+	wg.Wait()
+	for i := range actual {
+		assert.Equal(t, expected[i%len(targets)], actual[i], targets[i%len(targets)])
+	}
+}
@@ -684,8 +684,6 @@ metadata:
 data:
   foo: bar`),
 				writeC("/components", `
-apiVersion: kustomize.config.k8s.io/v1alpha1
-kind: Component
 nameSuffix: -suffix
 `),
 			},
@@ -736,8 +734,6 @@ spec:
     - name: CURRENT_POD_NAMESPACE
       value: "$(PLACEHOLDER)"`),
 				writeC("/components", `
-apiVersion: kustomize.config.k8s.io/v1alpha1
-kind: Component
 namespace: kustomize-namespace`),
 			},
 			expectedOutput: `
@@ -6,6 +6,8 @@ package krusty_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
 )
 
@@ -118,6 +120,55 @@ metadata:
 `)
 }
 
+func TestGeneratorFromStructuredFiles(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+configMapGenerator:
+- name: app
+  structured:
+  - path: app.yaml
+    subPath: app
+secretGenerator:
+- name: db
+  structured:
+  - path: db.toml
+    separator: __
+`)
+	th.WriteF("app.yaml", `
+app:
+  log:
+    level: debug
+  hosts:
+  - a.example.com
+  - b.example.com
+`)
+	th.WriteF("db.toml", `
+[database]
+user = "admin"
+port = 5432
+`)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  hosts.0: a.example.com
+  hosts.1: b.example.com
+  log.level: debug
+kind: ConfigMap
+metadata:
+  name: app-t27bd2tcfg
+---
+apiVersion: v1
+data:
+  database__port: NTQzMg==
+  database__user: YWRtaW4=
+kind: Secret
+metadata:
+  name: db-85m8t4g897
+type: Opaque
+`)
+}
+
 // Generate a Secret and a ConfigMap from the same data
 // to compare the result.
 func TestGeneratorBasics(t *testing.T) {
@@ -228,9 +279,6 @@ type: Opaque
 `)
 }
 
-// TODO: This should be an error instead. However, we can't strict unmarshal until we have a yaml
-// lib that support case-insensitive keys and anchors.
-// See https://github.com/nholuongut/kustomize/issues/5061
 func TestGeneratorRepeatsInKustomization(t *testing.T) {
 	th := kusttest_test.MakeHarness(t)
 	th.WriteK(".", `
@@ -249,38 +297,12 @@ configMapGenerator:
   files:
   - nobles=nobility.txt
 `)
-	th.WriteF("forces.txt", `
-gravitational
-electromagnetic
-strong nuclear
-weak nuclear
-`)
-	th.WriteF("nobility.txt", `
-helium
-neon
-argon
-krypton
-xenon
-radon
-`)
-	m := th.Run(".", th.MakeDefaultOptions())
-	th.AssertActualEqualsExpected(m, `
-apiVersion: v1
-data:
-  fruit: apple
-  nobles: |2
-
-    helium
-    neon
-    argon
-    krypton
-    xenon
-    radon
-  vegetable: broccoli
-kind: ConfigMap
-metadata:
-  name: blah-bob-db529cg5bk
-`)
+	err := th.RunWithErr(".", th.MakeDefaultOptions())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `/kustomization.yaml:12:3: `+
+		`duplicate field "literals" in configMapGenerator[0], already defined at line 9`)
+	assert.Contains(t, err.Error(), `/kustomization.yaml:17:3: `+
+		`duplicate field "files" in configMapGenerator[0], already defined at line 15`)
 }
 
 func TestIssue3393(t *testing.T) {
@@ -354,3 +354,205 @@ spec:
             description: Containers allows injecting additional containers
 `)
 }
+
+func TestCrdListMapKeysInBuild(t *testing.T) {
+	runOpenApiTest(t, func(t *testing.T) {
+		t.Helper()
+		th := kusttest_test.MakeHarness(t)
+		th.WriteK(".", `
+resources:
+- crd.yaml
+- backend.yaml
+patches:
+- patch: |-
+    apiVersion: example.com/v1
+    kind: Backend
+    metadata:
+      name: api
+    spec:
+      servers:
+      - host: a.example.com
+        port: 8443
+        weight: 2
+      tags:
+      - canary
+`)
+		th.WriteF("crd.yaml", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backends.example.com
+spec:
+  group: example.com
+  names:
+    kind: Backend
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              servers:
+                type: array
+                x-kubernetes-list-type: map
+                x-kubernetes-list-map-keys:
+                - host
+                - port
+                items:
+                  type: object
+              tags:
+                type: array
+                x-kubernetes-list-type: set
+                items:
+                  type: string
+`)
+		th.WriteF("backend.yaml", `
+apiVersion: example.com/v1
+kind: Backend
+metadata:
+  name: api
+spec:
+  servers:
+  - host: a.example.com
+    port: 8443
+    weight: 1
+  - host: a.example.com
+    port: 8080
+    weight: 1
+  tags:
+  - stable
+`)
+		m := th.Run(".", th.MakeDefaultOptions())
+		th.AssertActualEqualsExpected(m, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backends.example.com
+spec:
+  group: example.com
+  names:
+    kind: Backend
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        properties:
+          spec:
+            properties:
+              servers:
+                items:
+                  type: object
+                type: array
+                x-kubernetes-list-map-keys:
+                - host
+                - port
+                x-kubernetes-list-type: map
+              tags:
+                items:
+                  type: string
+                type: array
+                x-kubernetes-list-type: set
+            type: object
+        type: object
+---
+apiVersion: example.com/v1
+kind: Backend
+metadata:
+  name: api
+spec:
+  servers:
+  - host: a.example.com
+    port: 8443
+    weight: 2
+  - host: a.example.com
+    port: 8080
+    weight: 1
+  tags:
+  - canary
+  - stable
+`)
+	})
+}
+
+func TestCrdSchemaIsDroppedAfterBuild(t *testing.T) {
+	runOpenApiTest(t, func(t *testing.T) {
+		t.Helper()
+		th := kusttest_test.MakeHarness(t)
+		th.WriteK("with-crd", `
+resources:
+- crd.yaml
+- backend.yaml
+`)
+		th.WriteF("with-crd/crd.yaml", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backends.example.com
+spec:
+  group: example.com
+  names:
+    kind: Backend
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              tags:
+                type: array
+                x-kubernetes-list-type: set
+                items:
+                  type: string
+`)
+		th.WriteF("with-crd/backend.yaml", `
+apiVersion: example.com/v1
+kind: Backend
+metadata:
+  name: api
+spec:
+  tags:
+  - stable
+`)
+		th.WriteK("without-crd", `
+resources:
+- backend.yaml
+patches:
+- patch: |-
+    apiVersion: example.com/v1
+    kind: Backend
+    metadata:
+      name: api
+    spec:
+      tags:
+      - canary
+`)
+		th.WriteF("without-crd/backend.yaml", `
+apiVersion: example.com/v1
+kind: Backend
+metadata:
+  name: api
+spec:
+  tags:
+  - stable
+`)
+		th.Run("with-crd", th.MakeDefaultOptions())
+
+		// the tags are replaced, as the CRD isn't part of this build
+		m := th.Run("without-crd", th.MakeDefaultOptions())
+		th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: Backend
+metadata:
+  name: api
+spec:
+  tags:
+  - canary
+`)
+	})
+}
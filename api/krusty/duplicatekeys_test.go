@@ -45,3 +45,17 @@ spec:
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "mapping key \"env\" already defined")
 }
+
+func TestDuplicateKeysInKustomization(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- a.yaml
+resources:
+- b.yaml
+`)
+	err := th.RunWithErr(".", th.MakeDefaultOptions())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		`kustomization.yaml:7:1: duplicate field "resources", already defined at line 5`)
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestGraph(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	for name, content := range map[string]string{
+		"base/kustomization.yaml": `
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: config
+  files:
+  - config.properties
+  - key=other.txt
+helmCharts:
+- name: nginx
+  repo: https://charts.example.com
+  version: 1.0.0
+  valuesFile: values.yaml
+`,
+		"components/ssl/kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patches:
+- path: patch.yaml
+`,
+		"overlay/kustomization.yaml": `
+resources:
+- ../base
+- https://example.com/crd.yaml
+- github.com/example/repo//deploy?ref=v1
+components:
+- ../components/ssl
+patchesStrategicMerge:
+- smp.yaml
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+`,
+	} {
+		require.NoError(t, fSys.WriteFile(name, []byte(content)))
+	}
+
+	b := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	g, err := b.Graph(fSys, "overlay")
+	require.NoError(t, err)
+	assert.Equal(t, []types.GraphNode{
+		{ID: "/overlay", Type: types.GraphNodeKustomization},
+		{ID: "/base", Type: types.GraphNodeKustomization},
+		{ID: "/base/deployment.yaml", Type: types.GraphNodeFile},
+		{ID: "/base/config.properties", Type: types.GraphNodeFile},
+		{ID: "/base/other.txt", Type: types.GraphNodeFile},
+		{ID: "https://charts.example.com/nginx@1.0.0", Type: types.GraphNodeHelmChart},
+		{ID: "/base/values.yaml", Type: types.GraphNodeFile},
+		{ID: "https://example.com/crd.yaml", Type: types.GraphNodeRemote},
+		{ID: "github.com/example/repo//deploy?ref=v1", Type: types.GraphNodeRemote},
+		{ID: "/components/ssl", Type: types.GraphNodeComponent},
+		{ID: "/components/ssl/patch.yaml", Type: types.GraphNodeFile},
+		{ID: "/overlay/smp.yaml", Type: types.GraphNodeFile},
+	}, g.Nodes)
+	assert.Equal(t, []types.GraphEdge{
+		{From: "/overlay", To: "/base", Field: "resources"},
+		{From: "/base", To: "/base/deployment.yaml", Field: "resources"},
+		{From: "/base", To: "/base/config.properties", Field: "configMapGenerator"},
+		{From: "/base", To: "/base/other.txt", Field: "configMapGenerator"},
+		{From: "/base", To: "https://charts.example.com/nginx@1.0.0", Field: "helmCharts"},
+		{From: "/base", To: "/base/values.yaml", Field: "helmCharts"},
+		{From: "/overlay", To: "https://example.com/crd.yaml", Field: "resources"},
+		{From: "/overlay", To: "github.com/example/repo//deploy?ref=v1", Field: "resources"},
+		{From: "/overlay", To: "/components/ssl", Field: "components"},
+		{From: "/components/ssl", To: "/components/ssl/patch.yaml", Field: "patches"},
+		{From: "/overlay", To: "/overlay/smp.yaml", Field: "patchesStrategicMerge"},
+	}, g.Edges)
+	assert.Equal(t, []string{"/base", "/overlay"}, g.Dependents("/base/values.yaml"))
+
+	diags := b.Diagnostics()
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Message, "'patchesStrategicMerge' is deprecated")
+}
+
+func TestGraphSharedBase(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.WriteFile("base/kustomization.yaml", []byte(`
+resources:
+- service.yaml
+`)))
+	require.NoError(t, fSys.WriteFile("a/kustomization.yaml", []byte(`
+resources:
+- ../base
+`)))
+	require.NoError(t, fSys.WriteFile("kustomization.yaml", []byte(`
+resources:
+- a
+- base
+`)))
+	g, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Graph(fSys, ".")
+	require.NoError(t, err)
+	assert.Len(t, g.Nodes, 4)
+	assert.Equal(t, []types.GraphEdge{
+		{From: "/", To: "/a", Field: "resources"},
+		{From: "/a", To: "/base", Field: "resources"},
+		{From: "/base", To: "/base/service.yaml", Field: "resources"},
+		{From: "/", To: "/base", Field: "resources"},
+	}, g.Edges)
+}
+
+func TestGraphMissingComponent(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.WriteFile("kustomization.yaml", []byte(`
+components:
+- missing
+`)))
+	_, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Graph(fSys, ".")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `component "missing"`)
+}
+
+func TestGraphInlineConfigs(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.WriteFile("kustomization.yaml", []byte(`
+generators:
+- generator.yaml
+- |-
+  apiVersion: builtin
+  kind: ConfigMapGenerator
+  metadata:
+    name: inline
+  literals:
+  - a=b
+transformers:
+- |-
+  apiVersion: builtin
+  kind: LabelTransformer
+  metadata:
+    name: inline
+  labels:
+    app: demo
+  fieldSpecs:
+  - path: metadata/labels
+    create: true
+`)))
+	g, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Graph(fSys, ".")
+	require.NoError(t, err)
+	assert.Equal(t, []types.GraphNode{
+		{ID: "/", Type: types.GraphNodeKustomization},
+		{ID: "/generator.yaml", Type: types.GraphNodeFile},
+	}, g.Nodes)
+	assert.Equal(t, []types.GraphEdge{
+		{From: "/", To: "/generator.yaml", Field: "generators"},
+	}, g.Edges)
+}
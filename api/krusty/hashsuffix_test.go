@@ -0,0 +1,139 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestHashSuffixAnnotation(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("base", `
+resources:
+- job.yaml
+`)
+	th.WriteF("base/job.yaml", `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    kustomize.config.k8s.io/needs-hash: "true"
+    kustomize.config.k8s.io/hash-fields: spec/template
+spec:
+  template:
+    spec:
+      containers:
+      - name: migrate
+        image: migrate:v1
+`)
+	th.WriteK("overlay", `
+resources:
+- ../base
+namePrefix: prod-
+labels:
+- pairs:
+    env: prod
+`)
+	m := th.Run("overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  labels:
+    env: prod
+  name: prod-migrate-dk6md7m97t
+spec:
+  template:
+    spec:
+      containers:
+      - image: migrate:v1
+        name: migrate
+`)
+}
+
+func TestHashSuffixTargets(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- deployment.yaml
+- hpa.yaml
+generatorOptions:
+  hashSuffixTargets:
+  - target:
+      kind: Deployment
+      name: web
+`)
+	th.WriteF("deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:v1
+`)
+	th.WriteF("hpa.yaml", `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  maxReplicas: 3
+`)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-g7b88tgf75
+spec:
+  template:
+    spec:
+      containers:
+      - image: web:v1
+        name: web
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  maxReplicas: 3
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web-g7b88tgf75
+`)
+}
+
+func TestHashSuffixAnnotationInvalid(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- job.yaml
+`)
+	th.WriteF("job.yaml", `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    kustomize.config.k8s.io/needs-hash: "yes please"
+`)
+	err := th.RunWithErr(".", th.MakeDefaultOptions())
+	assert.ErrorContains(t, err,
+		`the annotation "kustomize.config.k8s.io/needs-hash" of Job.v1.batch/migrate.[noNs] `+
+			`contains an invalid value ("yes please")`)
+}
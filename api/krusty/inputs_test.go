@@ -0,0 +1,96 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makeInputsFileSystem(t *testing.T) filesys.FileSystem {
+	t.Helper()
+	fSys := filesys.MakeFsInMemory()
+	for name, content := range map[string]string{
+		"/repo/base/kustomization.yaml": `
+resources:
+- service.yaml
+configMapGenerator:
+- name: config
+  envs:
+  - config.env
+`,
+		"/repo/base/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"/repo/base/config.env":    "A=B\n",
+		"/repo/base/unrelated.txt": "",
+		"/repo/components/debug/kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patches:
+- path: patch.yaml
+`,
+		"/repo/components/debug/patch.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  labels:
+    debug: "true"
+`,
+		"/repo/overlays/prod/kustomization.yaml": `
+resources:
+- ../../base
+components:
+- ../../components/debug
+`,
+		"/repo/overlays/dev/kustomization.yml": `
+resources:
+- ../../base
+`,
+		"/repo/.git/kustomization.yaml": "",
+	} {
+		require.NoError(t, fSys.WriteFile(name, []byte(content)))
+	}
+	return fSys
+}
+
+func TestInputs(t *testing.T) {
+	fSys := makeInputsFileSystem(t)
+	inputs, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Inputs(fSys, "/repo/overlays/prod")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/repo/base/config.env",
+		"/repo/base/kustomization.yaml",
+		"/repo/base/service.yaml",
+		"/repo/components/debug/kustomization.yaml",
+		"/repo/components/debug/patch.yaml",
+		"/repo/overlays/prod/kustomization.yaml",
+	}, inputs)
+}
+
+func TestInputsOfFailedBuild(t *testing.T) {
+	fSys := makeInputsFileSystem(t)
+	require.NoError(t, fSys.WriteFile("/repo/base/service.yaml", []byte("not: [a resource")))
+	_, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Inputs(fSys, "/repo/overlays/prod")
+	require.Error(t, err)
+}
+
+func TestDiscoverRoots(t *testing.T) {
+	fSys := makeInputsFileSystem(t)
+	roots, err := krusty.DiscoverRoots(fSys, "/repo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/repo/base",
+		"/repo/overlays/dev",
+		"/repo/overlays/prod",
+	}, roots)
+}
@@ -6,7 +6,10 @@ package krusty
 import (
 	"fmt"
 	"log"
+	"path/filepath"
+	"sort"
 
+	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/builtins"
 	fLdr "sigs.k8s.io/kustomize/api/internal/loader"
 	pLdr "sigs.k8s.io/kustomize/api/internal/plugins/loader"
@@ -32,6 +35,9 @@ import (
 type Kustomizer struct {
 	options     *Options
 	depProvider *provider.DepProvider
+	diagnostics []types.Diagnostic
+	// wrapLoader, if set, wraps the loader of a Run.
+	wrapLoader func(ifc.Loader) ifc.Loader
 }
 
 // MakeKustomizer returns an instance of Kustomizer.
@@ -52,25 +58,40 @@ func MakeKustomizer(o *Options) *Kustomizer {
 // filesystem.  One may call Run any number of times, on any number
 // of internal paths (e.g. the filesystem may contain multiple overlays,
 // and Run can be called on each of them).
+//
+// The file system may be read-only, e.g. the files of an io/fs.FS or
+// of an archive, made with filesys.MakeFsFromFS, filesys.MakeFsFromTar
+// or filesys.MakeFsFromZip.
 func (b *Kustomizer) Run(
 	fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
 	resmapFactory := resmap.NewFactory(b.depProvider.GetResourceFactory())
+	var clones *fLdr.CloneCache
+	if c := b.options.Cache; c != nil {
+		resmapFactory = resmap.NewFactoryWithCache(b.depProvider.GetResourceFactory(), c.resources)
+		clones = c.clones
+	}
 	lr := fLdr.RestrictionNone
 	if b.options.LoadRestrictions == types.LoadRestrictionsRootOnly {
 		lr = fLdr.RestrictionRootOnly
 	}
-	ldr, err := fLdr.NewLoader(lr, path, fSys)
+	ldr, err := fLdr.NewLoaderWithCloneCache(lr, path, fSys, clones)
 	if err != nil {
 		return nil, err
 	}
+	if b.wrapLoader != nil {
+		ldr = b.wrapLoader(ldr)
+	}
 	defer ldr.Cleanup()
+	// The plugin configs are always located on disk, regardless of the fSys passed in
+	pl := pLdr.NewLoader(b.options.PluginConfig, resmapFactory, filesys.MakeFsOnDisk())
+	defer pl.Cleanup()
 	kt := target.NewKustTarget(
 		ldr,
 		b.depProvider.GetFieldValidator(),
 		resmapFactory,
-		// The plugin configs are always located on disk, regardless of the fSys passed in
-		pLdr.NewLoader(b.options.PluginConfig, resmapFactory, filesys.MakeFsOnDisk()),
+		pl,
 	)
+	defer func() { b.diagnostics = kt.Diagnostics() }()
 	err = kt.Load()
 	if err != nil {
 		return nil, err
@@ -82,10 +103,14 @@ func (b *Kustomizer) Run(
 			return nil, err
 		}
 	}
-	err = openapi.SetSchema(kt.Kustomization().OpenAPI, bytes, true)
+	// The build holds its schema until it's done, so that concurrent
+	// builds of other schemas don't change it.
+	schema, err := openapi.AcquireBuildSchema(kt.Kustomization().OpenAPI, bytes)
 	if err != nil {
 		return nil, err
 	}
+	defer schema.Release()
+	kt.SetSchema(schema)
 	var m resmap.ResMap
 	m, err = kt.MakeCustomizedResMap()
 	if err != nil {
@@ -126,6 +151,80 @@ func (b *Kustomizer) Run(
 	return m, nil
 }
 
+// Graph returns the dependency graph of the kustomization at the
+// given path: the kustomizations, components, remote references,
+// Helm charts and files it refers to, directly or not, as found
+// in the kustomization files.  Unlike Run, it doesn't read the
+// referred files, nor fetch remote repositories, nor run any
+// generator or transformer.
+func (b *Kustomizer) Graph(
+	fSys filesys.FileSystem, path string) (*types.Graph, error) {
+	resmapFactory := resmap.NewFactory(b.depProvider.GetResourceFactory())
+	ldr, err := fLdr.NewLoader(fLdr.RestrictionNone, path, fSys)
+	if err != nil {
+		return nil, err
+	}
+	defer ldr.Cleanup()
+	pl := pLdr.NewLoader(b.options.PluginConfig, resmapFactory, filesys.MakeFsOnDisk())
+	defer pl.Cleanup()
+	kt := target.NewKustTarget(
+		ldr,
+		b.depProvider.GetFieldValidator(),
+		resmapFactory,
+		pl,
+	)
+	defer func() { b.diagnostics = kt.Diagnostics() }()
+	if err = kt.Load(); err != nil {
+		return nil, err
+	}
+	g := &types.Graph{}
+	if err = kt.Graph(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Inputs performs the kustomization at the given path like Run,
+// and returns its inputs: the absolute paths of the local files it
+// reads, and of the local Helm chart homes it reads files from,
+// sorted.  Remote files and repositories aren't inputs.
+func (b *Kustomizer) Inputs(
+	fSys filesys.FileSystem, path string) ([]string, error) {
+	var rl *fLdr.RecordingLoader
+	rb := *b
+	rb.wrapLoader = func(ldr ifc.Loader) ifc.Loader {
+		rl = fLdr.NewRecordingLoader(ldr)
+		return rl
+	}
+	_, err := rb.Run(fSys, path)
+	b.diagnostics = rb.diagnostics
+	if err != nil {
+		return nil, err
+	}
+	inputs := rl.Files()
+	// Helm reads the charts itself, rather than through the loader.
+	g, err := b.Graph(fSys, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range g.Nodes {
+		if n.Type == types.GraphNodeHelmChart && filepath.IsAbs(n.ID) {
+			inputs = append(inputs, n.ID)
+		}
+	}
+	sort.Strings(inputs)
+	return inputs, nil
+}
+
+// Diagnostics returns the warnings about the kustomization files
+// read by the last Run or Graph, e.g. about deprecated fields, and
+// about the resources built by the last Run, e.g. about references
+// to generated objects that don't follow their hashed names, whether
+// or not it failed.
+func (b *Kustomizer) Diagnostics() []types.Diagnostic {
+	return b.diagnostics
+}
+
 func (b *Kustomizer) applySortOrder(m resmap.ResMap, kt *target.KustTarget) error {
 	// Sort order can be defined in two places:
 	// - (new) kustomization file
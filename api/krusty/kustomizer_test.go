@@ -4,9 +4,14 @@
 package krusty_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
@@ -25,3 +30,78 @@ func TestEmptyFileSystem(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+var readOnlyFiles = map[string]string{
+	"base/kustomization.yaml": `
+resources:
+- service.yaml
+`,
+	"base/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+	"overlay/kustomization.yaml": `
+namePrefix: prod-
+resources:
+- ../base
+`,
+}
+
+// Kustomizations can be built from any io/fs.FS, e.g. an embed.FS,
+// or archive, without extracting them.
+func TestReadOnlyFileSystems(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range readOnlyFiles {
+		mapFS[name] = &fstest.MapFile{Data: []byte(content)}
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	zipFs, err := filesys.MakeFsFromZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	for name, fSys := range map[string]filesys.FileSystem{
+		"fs":  filesys.MakeFsFromFS(mapFS),
+		"zip": zipFs,
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+			m, err := b.Run(fSys, "overlay")
+			require.NoError(t, err)
+			yml, err := m.AsYaml()
+			require.NoError(t, err)
+			assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: prod-app
+`, string(yml))
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.WriteFile("base/kustomization.yaml", []byte(`
+commonLabels:
+  app: a
+`)))
+	require.NoError(t, fSys.WriteFile("overlay/kustomization.yaml", []byte(`
+bases:
+- ../base
+`)))
+	b := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	_, err := b.Run(fSys, "overlay")
+	require.NoError(t, err)
+	diags := b.Diagnostics()
+	require.Len(t, diags, 2)
+	assert.Equal(t, "/overlay/kustomization.yaml:2:1", diags[0].Position())
+	assert.Contains(t, diags[0].Message, "'bases' is deprecated")
+	assert.Equal(t, "/base/kustomization.yaml:2:1", diags[1].Position())
+	assert.Contains(t, diags[1].Message, "'commonLabels' is deprecated")
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const scaledObjectWithRefs = `
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: worker
+spec:
+  triggers:
+  - type: rabbitmq
+    metadata:
+      hostFromEnv: HOST
+    authenticationRef:
+      name: rabbit-auth
+    configMapName: rabbit-config
+`
+
+func TestNameReferencesAnnotationOnCRD(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- crd.yaml
+- scaledobject.yaml
+configMapGenerator:
+- name: rabbit-config
+  literals:
+  - queue=jobs
+secretGenerator:
+- name: rabbit-auth
+  literals:
+  - password=secret
+`)
+	th.WriteF("crd.yaml", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: scaledobjects.keda.sh
+  annotations:
+    kustomize.config.k8s.io/name-references: |
+      - kind: Secret
+        path: spec/triggers/authenticationRef/name
+      - kind: ConfigMap
+        version: v1
+        path: spec/triggers/configMapName
+spec:
+  group: keda.sh
+  names:
+    kind: ScaledObject
+`)
+	th.WriteF("scaledobject.yaml", scaledObjectWithRefs)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    kustomize.config.k8s.io/name-references: |
+      - kind: Secret
+        path: spec/triggers/authenticationRef/name
+      - kind: ConfigMap
+        version: v1
+        path: spec/triggers/configMapName
+  name: scaledobjects.keda.sh
+spec:
+  group: keda.sh
+  names:
+    kind: ScaledObject
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: worker
+spec:
+  triggers:
+  - authenticationRef:
+      name: rabbit-auth-m4d885dchh
+    configMapName: rabbit-config-4kkfcf57t2
+    metadata:
+      hostFromEnv: HOST
+    type: rabbitmq
+---
+apiVersion: v1
+data:
+  queue: jobs
+kind: ConfigMap
+metadata:
+  name: rabbit-config-4kkfcf57t2
+---
+apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: rabbit-auth-m4d885dchh
+type: Opaque
+`)
+}
+
+func TestNameReferencesAnnotationInvalid(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- crd.yaml
+`)
+	th.WriteF("crd.yaml", `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: scaledobjects.keda.sh
+  annotations:
+    kustomize.config.k8s.io/name-references: |
+      - kind: Secret
+spec:
+  group: keda.sh
+  names:
+    kind: ScaledObject
+`)
+	err := th.RunWithErr(".", th.MakeDefaultOptions())
+	assert.ErrorContains(t, err,
+		"invalid kustomize.config.k8s.io/name-references annotation on "+
+			"CustomResourceDefinition.v1.apiextensions.k8s.io/scaledobjects.keda.sh.[noNs]: "+
+			"each entry requires a kind and a path")
+}
+
+func TestNameReferencesFromOpenAPISchema(t *testing.T) {
+	runOpenApiTest(t, func(t *testing.T) {
+		t.Helper()
+		th := kusttest_test.MakeHarness(t)
+		th.WriteK(".", `
+resources:
+- scaledobject.yaml
+openapi:
+  path: schema.yaml
+configMapGenerator:
+- name: rabbit-config
+  literals:
+  - queue=jobs
+secretGenerator:
+- name: rabbit-auth
+  literals:
+  - password=secret
+`)
+		th.WriteF("schema.yaml", `
+swagger: "2.0"
+info:
+  title: keda
+  version: v1alpha1
+paths: {}
+definitions:
+  sh.keda.v1alpha1.ScaledObject:
+    type: object
+    x-kubernetes-group-version-kind:
+    - group: keda.sh
+      version: v1alpha1
+      kind: ScaledObject
+    properties:
+      spec:
+        $ref: '#/definitions/sh.keda.v1alpha1.ScaledObjectSpec'
+  sh.keda.v1alpha1.ScaledObjectSpec:
+    type: object
+    properties:
+      triggers:
+        type: array
+        items:
+          $ref: '#/definitions/sh.keda.v1alpha1.Trigger'
+  sh.keda.v1alpha1.Trigger:
+    type: object
+    properties:
+      authenticationRef:
+        type: object
+        x-kubernetes-object-ref-kind: Secret
+        x-kubernetes-object-ref-api-version: v1
+      configMapName:
+        type: string
+      parent:
+        $ref: '#/definitions/sh.keda.v1alpha1.Trigger'
+`)
+		th.WriteF("scaledobject.yaml", scaledObjectWithRefs)
+
+		o := th.MakeDefaultOptions()
+		b := krusty.MakeKustomizer(&o)
+		m, err := b.Run(th.GetFSys(), ".")
+		require.NoError(t, err)
+		th.AssertActualEqualsExpected(m, `
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: worker
+spec:
+  triggers:
+  - authenticationRef:
+      name: rabbit-auth-m4d885dchh
+    configMapName: rabbit-config
+    metadata:
+      hostFromEnv: HOST
+    type: rabbitmq
+---
+apiVersion: v1
+data:
+  queue: jobs
+kind: ConfigMap
+metadata:
+  name: rabbit-config-4kkfcf57t2
+---
+apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: rabbit-auth-m4d885dchh
+type: Opaque
+`)
+		// configMapName is not declared as a reference
+		diags := b.Diagnostics()
+		require.Len(t, diags, 1)
+		assert.Equal(t, types.DiagnosticWarning, diags[0].Severity)
+		assert.Contains(t, diags[0].Message,
+			`ScaledObject.v1alpha1.keda.sh/worker.[noNs] has "rabbit-config" `+
+				`at spec/triggers/configMapName, the name of `+
+				`ConfigMap.v1.[noGrp]/rabbit-config-4kkfcf57t2.[noNs] before its hash suffix was added`)
+	})
+}
@@ -45,6 +45,10 @@ type Options struct {
 
 	// Options related to kustomize plugins.
 	PluginConfig *types.PluginConfig
+
+	// If non-nil, the resources parsed and the remote
+	// repositories cloned by Run are shared through it.
+	Cache *Cache
 }
 
 // MakeDefaultOptions returns a default instance of Options.
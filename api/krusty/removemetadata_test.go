@@ -0,0 +1,124 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestRemoveLabelsAndAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/base/chart.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+    app.kubernetes.io/managed-by: Helm
+    helm.sh/chart: web-1.0.0
+  annotations:
+    meta.helm.sh/release-name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+      helm.sh/chart: web-1.0.0
+  template:
+    metadata:
+      labels:
+        app: web
+        helm.sh/chart: web-1.0.0
+      annotations:
+        checksum/config: abc
+    spec:
+      containers:
+      - name: web
+        image: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/managed-by: Helm
+spec:
+  selector:
+    app: web
+    helm.sh/chart: web-1.0.0
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+spec:
+  podLabels:
+    helm.sh/chart: db-1.0.0
+`)
+	th.WriteK("/base", `
+resources:
+- chart.yaml
+`)
+	th.WriteK("/app", `
+resources:
+- ../base
+labels:
+- pairs:
+    helm.sh/chart: kept
+removeLabels:
+  keys:
+  - app.kubernetes.io/managed-by
+  prefixes:
+  - helm.sh/
+  fields:
+  - path: spec/podLabels
+    kind: Database
+removeAnnotations:
+  regexes:
+  - (meta\.helm\.sh|checksum)/.*
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+    helm.sh/chart: kept
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - image: web
+        name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    helm.sh/chart: kept
+  name: web
+spec:
+  selector:
+    app: web
+---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  labels:
+    helm.sh/chart: kept
+  name: db
+spec:
+  podLabels: {}
+`)
+}
@@ -568,9 +568,6 @@ resources:
   - app.yaml
 `)
 	th.WriteK("overlay", `
-apiVersion: kustomize.config.k8s.io/v1beta1
-kind: Kustomization
-
 nameSuffix: -dev
 resources:
   - ../base
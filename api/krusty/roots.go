@@ -0,0 +1,51 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// DiscoverRoots returns the kustomization roots in the tree at dir,
+// in lexical order: the directories with a kustomization file that
+// isn't a Component, which can't be built alone.  The paths are dir
+// joined with the paths of the roots in the tree.  Hidden directories,
+// e.g. .git, are skipped.
+func DiscoverRoots(fSys filesys.FileSystem, dir string) ([]string, error) {
+	var roots []string
+	err := fSys.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		for _, name := range konfig.RecognizedKustomizationFileNames() {
+			content, err := fSys.ReadFile(filepath.Join(path, name))
+			if err != nil {
+				continue
+			}
+			// an invalid kustomization is a root, failing to build
+			if k, _, err := types.DecodeKustomization(content, ""); err != nil ||
+				k.Kind != types.ComponentKind {
+				roots = append(roots, path)
+			}
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
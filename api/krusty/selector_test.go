@@ -0,0 +1,276 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeSelectorBase(th kusttest_test.Harness) {
+	th.WriteF("base/services.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-internal
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-canary
+spec:
+  type: LoadBalancer
+`)
+	th.WriteF("base/deployments.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-canary
+spec:
+  replicas: 1
+`)
+	th.WriteK("base", `
+resources:
+- services.yaml
+- deployments.yaml
+`)
+}
+
+func TestPatchTargetFieldSelectorAndNot(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSelectorBase(th)
+	th.WriteK("overlay", `
+resources:
+- ../base
+patches:
+- target:
+    kind: Service
+    fieldSelector: spec.type=LoadBalancer
+    not:
+      name: .*-canary
+  patch: |
+    - op: add
+      path: /metadata/annotations
+      value:
+        lb: external
+`)
+	m := th.Run("overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    lb: external
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-internal
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-canary
+spec:
+  type: LoadBalancer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-canary
+spec:
+  replicas: 1
+`)
+}
+
+func TestPatchTargetOriginPath(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSelectorBase(th)
+	th.WriteF("overlay/extra.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: extra
+`)
+	th.WriteK("overlay", `
+resources:
+- ../base
+- extra.yaml
+configMapGenerator:
+- name: settings
+  literals:
+  - a=b
+patches:
+- target:
+    originPath: base/.*
+    not:
+      kind: Deployment
+  patch: |
+    - op: add
+      path: /metadata/labels
+      value:
+        from: base
+- target:
+    originPath: kustomization.yaml
+  patch: |
+    - op: add
+      path: /metadata/labels
+      value:
+        generated: "true"
+`)
+	m := th.Run("overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    from: base
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    from: base
+  name: web-internal
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    from: base
+  name: web-canary
+spec:
+  type: LoadBalancer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-canary
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: extra
+---
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  labels:
+    generated: "true"
+  name: settings-4h2mbtbbt6
+`)
+}
+
+func TestReplacementAndReplicasWithSelectors(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeSelectorBase(th)
+	th.WriteK("overlay", `
+resources:
+- ../base
+replicas:
+- selector:
+    kind: Deployment
+    not:
+      name: web-canary
+  count: 3
+replacements:
+- source:
+    kind: Service
+    name: web
+    fieldPath: spec.type
+  targets:
+  - select:
+      kind: Service
+    reject:
+    - fieldSelector: spec.type=LoadBalancer
+    fieldPaths:
+    - metadata.annotations.peer-type
+    options:
+      create: true
+`)
+	m := th.Run("overlay", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    peer-type: LoadBalancer
+  name: web-internal
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-canary
+spec:
+  type: LoadBalancer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-canary
+spec:
+  replicas: 1
+`)
+}
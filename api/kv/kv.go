@@ -58,6 +58,12 @@ func (kvl *loader) Load(
 		return nil, errors.WrapPrefixf(err,
 			"file sources: %v", args.FileSources)
 	}
+	all = append(all, pairs...)
+
+	pairs, err = kvl.keyValuesFromStructuredSources(args.StructuredSources)
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "structured sources")
+	}
 	return append(all, pairs...), nil
 }
 
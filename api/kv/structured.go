@@ -0,0 +1,283 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package kv
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	formatYAML       = "yaml"
+	formatJSON       = "json"
+	formatTOML       = "toml"
+	formatProperties = "properties"
+
+	defaultKeySeparator = "."
+)
+
+// leaf is a scalar value of a structured file, and the path to it.
+type leaf struct {
+	path  []string
+	value string
+}
+
+func (kvl *loader) keyValuesFromStructuredSources(
+	sources []types.StructuredSource) ([]types.Pair, error) {
+	var kvs []types.Pair
+	for _, src := range sources {
+		more, err := kvl.keyValuesFromStructuredSource(src)
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "%s", src.Path)
+		}
+		kvs = append(kvs, more...)
+	}
+	return kvs, nil
+}
+
+func (kvl *loader) keyValuesFromStructuredSource(
+	src types.StructuredSource) ([]types.Pair, error) {
+	format, err := structuredFormat(src)
+	if err != nil {
+		return nil, err
+	}
+	content, err := kvl.ldr.Load(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := parseStructured(format, content)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range leaves {
+		if len(l.path) == 0 {
+			return nil, errors.Errorf("expected a map or a list, found a single value")
+		}
+	}
+	if leaves, err = selectSubPath(leaves, src.SubPath); err != nil {
+		return nil, err
+	}
+	sep := src.Separator
+	if sep == "" {
+		sep = defaultKeySeparator
+	}
+	kvs := make([]types.Pair, 0, len(leaves))
+	for _, l := range leaves {
+		kvs = append(kvs, types.Pair{Key: strings.Join(l.path, sep), Value: l.value})
+	}
+	return kvs, nil
+}
+
+// structuredFormat returns the format of src, inferred from the extension
+// of its path if not set.
+func structuredFormat(src types.StructuredSource) (string, error) {
+	if src.Format != "" {
+		switch f := strings.ToLower(src.Format); f {
+		case formatYAML, formatJSON, formatTOML, formatProperties:
+			return f, nil
+		default:
+			return "", errors.Errorf(
+				"unknown format %q, must be one of %s, %s, %s or %s",
+				src.Format, formatYAML, formatJSON, formatTOML, formatProperties)
+		}
+	}
+	switch strings.ToLower(filepath.Ext(src.Path)) {
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	case ".json":
+		return formatJSON, nil
+	case ".toml":
+		return formatTOML, nil
+	case ".properties":
+		return formatProperties, nil
+	default:
+		return "", errors.Errorf(
+			"unable to infer the format from the file extension, set the format")
+	}
+}
+
+// parseStructured returns the leaves of content in the order they appear.
+func parseStructured(format string, content []byte) ([]leaf, error) {
+	switch format {
+	case formatProperties:
+		return propertiesLeaves(content)
+	case formatTOML:
+		node, err := parseTOML(content)
+		if err != nil {
+			return nil, err
+		}
+		return nodeLeaves(node, nil, nil), nil
+	default:
+		// JSON is parsed as YAML, of which it is a subset
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil && !errors.Is(err, io.EOF) {
+			return nil, errors.Wrap(err)
+		}
+		var next yaml.Node
+		if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			return nil, errors.Errorf("expected a single document, found several")
+		}
+		return nodeLeaves(&node, nil, nil), nil
+	}
+}
+
+// nodeLeaves appends the leaves of n, found at path, to leaves.
+// Elements of lists are keyed by their index.
+func nodeLeaves(n *yaml.Node, path []string, leaves []leaf) []leaf {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			leaves = nodeLeaves(c, path, leaves)
+		}
+	case yaml.AliasNode:
+		leaves = nodeLeaves(n.Alias, path, leaves)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			leaves = nodeLeaves(n.Content[i+1], appendPath(path, n.Content[i].Value), leaves)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			leaves = nodeLeaves(c, appendPath(path, strconv.Itoa(i)), leaves)
+		}
+	case yaml.ScalarNode:
+		value := n.Value
+		if n.ShortTag() == yaml.NodeTagNull {
+			value = ""
+		}
+		leaves = append(leaves, leaf{path: path, value: value})
+	}
+	return leaves
+}
+
+// appendPath returns a copy of path with elem appended.
+func appendPath(path []string, elem string) []string {
+	return append(path[:len(path):len(path)], elem)
+}
+
+// selectSubPath returns the leaves under subPath, with paths relative to it.
+func selectSubPath(leaves []leaf, subPath string) ([]leaf, error) {
+	if subPath == "" {
+		return leaves, nil
+	}
+	prefix := strings.Split(subPath, ".")
+	var selected []leaf
+	for _, l := range leaves {
+		if len(l.path) < len(prefix) || !slices.Equal(l.path[:len(prefix)], prefix) {
+			continue
+		}
+		if len(l.path) == len(prefix) {
+			return nil, errors.Errorf(
+				"subPath %q selects a single value rather than a map or a list", subPath)
+		}
+		selected = append(selected, leaf{path: l.path[len(prefix):], value: l.value})
+	}
+	if len(selected) == 0 {
+		return nil, errors.Errorf("no values found at subPath %q", subPath)
+	}
+	return selected, nil
+}
+
+// propertiesLeaves parses content in the Java properties format.
+// Keys are split on '.' into paths.
+func propertiesLeaves(content []byte) ([]leaf, error) {
+	var leaves []leaf
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(content, utf8bom)))
+	var logical strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		// an odd number of trailing backslashes continues the line
+		trailing := len(line) - len(strings.TrimRight(line, `\`))
+		if trailing%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+		key, value := splitProperty(logical.String())
+		logical.Reset()
+		leaves = append(leaves, leaf{path: strings.Split(key, "."), value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if logical.Len() != 0 {
+		key, value := splitProperty(logical.String())
+		leaves = append(leaves, leaf{path: strings.Split(key, "."), value: value})
+	}
+	return leaves, nil
+}
+
+// splitProperty splits a logical properties line into its unescaped key and
+// value.  The key ends at the first unescaped '=', ':' or whitespace.
+func splitProperty(line string) (key, value string) {
+	i := 0
+	for ; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			break
+		}
+	}
+	if i > len(line) {
+		i = len(line)
+	}
+	key, rest := line[:i], strings.TrimLeft(line[i:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return unescapeProperty(key), unescapeProperty(rest)
+}
+
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 <= len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
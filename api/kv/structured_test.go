@@ -0,0 +1,186 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestKeyValuesFromStructuredSources(t *testing.T) {
+	files := map[string]string{
+		"app.yaml": `
+database:
+  host: db
+  ports: [5432, 5433]
+  password: null
+features:
+- name: a
+  enabled: true
+`,
+		"app.json": `{"database": {"host": "db", "port": 5432}}`,
+		"app.toml": `
+# comment
+title = "app" # trailing comment
+[database]
+host = 'db'
+ports = [
+  5_432,
+  5433, # comment
+]
+options = { ssl = true, "max conns" = 10 }
+
+[[features]]
+name = "a"
+[[features]]
+name = """
+b\
+  c"""
+started = 1979-05-27 07:32:00Z
+`,
+		"app.properties": `
+# comment
+! comment
+database.host = db
+database.port:5432
+database.url=jdbc:postgresql://db/app?a=b
+message = multi \
+          line
+unicode=\u00e9t\u00e9
+`,
+		"app.conf":    `database.host=db`,
+		"scalar.yaml": `hello`,
+		"multi.yaml":  "---\na: 1\n---\nb: 2\n",
+	}
+	fSys := filesys.MakeFsInMemory()
+	for name, content := range files {
+		require.NoError(t, fSys.WriteFile(name, []byte(content)))
+	}
+	kvl := makeKvLoader(fSys)
+
+	tests := map[string]struct {
+		source   types.StructuredSource
+		expected []types.Pair
+		err      string
+	}{
+		"yaml": {
+			source: types.StructuredSource{Path: "app.yaml"},
+			expected: []types.Pair{
+				{Key: "database.host", Value: "db"},
+				{Key: "database.ports.0", Value: "5432"},
+				{Key: "database.ports.1", Value: "5433"},
+				{Key: "database.password", Value: ""},
+				{Key: "features.0.name", Value: "a"},
+				{Key: "features.0.enabled", Value: "true"},
+			},
+		},
+		"yaml with separator and sub path": {
+			source: types.StructuredSource{
+				Path: "app.yaml", SubPath: "database", Separator: "__"},
+			expected: []types.Pair{
+				{Key: "host", Value: "db"},
+				{Key: "ports__0", Value: "5432"},
+				{Key: "ports__1", Value: "5433"},
+				{Key: "password", Value: ""},
+			},
+		},
+		"sub path into a list": {
+			source:   types.StructuredSource{Path: "app.yaml", SubPath: "features.0"},
+			expected: []types.Pair{{Key: "name", Value: "a"}, {Key: "enabled", Value: "true"}},
+		},
+		"json": {
+			source: types.StructuredSource{Path: "app.json"},
+			expected: []types.Pair{
+				{Key: "database.host", Value: "db"},
+				{Key: "database.port", Value: "5432"},
+			},
+		},
+		"toml": {
+			source: types.StructuredSource{Path: "app.toml", Separator: "_"},
+			expected: []types.Pair{
+				{Key: "title", Value: "app"},
+				{Key: "database_host", Value: "db"},
+				{Key: "database_ports_0", Value: "5432"},
+				{Key: "database_ports_1", Value: "5433"},
+				{Key: "database_options_ssl", Value: "true"},
+				{Key: "database_options_max conns", Value: "10"},
+				{Key: "features_0_name", Value: "a"},
+				{Key: "features_1_name", Value: "bc"},
+				{Key: "features_1_started", Value: "1979-05-27T07:32:00Z"},
+			},
+		},
+		"properties": {
+			source: types.StructuredSource{Path: "app.properties"},
+			expected: []types.Pair{
+				{Key: "database.host", Value: "db"},
+				{Key: "database.port", Value: "5432"},
+				{Key: "database.url", Value: "jdbc:postgresql://db/app?a=b"},
+				{Key: "message", Value: "multi line"},
+				{Key: "unicode", Value: "été"},
+			},
+		},
+		"explicit format": {
+			source:   types.StructuredSource{Path: "app.conf", Format: "properties", SubPath: "database"},
+			expected: []types.Pair{{Key: "host", Value: "db"}},
+		},
+		"several documents": {
+			source: types.StructuredSource{Path: "multi.yaml"},
+			err:    "multi.yaml: expected a single document, found several",
+		},
+		"unknown extension": {
+			source: types.StructuredSource{Path: "app.conf"},
+			err:    "app.conf: unable to infer the format from the file extension, set the format",
+		},
+		"unknown format": {
+			source: types.StructuredSource{Path: "app.yaml", Format: "xml"},
+			err:    `app.yaml: unknown format "xml", must be one of yaml, json, toml or properties`,
+		},
+		"missing sub path": {
+			source: types.StructuredSource{Path: "app.yaml", SubPath: "cache"},
+			err:    `app.yaml: no values found at subPath "cache"`,
+		},
+		"sub path of a value": {
+			source: types.StructuredSource{Path: "app.yaml", SubPath: "database.host"},
+			err:    `app.yaml: subPath "database.host" selects a single value rather than a map or a list`,
+		},
+		"scalar file": {
+			source: types.StructuredSource{Path: "scalar.yaml"},
+			err:    "scalar.yaml: expected a map or a list, found a single value",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pairs, err := kvl.keyValuesFromStructuredSources([]types.StructuredSource{tc.source})
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pairs)
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := map[string]string{
+		"a = 1\na = 2":        "line 2",
+		"a = 1\n[a]":          "line 2",
+		"a = \"unterminated":  "unexpected EOF",
+		"a = [1 2]":           "line 1",
+		"a = yes":             "line 1",
+		"a = 1 b = 2":         "line 1",
+		"= 1":                 "line 1",
+		"a = \"\\q\"":         "line 1",
+		"[[a]]\nb = 1\n[a.b]": "line 3",
+	}
+	for content, expected := range tests {
+		_, err := parseTOML([]byte(content))
+		require.Error(t, err, content)
+		assert.Contains(t, err.Error(), expected, content)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package kv
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// parseTOML parses TOML into a yaml.Node tree of maps, lists and string
+// scalars, with the keys of the maps in the order they appear in content.
+func parseTOML(content []byte) (*yaml.Node, error) {
+	var value map[string]interface{}
+	md, err := toml.Decode(string(bytes.TrimPrefix(content, utf8bom)), &value)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	// the keys of the elements of arrays of tables are
+	// listed without the index of the element
+	order := map[string]int{}
+	for i, key := range md.Keys() {
+		if _, found := order[key.String()]; !found {
+			order[key.String()] = i
+		}
+	}
+	return tomlNode(value, nil, order), nil
+}
+
+// tomlNode returns the yaml.Node of the TOML value v, found at path.
+func tomlNode(v interface{}, path toml.Key, order map[string]int) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		position := func(k string) int {
+			if i, found := order[append(path[:len(path):len(path)], k).String()]; found {
+				return i
+			}
+			return len(order)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			pi, pj := position(keys[i]), position(keys[j])
+			if pi != pj {
+				return pi < pj
+			}
+			return keys[i] < keys[j]
+		})
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: k},
+				tomlNode(v[k], append(path[:len(path):len(path)], k), order))
+		}
+		return n
+	case []map[string]interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, e := range v {
+			n.Content = append(n.Content, tomlNode(e, path, order))
+		}
+		return n
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, e := range v {
+			n.Content = append(n.Content, tomlNode(e, path, order))
+		}
+		return n
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: yaml.NodeTagString, Value: tomlScalar(v)}
+	}
+}
+
+// tomlScalar returns the string value of the TOML scalar v.
+func tomlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		// integers, booleans, and local dates and times
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
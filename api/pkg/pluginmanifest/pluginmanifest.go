@@ -0,0 +1,194 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pluginmanifest reads and verifies the manifests of Exec,
+// RPC and Go plugins, and discovers the plugins in the plugin home.
+package pluginmanifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// The types of plugins, told apart by the suffix of their file.
+const (
+	TypeExec = "exec"
+	TypeRPC  = "rpc"
+	TypeGo   = "go"
+)
+
+var suffixes = map[string]string{
+	".rpc": TypeRPC,
+	".so":  TypeGo,
+}
+
+// Read reads the manifest of the plugin in dir,
+// returning nil if the plugin has none.
+func Read(fSys filesys.FileSystem, dir string) (*types.PluginManifest, error) {
+	path := filepath.Join(dir, types.PluginManifestFileName)
+	if !fSys.Exists(path) {
+		return nil, nil
+	}
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m types.PluginManifest
+	if err = yaml.UnmarshalStrict(content, &m); err != nil {
+		return nil, errors.WrapPrefixf(err, "invalid plugin manifest %s", path)
+	}
+	if err = validate(&m); err != nil {
+		return nil, errors.WrapPrefixf(err, "invalid plugin manifest %s", path)
+	}
+	return &m, nil
+}
+
+func validate(m *types.PluginManifest) error {
+	if m.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if m.Version != "" {
+		if _, err := semver.ParseTolerant(m.Version); err != nil {
+			return fmt.Errorf("invalid version %q: %w", m.Version, err)
+		}
+	}
+	if m.KustomizeVersion != "" {
+		if _, err := semver.ParseRange(m.KustomizeVersion); err != nil {
+			return fmt.Errorf("invalid kustomizeVersion %q: %w", m.KustomizeVersion, err)
+		}
+	}
+	if m.Checksum != "" && !strings.HasPrefix(m.Checksum, types.PluginManifestChecksumPrefix) {
+		return fmt.Errorf("checksum %q lacks the %q prefix",
+			m.Checksum, types.PluginManifestChecksumPrefix)
+	}
+	return nil
+}
+
+// Checksum returns the checksum of the plugin file at path,
+// in the format of manifests.
+func Checksum(fSys filesys.FileSystem, path string) (string, error) {
+	content, err := fSys.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return types.PluginManifestChecksumPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// Verify returns an error if the plugin file at path, loaded for
+// configurations of kind, doesn't match the manifest m, or if m
+// doesn't support kustomizeVersion.  A kustomizeVersion that isn't
+// a semantic version, e.g. of a development build, is supported.
+func Verify(
+	fSys filesys.FileSystem, m *types.PluginManifest,
+	path, kind, kustomizeVersion string) error {
+	if len(m.Kinds) > 0 && !slices.Contains(m.Kinds, kind) {
+		return fmt.Errorf("plugin %s supports kinds %v, not %s",
+			m.Name, m.Kinds, kind)
+	}
+	if m.KustomizeVersion != "" {
+		if v, err := semver.ParseTolerant(kustomizeVersion); err == nil {
+			// validated by Read
+			r, err := semver.ParseRange(m.KustomizeVersion)
+			if err != nil {
+				return err
+			}
+			if !r(v) {
+				return fmt.Errorf("plugin %s requires kustomize %s, not %s",
+					m.Name, m.KustomizeVersion, kustomizeVersion)
+			}
+		}
+	}
+	if m.Checksum != "" {
+		sum, err := Checksum(fSys, path)
+		if err != nil {
+			return errors.WrapPrefixf(err, "unable to verify the checksum of plugin %s", m.Name)
+		}
+		if sum != m.Checksum {
+			return fmt.Errorf(
+				"plugin file %s has checksum %s, but its manifest expects %s; "+
+					"the plugin may have been tampered with", path, sum, m.Checksum)
+		}
+	}
+	return nil
+}
+
+// Plugin is a plugin found in the plugin home.
+type Plugin struct {
+	// APIVersion of the configurations of the plugin.
+	APIVersion string
+	// Kind of the configurations of the plugin.
+	Kind string
+	// Type of the plugin, one of the types above.
+	Type string
+	// Path of the plugin file.
+	Path string
+	// Manifest of the plugin, if any.
+	Manifest *types.PluginManifest
+}
+
+// Discover returns the plugins in the plugin home, each housed at
+//
+//	${home}/${apiVersion}/LOWERCASE(${kind})/${kind}[.rpc|.so]
+//
+// Plugins with invalid manifests are reported in the error, after
+// the plugins found.
+func Discover(fSys filesys.FileSystem, home string) ([]Plugin, error) {
+	var result []Plugin
+	var errs []string
+	err := fSys.Walk(home, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() == types.PluginManifestFileName {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		ext := filepath.Ext(info.Name())
+		kind, typ := strings.TrimSuffix(info.Name(), ext), suffixes[ext]
+		if typ == "" {
+			kind, typ = info.Name(), TypeExec
+		}
+		if strings.ToLower(kind) != filepath.Base(dir) {
+			return nil
+		}
+		apiVersion, err := filepath.Rel(home, filepath.Dir(dir))
+		if err != nil {
+			return err
+		}
+		apiVersion = filepath.ToSlash(apiVersion)
+		if apiVersion == "." || strings.Count(apiVersion, "/") > 1 {
+			return nil
+		}
+		m, err := Read(fSys, dir)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		result = append(result, Plugin{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Type:       typ,
+			Path:       path,
+			Manifest:   m,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return result, nil
+}
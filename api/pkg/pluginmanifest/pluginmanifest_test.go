@@ -0,0 +1,189 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginmanifest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/api/pkg/pluginmanifest"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	pluginDir  = "/plugins/example.com/v1/somegenerator"
+	pluginPath = pluginDir + "/SomeGenerator"
+	// a checksum of other content than the plugin file
+	otherChecksum = "sha256:b0b0f0bb7e04e5d0d7a1bd1f9e0a3b4e8a1f6d4c7e5e6b1d8f8b3fb6d0e2f1a4"
+)
+
+func makeFs(t *testing.T, manifest string) filesys.FileSystem {
+	t.Helper()
+	fSys := filesys.MakeFsInMemory()
+	require.NoError(t, fSys.WriteFile(pluginPath, []byte("#!/bin/sh\n")))
+	if manifest != "" {
+		require.NoError(t, fSys.WriteFile(
+			pluginDir+"/"+types.PluginManifestFileName, []byte(manifest)))
+	}
+	return fSys
+}
+
+func TestRead(t *testing.T) {
+	testCases := map[string]struct {
+		manifest      string
+		expected      *types.PluginManifest
+		expectedError string
+	}{
+		"no manifest": {},
+		"valid": {
+			manifest: `
+name: some-generator
+version: v1.2.0
+kinds: [SomeGenerator]
+kustomizeVersion: ">=5.0.0 <6.0.0"
+checksum: sha256:abc
+`,
+			expected: &types.PluginManifest{
+				Name:             "some-generator",
+				Version:          "v1.2.0",
+				Kinds:            []string{"SomeGenerator"},
+				KustomizeVersion: ">=5.0.0 <6.0.0",
+				Checksum:         "sha256:abc",
+			},
+		},
+		"unknown field": {
+			manifest:      "name: some-generator\nkind: SomeGenerator\n",
+			expectedError: `unknown field "kind"`,
+		},
+		"missing name": {
+			manifest:      "version: v1.0.0\n",
+			expectedError: "missing name",
+		},
+		"invalid version": {
+			manifest:      "name: some-generator\nversion: latest\n",
+			expectedError: `invalid version "latest"`,
+		},
+		"invalid range": {
+			manifest:      "name: some-generator\nkustomizeVersion: 5.x and up\n",
+			expectedError: `invalid kustomizeVersion "5.x and up"`,
+		},
+		"invalid checksum": {
+			manifest:      "name: some-generator\nchecksum: abc\n",
+			expectedError: `checksum "abc" lacks the "sha256:" prefix`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m, err := Read(makeFs(t, tc.manifest), pluginDir)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				require.ErrorContains(t, err, "invalid plugin manifest "+pluginDir)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	fSys := makeFs(t, "")
+	checksum, err := Checksum(fSys, pluginPath)
+	require.NoError(t, err)
+	assert.Len(t, checksum, len(otherChecksum))
+
+	testCases := map[string]struct {
+		manifest         types.PluginManifest
+		kind             string
+		kustomizeVersion string
+		expectedError    string
+	}{
+		"no constraints": {
+			manifest: types.PluginManifest{Name: "p"},
+			kind:     "SomeGenerator",
+		},
+		"all constraints met": {
+			manifest: types.PluginManifest{
+				Name:             "p",
+				Kinds:            []string{"SomeGenerator"},
+				KustomizeVersion: ">=5.0.0 <6.0.0",
+				Checksum:         checksum,
+			},
+			kind:             "SomeGenerator",
+			kustomizeVersion: "v5.4.1",
+		},
+		"unsupported kind": {
+			manifest:      types.PluginManifest{Name: "p", Kinds: []string{"OtherGenerator"}},
+			kind:          "SomeGenerator",
+			expectedError: "plugin p supports kinds [OtherGenerator], not SomeGenerator",
+		},
+		"unsupported kustomize version": {
+			manifest:         types.PluginManifest{Name: "p", KustomizeVersion: ">=5.0.0 <6.0.0"},
+			kind:             "SomeGenerator",
+			kustomizeVersion: "v6.0.0",
+			expectedError:    "plugin p requires kustomize >=5.0.0 <6.0.0, not v6.0.0",
+		},
+		"development kustomize version": {
+			manifest:         types.PluginManifest{Name: "p", KustomizeVersion: ">=5.0.0 <6.0.0"},
+			kind:             "SomeGenerator",
+			kustomizeVersion: "(devel)",
+		},
+		"tampered": {
+			manifest:      types.PluginManifest{Name: "p", Checksum: otherChecksum},
+			kind:          "SomeGenerator",
+			expectedError: "the plugin may have been tampered with",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(fSys, &tc.manifest, pluginPath, tc.kind, tc.kustomizeVersion)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	fSys := makeFs(t, "name: some-generator\nversion: v1.0.0\n")
+	for path, content := range map[string]string{
+		"/plugins/v1/rpctransformer/RPCTransformer.rpc":          "",
+		"/plugins/v1/rpctransformer/plugin.yaml":                 "name: [invalid]\n",
+		"/plugins/example.com/v1/gotransformer/GoTransformer.so": "",
+		// sources and other files are skipped
+		"/plugins/example.com/v1/gotransformer/GoTransformer.go": "",
+		"/plugins/example.com/v1/gotransformer/README.md":        "",
+		"/plugins/README.md": "",
+	} {
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+
+	plugins, err := Discover(fSys, "/plugins")
+	require.ErrorContains(t, err, "invalid plugin manifest /plugins/v1/rpctransformer/plugin.yaml")
+	assert.Equal(t, []Plugin{
+		{
+			APIVersion: "example.com/v1",
+			Kind:       "GoTransformer",
+			Type:       TypeGo,
+			Path:       "/plugins/example.com/v1/gotransformer/GoTransformer.so",
+		},
+		{
+			APIVersion: "example.com/v1",
+			Kind:       "SomeGenerator",
+			Type:       TypeExec,
+			Path:       pluginPath,
+			Manifest:   &types.PluginManifest{Name: "some-generator", Version: "v1.0.0"},
+		},
+		{
+			APIVersion: "v1",
+			Kind:       "RPCTransformer",
+			Type:       TypeRPC,
+			Path:       "/plugins/v1/rpctransformer/RPCTransformer.rpc",
+		},
+	}, plugins)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rpcplugin is the Go SDK of kustomize RPC plugins.
+//
+// Unlike Go plugins, RPC plugins are separate programs, so they need not
+// be built with the Go toolchain and module versions of kustomize.  An RPC
+// plugin of a kind is found by kustomize at
+//
+//	${pluginHome}/${apiVersion}/LOWERCASE(${kind})/${kind}.rpc
+//
+// either as an executable, started once per build and serving the
+// protocol over its stdin and stdout, or as a Unix socket of a running
+// server.  The same connection serves all the configurations of the
+// kind in a build.
+//
+// A plugin program implements Generator or Transformer, or both,
+// and calls Serve:
+//
+//	func main() {
+//		if err := rpcplugin.Serve(func() rpcplugin.Plugin {
+//			return &plugin{}
+//		}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// Stdout is reserved for the protocol, so plugins log to stderr.
+package rpcplugin
+
+// ProtocolVersion is the version of the protocol.  It changes when
+// kustomize and a plugin of another version can't understand each other.
+const ProtocolVersion = "v1"
+
+// FileSuffix is the suffix of the name of RPC plugin files.
+const FileSuffix = ".rpc"
+
+// The methods of requests.
+const (
+	// MethodHandshake starts a connection, agreeing on the ProtocolVersion.
+	MethodHandshake = "handshake"
+	// MethodConfig makes and configures a plugin instance.
+	MethodConfig = "config"
+	// MethodGenerate generates resources with a plugin instance.
+	MethodGenerate = "generate"
+	// MethodTransform transforms resources with a plugin instance.
+	MethodTransform = "transform"
+)
+
+// Request is a call from kustomize to a plugin, sent as a JSON object.
+// Requests are answered in order, with one Response each.
+type Request struct {
+	// Method is one of the methods above.
+	Method string `json:"method"`
+
+	// ProtocolVersion is the version of kustomize, for MethodHandshake.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+
+	// Instance identifies the plugin instance, unique in a connection.
+	Instance int `json:"instance,omitempty"`
+
+	// Root is the root directory of the kustomization configuring
+	// the instance, for MethodConfig.
+	Root string `json:"root,omitempty"`
+
+	// Config is the YAML configuration of the instance, for MethodConfig.
+	Config string `json:"config,omitempty"`
+
+	// Resources are the YAML resources to transform, for MethodTransform.
+	Resources string `json:"resources,omitempty"`
+}
+
+// Response is the answer of a plugin to a Request, sent as a JSON object.
+type Response struct {
+	// ProtocolVersion is the version of the plugin, for MethodHandshake.
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+
+	// Generator is true if the instance generates resources,
+	// for MethodConfig.
+	Generator bool `json:"generator,omitempty"`
+
+	// Transformer is true if the instance transforms resources,
+	// for MethodConfig.
+	Transformer bool `json:"transformer,omitempty"`
+
+	// Resources are the YAML resources generated or transformed,
+	// for MethodGenerate and MethodTransform.
+	Resources string `json:"resources,omitempty"`
+
+	// Error is the error of the request, if it failed.
+	Error string `json:"error,omitempty"`
+}
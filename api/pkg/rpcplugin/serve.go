@@ -0,0 +1,167 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpcplugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Plugin is a plugin instance, configured once.
+// It implements Generator or Transformer, or both.
+type Plugin interface {
+	// Config configures the instance with the configuration of
+	// the kustomization in the root directory.
+	Config(root string, config *yaml.RNode) error
+}
+
+// Generator is a Plugin generating resources.
+type Generator interface {
+	Plugin
+	Generate() ([]*yaml.RNode, error)
+}
+
+// Transformer is a Plugin transforming resources.
+// It must keep the annotations of the resources it changes,
+// which kustomize uses to match them to its resources.
+type Transformer interface {
+	Plugin
+	Transform(nodes []*yaml.RNode) ([]*yaml.RNode, error)
+}
+
+// Serve serves the instances made by newPlugin over stdin and stdout,
+// until stdin is closed.
+func Serve(newPlugin func() Plugin) error {
+	return ServeConn(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, newPlugin)
+}
+
+// ListenAndServe serves the instances made by newPlugin to the
+// connections to the Unix socket at path.
+func ListenAndServe(path string, newPlugin func() Plugin) error {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return ServeListener(l, newPlugin)
+}
+
+// ServeListener serves the instances made by newPlugin to the
+// connections accepted by l, until it is closed.
+func ServeListener(l net.Listener, newPlugin func() Plugin) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			_ = ServeConn(conn, newPlugin)
+		}()
+	}
+}
+
+// ServeConn serves the instances made by newPlugin over conn,
+// until it is closed.
+func ServeConn(conn io.ReadWriter, newPlugin func() Plugin) error {
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	s := server{newPlugin: newPlugin, instances: map[int]Plugin{}}
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("unable to read request: %w", err)
+		}
+		resp, err := s.handle(&req)
+		if err != nil {
+			resp = &Response{Error: err.Error()}
+		}
+		if err = enc.Encode(resp); err != nil {
+			return fmt.Errorf("unable to write response: %w", err)
+		}
+	}
+}
+
+// server holds the instances of a connection.
+type server struct {
+	newPlugin func() Plugin
+	instances map[int]Plugin
+}
+
+func (s *server) handle(req *Request) (*Response, error) {
+	if req.Method == MethodHandshake {
+		if req.ProtocolVersion != ProtocolVersion {
+			return nil, fmt.Errorf("unsupported protocol version %q, expected %q",
+				req.ProtocolVersion, ProtocolVersion)
+		}
+		return &Response{ProtocolVersion: ProtocolVersion}, nil
+	}
+	if req.Method == MethodConfig {
+		return s.config(req)
+	}
+	p, found := s.instances[req.Instance]
+	if !found {
+		return nil, fmt.Errorf("unknown instance %d", req.Instance)
+	}
+	var nodes []*yaml.RNode
+	var err error
+	switch req.Method {
+	case MethodGenerate:
+		g, ok := p.(Generator)
+		if !ok {
+			return nil, fmt.Errorf("plugin is not a generator")
+		}
+		nodes, err = g.Generate()
+	case MethodTransform:
+		t, ok := p.(Transformer)
+		if !ok {
+			return nil, fmt.Errorf("plugin is not a transformer")
+		}
+		if nodes, err = kio.FromBytes([]byte(req.Resources)); err != nil {
+			return nil, err
+		}
+		nodes, err = t.Transform(nodes)
+	default:
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resources, err := kio.StringAll(nodes)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Resources: resources}, nil
+}
+
+func (s *server) config(req *Request) (*Response, error) {
+	config, err := yaml.Parse(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	p := s.newPlugin()
+	if err = p.Config(req.Root, config); err != nil {
+		return nil, err
+	}
+	s.instances[req.Instance] = p
+	_, generator := p.(Generator)
+	_, transformer := p.(Transformer)
+	return &Response{Generator: generator, Transformer: transformer}, nil
+}
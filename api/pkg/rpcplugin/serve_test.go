@@ -0,0 +1,75 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpcplugin_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/api/pkg/rpcplugin"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+type namePrefixer struct {
+	prefix string
+}
+
+func (p *namePrefixer) Config(_ string, config *yaml.RNode) error {
+	prefix, err := config.GetString("prefix")
+	p.prefix = prefix
+	return err
+}
+
+func (p *namePrefixer) Transform(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	for _, n := range nodes {
+		if err := n.SetName(p.prefix + n.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func TestServeConn(t *testing.T) {
+	requests := []Request{
+		{Method: MethodHandshake, ProtocolVersion: "v0"},
+		{Method: MethodHandshake, ProtocolVersion: ProtocolVersion},
+		{Method: MethodConfig, Instance: 1, Config: "kind: NamePrefixer\nprefix: dev-\n"},
+		{Method: MethodTransform, Instance: 1, Resources: "kind: Service\nmetadata:\n  name: svc\n"},
+		{Method: MethodGenerate, Instance: 1},
+		{Method: MethodTransform, Instance: 2},
+		{Method: "validate", Instance: 1},
+	}
+	var in bytes.Buffer
+	enc := json.NewEncoder(&in)
+	for i := range requests {
+		require.NoError(t, enc.Encode(&requests[i]))
+	}
+	var out strings.Builder
+	require.NoError(t, ServeConn(struct {
+		io.Reader
+		io.Writer
+	}{&in, &out}, func() Plugin { return &namePrefixer{} }))
+
+	dec := json.NewDecoder(strings.NewReader(out.String()))
+	var responses []Response
+	for dec.More() {
+		var resp Response
+		require.NoError(t, dec.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	assert.Equal(t, []Response{
+		{Error: `unsupported protocol version "v0", expected "v1"`},
+		{ProtocolVersion: ProtocolVersion},
+		{Transformer: true},
+		{Resources: "kind: Service\nmetadata:\n  name: dev-svc\n"},
+		{Error: "plugin is not a generator"},
+		{Error: "unknown instance 2"},
+		{Error: `unknown method "validate"`},
+	}, responses)
+}
@@ -16,6 +16,10 @@ import (
 type Factory struct {
 	// Makes resources.
 	resF *resource.Factory
+
+	// If this is non-nil, the resources parsed
+	// from bytes are cached in it.
+	cache *ResourceCache
 }
 
 // NewFactory returns a new resmap.Factory.
@@ -23,6 +27,12 @@ func NewFactory(rf *resource.Factory) *Factory {
 	return &Factory{resF: rf}
 }
 
+// NewFactoryWithCache returns a new resmap.Factory caching
+// the resources it parses from bytes in the given cache.
+func NewFactoryWithCache(rf *resource.Factory, c *ResourceCache) *Factory {
+	return &Factory{resF: rf, cache: c}
+}
+
 // RF returns a resource.Factory.
 func (rmF *Factory) RF() *resource.Factory {
 	return rmF.resF
@@ -66,7 +76,13 @@ func (rmF *Factory) FromFile(
 
 // NewResMapFromBytes decodes a list of objects in byte array format.
 func (rmF *Factory) NewResMapFromBytes(b []byte) (ResMap, error) {
-	resources, err := rmF.resF.SliceFromBytes(b)
+	var resources []*resource.Resource
+	var err error
+	if rmF.cache != nil {
+		resources, err = rmF.cache.sliceFromBytes(rmF.resF, b)
+	} else {
+		resources, err = rmF.resF.SliceFromBytes(b)
+	}
 	if err != nil {
 		return nil, err
 	}
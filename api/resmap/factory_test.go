@@ -111,6 +111,33 @@ metadata:
 	assert.Equal(t, expYaml, mYaml)
 }
 
+func TestFromBytesWithCache(t *testing.T) {
+	encoded := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+`)
+	f := NewFactoryWithCache(rf, NewResourceCache())
+	m1, err := f.NewResMapFromBytes(encoded)
+	require.NoError(t, err)
+	expYaml, err := m1.AsYaml()
+	require.NoError(t, err)
+	require.NoError(t, m1.Resources()[0].SetName("changed"))
+
+	// The cached resources aren't changed by the changes of the copies.
+	for i := 0; i < 2; i++ {
+		m2, err := f.NewResMapFromBytes(encoded)
+		require.NoError(t, err)
+		mYaml, err := m2.AsYaml()
+		require.NoError(t, err)
+		assert.Equal(t, string(expYaml), string(mYaml))
+		require.NoError(t, m2.Resources()[0].SetName("changed"))
+	}
+
+	_, err = f.NewResMapFromBytes([]byte("not: [yaml"))
+	require.Error(t, err)
+}
+
 func TestNewFromConfigMaps(t *testing.T) {
 	type testCase struct {
 		description string
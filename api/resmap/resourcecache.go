@@ -0,0 +1,65 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package resmap
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// ResourceCache holds the resources parsed from bytes by the
+// Factories using it, e.g. from the files of the bases shared by
+// the targets of a multi-target build, so that they're parsed once.
+// The Factories return copies of the cached resources.  It's safe
+// for concurrent use.
+type ResourceCache struct {
+	mu        sync.RWMutex
+	resources map[resourceCacheKey][]*resource.Resource
+}
+
+type resourceCacheKey struct {
+	sum                 [sha256.Size]byte
+	includeLocalConfigs bool
+}
+
+// NewResourceCache returns an empty ResourceCache.
+func NewResourceCache() *ResourceCache {
+	return &ResourceCache{
+		resources: map[resourceCacheKey][]*resource.Resource{},
+	}
+}
+
+// sliceFromBytes returns the resources parsed from b by rf,
+// parsing them unless cached.
+func (c *ResourceCache) sliceFromBytes(
+	rf *resource.Factory, b []byte) ([]*resource.Resource, error) {
+	key := resourceCacheKey{
+		sum:                 sha256.Sum256(b),
+		includeLocalConfigs: rf.IncludeLocalConfigs,
+	}
+	c.mu.RLock()
+	cached, ok := c.resources[key]
+	c.mu.RUnlock()
+	if ok {
+		return copyResources(cached), nil
+	}
+	resources, err := rf.SliceFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.resources[key] = copyResources(resources)
+	c.mu.Unlock()
+	return resources, nil
+}
+
+func copyResources(resources []*resource.Resource) []*resource.Resource {
+	result := make([]*resource.Resource, len(resources))
+	for i, r := range resources {
+		result[i] = r.DeepCopy()
+	}
+	return result
+}
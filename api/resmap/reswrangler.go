@@ -632,48 +632,74 @@ func (m *resWrangler) Select(s types.Selector) ([]*resource.Resource, error) {
 		return nil, err
 	}
 	for _, r := range m.rList {
-		curId := r.CurId()
-		orgId := r.OrgId()
-
-		// It first tries to match with the original namespace
-		// then matches with the current namespace
-		if !sr.MatchNamespace(orgId.EffectiveNamespace()) &&
-			!sr.MatchNamespace(curId.EffectiveNamespace()) {
-			continue
-		}
-
-		// It first tries to match with the original name
-		// then matches with the current name
-		if !sr.MatchName(orgId.Name) &&
-			!sr.MatchName(curId.Name) {
-			continue
-		}
-
-		// matches the GVK
-		if !sr.MatchGvk(r.GetGvk()) {
-			continue
-		}
-
-		// matches the label selector
-		matched, err := r.MatchesLabelSelector(s.LabelSelector)
+		matched, err := selects(sr, r)
 		if err != nil {
 			return nil, err
 		}
-		if !matched {
-			continue
+		if matched {
+			result = append(result, r)
 		}
+	}
+	return result, nil
+}
 
-		// matches the annotation selector
-		matched, err = r.MatchesAnnotationSelector(s.AnnotationSelector)
-		if err != nil {
-			return nil, err
-		}
-		if !matched {
-			continue
+// selects returns true if the resource matches all the
+// conditions of the selector, and not its negated selector.
+func selects(sr *types.SelectorRegex, r *resource.Resource) (bool, error) {
+	s := sr.Selector()
+	curId := r.CurId()
+	orgId := r.OrgId()
+
+	// It first tries to match with the original namespace
+	// then matches with the current namespace
+	if !sr.MatchNamespace(orgId.EffectiveNamespace()) &&
+		!sr.MatchNamespace(curId.EffectiveNamespace()) {
+		return false, nil
+	}
+
+	// It first tries to match with the original name
+	// then matches with the current name
+	if !sr.MatchName(orgId.Name) &&
+		!sr.MatchName(curId.Name) {
+		return false, nil
+	}
+
+	// matches the GVK
+	if !sr.MatchGvk(r.GetGvk()) {
+		return false, nil
+	}
+
+	// matches the origin path
+	if !sr.MatchOriginPath(r.GetOriginPath()) {
+		return false, nil
+	}
+
+	// matches the label selector
+	matched, err := r.MatchesLabelSelector(s.LabelSelector)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	// matches the annotation selector
+	matched, err = r.MatchesAnnotationSelector(s.AnnotationSelector)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	// matches the field selector
+	matched, err = r.MatchesFieldSelector(s.FieldSelector)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	// does not match the negated selector
+	if sr.Not() != nil {
+		excluded, err := selects(sr.Not(), r)
+		if err != nil || excluded {
+			return false, err
 		}
-		result = append(result, r)
 	}
-	return result, nil
+	return true, nil
 }
 
 // ToRNodeSlice returns a copy of the resources as RNodes.
@@ -0,0 +1,127 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	kyaml_utils "sigs.k8s.io/kustomize/kyaml/utils"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// fieldRequirement is a single requirement of a field selector.
+type fieldRequirement struct {
+	path   []string
+	value  string
+	equals bool
+}
+
+// parseFieldSelector parses a comma-separated list of field requirements,
+// such as "spec.type=LoadBalancer,spec.replicas!=1".  Commas and operators
+// inside brackets, as in "spec.ports.[name=http].port=80", are part of the
+// field path.
+func parseFieldSelector(selector string) ([]fieldRequirement, error) {
+	var result []fieldRequirement
+	for _, term := range splitOutsideBrackets(selector, ',') {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		field, value, equals, found := cutOperator(term)
+		if !found {
+			return nil, errors.Errorf(
+				"invalid field selector requirement %q: expected =, == or !=", term)
+		}
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return nil, errors.Errorf(
+				"invalid field selector requirement %q: empty field path", term)
+		}
+		result = append(result, fieldRequirement{
+			path:   kyaml_utils.SmarterPathSplitter(field, "."),
+			value:  strings.TrimSpace(value),
+			equals: equals,
+		})
+	}
+	return result, nil
+}
+
+// splitOutsideBrackets splits s at each sep that is not inside brackets.
+func splitOutsideBrackets(s string, sep byte) []string {
+	var result []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case sep:
+			if depth == 0 {
+				result = append(result, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, s[start:])
+}
+
+// cutOperator splits a requirement at its first operator outside brackets.
+func cutOperator(term string) (field, value string, equals, found bool) {
+	depth := 0
+	for i := 0; i < len(term); i++ {
+		switch term[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '!':
+			if depth == 0 && strings.HasPrefix(term[i:], "!=") {
+				return term[:i], term[i+2:], false, true
+			}
+		case '=':
+			if depth == 0 {
+				return term[:i], strings.TrimPrefix(term[i+1:], "="), true, true
+			}
+		}
+	}
+	return "", "", false, false
+}
+
+// MatchesFieldSelector returns true on a selector match to field values.
+// An = or == requirement matches if a field at the path has the value, and
+// a != requirement matches if no field at the path has the value.
+func (r *Resource) MatchesFieldSelector(selector string) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+	requirements, err := parseFieldSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	for _, req := range requirements {
+		fields, err := r.Pipe(&yaml.PathMatcher{Path: req.path})
+		if err != nil {
+			return false, errors.WrapPrefixf(err, "field selector %q", selector)
+		}
+		found := false
+		if fields != nil {
+			elements, err := fields.Elements()
+			if err != nil {
+				return false, errors.WrapPrefixf(err, "field selector %q", selector)
+			}
+			for _, field := range elements {
+				if field.YNode().Kind == yaml.ScalarNode && field.YNode().Value == req.value {
+					found = true
+					break
+				}
+			}
+		}
+		if found != req.equals {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesFieldSelector(t *testing.T) {
+	r, err := factory.FromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+`))
+	require.NoError(t, err)
+
+	testCases := map[string]bool{
+		"":                                 true,
+		"spec.type=LoadBalancer":           true,
+		"spec.type==LoadBalancer":          true,
+		"spec.type!=LoadBalancer":          false,
+		"spec.type=ClusterIP":              false,
+		"spec.type!=ClusterIP":             true,
+		"spec.clusterIP=None":              false,
+		"spec.clusterIP!=None":             true,
+		"spec.ports.[name=https].port=443": true,
+		"spec.ports.*.port=80":             true,
+		"spec.ports.*.port!=8080":          true,
+		"spec.type=LoadBalancer, spec.ports.[name=http].port=80": true,
+		"spec.type=LoadBalancer,spec.ports.[name=https].port=80": false,
+		"spec=LoadBalancer": false,
+		"spec.type=":        false,
+	}
+	for selector, expected := range testCases {
+		t.Run(selector, func(t *testing.T) {
+			matched, err := r.MatchesFieldSelector(selector)
+			require.NoError(t, err)
+			assert.Equal(t, expected, matched)
+		})
+	}
+
+	for _, selector := range []string{"spec.type", "=LoadBalancer"} {
+		_, err := r.MatchesFieldSelector(selector)
+		assert.ErrorContains(t, err, "invalid field selector requirement")
+	}
+}
+
+func TestGetOriginPath(t *testing.T) {
+	r, err := factory.FromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+	require.NoError(t, err)
+	assert.Empty(t, r.GetOriginPath())
+
+	r.SetAnnotations(map[string]string{
+		"config.kubernetes.io/origin": "configuredIn: base/kustomization.yaml\n",
+	})
+	assert.Equal(t, "base/kustomization.yaml", r.GetOriginPath())
+
+	r.SetAnnotations(map[string]string{
+		"config.kubernetes.io/origin":              "path: base/service.yaml\n",
+		"internal.config.kubernetes.io/originPath": "../base/service.yaml",
+	})
+	assert.Equal(t, "../base/service.yaml", r.GetOriginPath())
+}
@@ -37,6 +37,8 @@ var BuildAnnotations = []string{
 	utils.BuildAnnotationsRefBy,
 	utils.BuildAnnotationsGenBehavior,
 	utils.BuildAnnotationsGenAddHashSuffix,
+	utils.BuildAnnotationHashFields,
+	utils.BuildAnnotationOriginPath,
 
 	kioutil.PathAnnotation,
 	kioutil.IndexAnnotation,
@@ -57,7 +59,14 @@ func (r *Resource) GetGvk() resid.Gvk {
 	return resid.GvkFromNode(&r.RNode)
 }
 
+// Hash returns a hash of the resource, covering the fields
+// set by EnableHashSuffixOfFields if the hasher supports it.
 func (r *Resource) Hash(h ifc.KustHasher) (string, error) {
+	if paths, found := r.hashFieldPaths(); found {
+		if fh, ok := h.(ifc.KustFieldHasher); ok {
+			return fh.HashFields(&r.RNode, paths)
+		}
+	}
 	return h.Hash(&r.RNode)
 }
 
@@ -93,6 +102,24 @@ func (r *Resource) SetOrigin(origin *Origin) error {
 	return r.SetAnnotations(annotations)
 }
 
+// GetOriginPath returns the path of the file the resource was read from,
+// or of the kustomization file of the generator that made it.
+// It returns an empty string if the origin of the resource is unknown.
+func (r *Resource) GetOriginPath() string {
+	annotations := r.GetAnnotations()
+	if p, ok := annotations[utils.BuildAnnotationOriginPath]; ok {
+		return p
+	}
+	origin, err := r.GetOrigin()
+	if err != nil || origin == nil {
+		return ""
+	}
+	if origin.Path != "" {
+		return origin.Path
+	}
+	return origin.ConfiguredIn
+}
+
 func (r *Resource) GetTransformations() (Transformations, error) {
 	annotations := r.GetAnnotations()
 	transformerAnnotations, ok := annotations[utils.TransformerAnnotationKey]
@@ -423,6 +450,24 @@ func (r *Resource) EnableHashSuffix() {
 	r.enable(utils.BuildAnnotationsGenAddHashSuffix)
 }
 
+// EnableHashSuffixOfFields marks the resource as needing a hash of
+// its kind, name and the fields at paths to be appended to its name.
+// Without paths, all the fields but metadata and status are hashed.
+func (r *Resource) EnableHashSuffixOfFields(paths []string) error {
+	annotations := r.GetAnnotations()
+	annotations[utils.BuildAnnotationsGenAddHashSuffix] = utils.Enabled
+	annotations[utils.BuildAnnotationHashFields] = strings.Join(paths, ",")
+	return r.SetAnnotations(annotations)
+}
+
+func (r *Resource) hashFieldPaths() ([]string, bool) {
+	v, found := r.GetAnnotations()[utils.BuildAnnotationHashFields]
+	if !found || v == "" {
+		return nil, found
+	}
+	return strings.Split(v, ","), true
+}
+
 // OrgId returns the original, immutable ResId for the resource.
 // This doesn't have to be unique in a ResMap.
 func (r *Resource) OrgId() resid.ResId {
@@ -103,6 +103,7 @@ func (th *HarnessEnhanced) MkDir(path string) string {
 }
 
 func (th *HarnessEnhanced) Reset() {
+	_ = th.pl.Cleanup()
 	if th.shouldWipeLdrRoot {
 		root, _ := filepath.EvalSymlinks(th.ldr.Root())
 		tmpdir, _ := filepath.EvalSymlinks(os.TempDir())
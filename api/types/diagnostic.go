@@ -0,0 +1,75 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiagnosticSeverity is the severity of a Diagnostic.
+type DiagnosticSeverity string
+
+const (
+	// DiagnosticError is a problem failing the build.
+	DiagnosticError DiagnosticSeverity = "error"
+	// DiagnosticWarning is a problem the build tolerates,
+	// e.g. a deprecated field.
+	DiagnosticWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic is a problem in a file, at a position if known.
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity" yaml:"severity"`
+
+	// File is the path of the file, if known.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+
+	// Line and Column are the 1-based position of the problem
+	// in the file, or 0 if unknown.
+	Line   int `json:"line,omitempty" yaml:"line,omitempty"`
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+
+	Message string `json:"message" yaml:"message"`
+}
+
+// Position returns the position of the problem, as file:line:column
+// or as much of it as is known.
+func (d Diagnostic) Position() string {
+	switch {
+	case d.File != "" && d.Line > 0:
+		return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	case d.File != "":
+		return d.File
+	case d.Line > 0:
+		return fmt.Sprintf("line %d, column %d", d.Line, d.Column)
+	default:
+		return ""
+	}
+}
+
+// String returns the diagnostic as position: severity: message.
+func (d Diagnostic) String() string {
+	if pos := d.Position(); pos != "" {
+		return fmt.Sprintf("%s: %s: %s", pos, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+}
+
+// DiagnosticsError is the error of diagnostics of severity error.
+type DiagnosticsError struct {
+	Diagnostics []Diagnostic
+}
+
+func (e *DiagnosticsError) Error() string {
+	lines := make([]string, 0, len(e.Diagnostics))
+	for _, d := range e.Diagnostics {
+		if pos := d.Position(); pos != "" {
+			lines = append(lines, pos+": "+d.Message)
+		} else {
+			lines = append(lines, d.Message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
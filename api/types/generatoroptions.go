@@ -18,6 +18,23 @@ type GeneratorOptions struct {
 
 	// Immutable if true add to all generated resources.
 	Immutable bool `json:"immutable,omitempty" yaml:"immutable,omitempty"`
+
+	// HashSuffixTargets selects resources, other than generated ones, whose
+	// names get a suffix that is a hash of their content, like generated
+	// ConfigMaps and Secrets.  Only used in the generatorOptions of a
+	// kustomization, not in the options of a generator.
+	HashSuffixTargets []HashSuffixTarget `json:"hashSuffixTargets,omitempty" yaml:"hashSuffixTargets,omitempty"`
+}
+
+// HashSuffixTarget selects resources whose names get a content hash suffix.
+type HashSuffixTarget struct {
+	// Target selects the resources.
+	Target *Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// FieldPaths are the paths of the fields hashed, along with the kind
+	// and name of a resource, e.g. `spec/template`.  Defaults to all the
+	// fields other than metadata and status.
+	FieldPaths []string `json:"fieldPaths,omitempty" yaml:"fieldPaths,omitempty"`
 }
 
 // MergeGlobalOptionsIntoLocal merges two instances of GeneratorOptions.
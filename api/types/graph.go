@@ -0,0 +1,174 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GraphNodeType is the type of a node of a Graph.
+type GraphNodeType string
+
+const (
+	// GraphNodeKustomization is a directory with a Kustomization.
+	GraphNodeKustomization GraphNodeType = "kustomization"
+	// GraphNodeComponent is a directory with a Component.
+	GraphNodeComponent GraphNodeType = "component"
+	// GraphNodeRemote is a remote file or git repository.
+	GraphNodeRemote GraphNodeType = "remote"
+	// GraphNodeHelmChart is a Helm chart, in a repository
+	// or in the chart home of a kustomization.
+	GraphNodeHelmChart GraphNodeType = "helmChart"
+	// GraphNodeFile is a local file or directory that
+	// isn't a kustomization, e.g. a resource or a patch.
+	GraphNodeFile GraphNodeType = "file"
+)
+
+// GraphNode is a node of a Graph.
+type GraphNode struct {
+	// ID is the absolute path of a local node,
+	// or the URL or reference of a remote one.
+	ID   string        `json:"id" yaml:"id"`
+	Type GraphNodeType `json:"type" yaml:"type"`
+}
+
+// GraphEdge is an edge of a Graph, from a kustomization
+// to a node it refers to in one of its fields.
+type GraphEdge struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+
+	// Field is the name of the field of the
+	// kustomization referring to the node,
+	// e.g. resources or configMapGenerator.
+	Field string `json:"field" yaml:"field"`
+}
+
+// Graph is the dependency graph of a kustomization:
+// the kustomizations, components, remote references,
+// Helm charts and files it refers to, directly or not.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes" yaml:"nodes"`
+	Edges []GraphEdge `json:"edges" yaml:"edges"`
+
+	// nodes are the indexes of the nodes by ID.
+	nodes map[string]int
+	// edges are the edges already added.
+	edges map[GraphEdge]bool
+}
+
+// AddNode adds the node of the given ID and type, unless
+// the graph already has a node of that ID.
+func (g *Graph) AddNode(id string, t GraphNodeType) {
+	if g.nodes == nil {
+		g.index()
+	}
+	if _, found := g.nodes[id]; found {
+		return
+	}
+	g.nodes[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, GraphNode{ID: id, Type: t})
+}
+
+// AddEdge adds the edge, unless the graph already has it.
+func (g *Graph) AddEdge(from, to, field string) {
+	if g.edges == nil {
+		g.index()
+	}
+	e := GraphEdge{From: from, To: to, Field: field}
+	if g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// Node returns the node of the given ID, if any.
+func (g *Graph) Node(id string) (GraphNode, bool) {
+	if g.nodes == nil {
+		g.index()
+	}
+	i, found := g.nodes[id]
+	if !found {
+		return GraphNode{}, false
+	}
+	return g.Nodes[i], true
+}
+
+// index indexes the nodes and edges, e.g. of an unmarshalled graph.
+func (g *Graph) index() {
+	g.nodes = make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		g.nodes[n.ID] = i
+	}
+	g.edges = make(map[GraphEdge]bool, len(g.Edges))
+	for _, e := range g.Edges {
+		g.edges[e] = true
+	}
+}
+
+// Reverse returns the graph with the edges reversed, from
+// the nodes to the kustomizations referring to them.
+func (g *Graph) Reverse() *Graph {
+	r := &Graph{Nodes: append([]GraphNode{}, g.Nodes...)}
+	for _, e := range g.Edges {
+		r.Edges = append(r.Edges, GraphEdge{From: e.To, To: e.From, Field: e.Field})
+	}
+	r.index()
+	return r
+}
+
+// Dependents returns the IDs, sorted, of the nodes referring
+// to any of the nodes of the given IDs, directly or not.
+func (g *Graph) Dependents(ids ...string) []string {
+	referrers := map[string][]string{}
+	for _, e := range g.Edges {
+		referrers[e.To] = append(referrers[e.To], e.From)
+	}
+	found := map[string]bool{}
+	queue := append([]string{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, r := range referrers[id] {
+			if !found[r] {
+				found[r] = true
+				queue = append(queue, r)
+			}
+		}
+	}
+	result := make([]string, 0, len(found))
+	for id := range found {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// graphNodeShapes are the shapes of the types of nodes in DOT.
+var graphNodeShapes = map[GraphNodeType]string{
+	GraphNodeKustomization: "box",
+	GraphNodeComponent:     "component",
+	GraphNodeRemote:        "box3d",
+	GraphNodeHelmChart:     "folder",
+	GraphNodeFile:          "note",
+}
+
+// DOT returns the graph in the DOT language of Graphviz.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph kustomize {\n")
+	for _, n := range g.Nodes {
+		b.WriteString("  " + strconv.Quote(n.ID) +
+			" [shape=" + graphNodeShapes[n.Type] + "];\n")
+	}
+	for _, e := range g.Edges {
+		b.WriteString("  " + strconv.Quote(e.From) + " -> " + strconv.Quote(e.To) +
+			" [label=" + strconv.Quote(e.Field) + "];\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
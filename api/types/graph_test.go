@@ -0,0 +1,79 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/api/types"
+)
+
+func makeTestGraph() *Graph {
+	g := &Graph{}
+	g.AddNode("/overlay", GraphNodeKustomization)
+	g.AddNode("/base", GraphNodeKustomization)
+	g.AddNode("/base", GraphNodeFile)
+	g.AddNode("/base/cm.env", GraphNodeFile)
+	g.AddNode("/component", GraphNodeComponent)
+	g.AddEdge("/overlay", "/base", "resources")
+	g.AddEdge("/overlay", "/component", "components")
+	g.AddEdge("/base", "/base/cm.env", "configMapGenerator")
+	g.AddEdge("/base", "/base/cm.env", "configMapGenerator")
+	return g
+}
+
+func TestGraphAdd(t *testing.T) {
+	g := makeTestGraph()
+	assert.Len(t, g.Nodes, 4)
+	assert.Len(t, g.Edges, 3)
+	n, found := g.Node("/base")
+	require.True(t, found)
+	assert.Equal(t, GraphNodeKustomization, n.Type)
+	_, found = g.Node("/missing")
+	assert.False(t, found)
+}
+
+func TestGraphUnmarshalled(t *testing.T) {
+	data, err := json.Marshal(makeTestGraph())
+	require.NoError(t, err)
+	var g Graph
+	require.NoError(t, json.Unmarshal(data, &g))
+	g.AddEdge("/base", "/base/cm.env", "configMapGenerator")
+	assert.Len(t, g.Edges, 3)
+	_, found := g.Node("/component")
+	assert.True(t, found)
+}
+
+func TestGraphReverse(t *testing.T) {
+	r := makeTestGraph().Reverse()
+	assert.Equal(t, []GraphEdge{
+		{From: "/base", To: "/overlay", Field: "resources"},
+		{From: "/component", To: "/overlay", Field: "components"},
+		{From: "/base/cm.env", To: "/base", Field: "configMapGenerator"},
+	}, r.Edges)
+	assert.Equal(t, []string{"/base/cm.env"}, r.Dependents("/base"))
+}
+
+func TestGraphDependents(t *testing.T) {
+	g := makeTestGraph()
+	assert.Equal(t, []string{"/base", "/overlay"}, g.Dependents("/base/cm.env"))
+	assert.Equal(t, []string{"/overlay"}, g.Dependents("/component", "/base"))
+	assert.Empty(t, g.Dependents("/overlay"))
+}
+
+func TestGraphDOT(t *testing.T) {
+	assert.Equal(t, `digraph kustomize {
+  "/overlay" [shape=box];
+  "/base" [shape=box];
+  "/base/cm.env" [shape=note];
+  "/component" [shape=component];
+  "/overlay" -> "/base" [label="resources"];
+  "/overlay" -> "/component" [label="components"];
+  "/base" -> "/base/cm.env" [label="configMapGenerator"];
+}
+`, makeTestGraph().DOT())
+}
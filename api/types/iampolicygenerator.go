@@ -5,18 +5,30 @@ package types
 
 type Cloud string
 
-const GKE Cloud = "gke"
+const (
+	GKE Cloud = "gke"
+	EKS Cloud = "eks"
+	AKS Cloud = "aks"
+)
 
-// IAMPolicyGeneratorArgs contains arguments to generate a GKE service account resource.
+// IAMPolicyGeneratorArgs contains arguments to generate a service account
+// resource bound to a cloud provider identity: a GKE service account,
+// an AWS IAM role for EKS, or an Azure managed identity for AKS.
 type IAMPolicyGeneratorArgs struct {
-	// which cloud provider to generate for (e.g. "gke")
+	// which cloud provider to generate for (e.g. "gke", "eks" or "aks")
 	Cloud `json:"cloud" yaml:"cloud"`
 
 	// information about the kubernetes cluster for this object
 	KubernetesService `json:"kubernetesService" yaml:"kubernetesService"`
 
-	// information about the service account and project
+	// information about the service account and project, for gke
 	ServiceAccount `json:"serviceAccount" yaml:"serviceAccount"`
+
+	// information about the IAM role, for eks
+	AWS *AWSRole `json:"aws,omitempty" yaml:"aws,omitempty"`
+
+	// information about the managed identity, for aks
+	Azure *AzureIdentity `json:"azure,omitempty" yaml:"azure,omitempty"`
 }
 
 type KubernetesService struct {
@@ -34,3 +46,28 @@ type ServiceAccount struct {
 	// The ID of the project
 	ProjectId string `json:"projectId" yaml:"projectId"`
 }
+
+// AWSRole is an IAM role assumed by the pods using the Kubernetes
+// service account, with IAM roles for service accounts (IRSA).
+type AWSRole struct {
+	// the ID of the AWS account of the role
+	AccountId string `json:"accountId" yaml:"accountId"`
+
+	// the name of the role, including its path if any
+	RoleName string `json:"roleName" yaml:"roleName"`
+
+	// the partition of the account, "aws" by default
+	Partition string `json:"partition,omitempty" yaml:"partition,omitempty"`
+}
+
+// AzureIdentity is a managed identity or application used by the pods
+// using the Kubernetes service account, with Azure Workload Identity.
+// The pods must have the azure.workload.identity/use: "true" label,
+// which the generator doesn't add, since it doesn't see them.
+type AzureIdentity struct {
+	// the client ID of the identity
+	ClientId string `json:"clientId" yaml:"clientId"`
+
+	// the ID of the tenant of the identity, the tenant of the cluster by default
+	TenantId string `json:"tenantId,omitempty" yaml:"tenantId,omitempty"`
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build ignore
+
+// This program generates the JSON Schemas of kustomization files.
+// Run it with go generate.
+package main
+
+import (
+	"log"
+	"os"
+
+	"sigs.k8s.io/kustomize/api/internal/schemagen"
+)
+
+func main() {
+	kustomization, component, err := schemagen.KustomizationSchemas("..")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile("kustomization.json", kustomization, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile("component.json", component, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonschema holds the JSON Schemas of kustomization files,
+// for editors and validators.  They are generated from the Go types
+// of the types package, with their doc comments as descriptions.
+package jsonschema
+
+import (
+	_ "embed"
+	"slices"
+)
+
+//go:generate go run gen.go
+
+//go:embed kustomization.json
+var kustomization []byte
+
+//go:embed component.json
+var component []byte
+
+// Kustomization returns the JSON Schema of kustomization
+// files of kind Kustomization.
+func Kustomization() []byte {
+	return slices.Clone(kustomization)
+}
+
+// Component returns the JSON Schema of kustomization
+// files of kind Component.
+func Component() []byte {
+	return slices.Clone(component)
+}
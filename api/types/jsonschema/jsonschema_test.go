@@ -0,0 +1,24 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/internal/schemagen"
+	"sigs.k8s.io/kustomize/api/types/jsonschema"
+)
+
+// TestSchemasMatchTypes fails when the types and the schemas
+// disagree.  Run go generate to update the schemas.
+func TestSchemasMatchTypes(t *testing.T) {
+	kustomization, component, err := schemagen.KustomizationSchemas("..")
+	require.NoError(t, err)
+	assert.Equal(t, string(kustomization), string(jsonschema.Kustomization()),
+		"kustomization.json is out of date, run go generate in api/types/jsonschema")
+	assert.Equal(t, string(component), string(jsonschema.Component()),
+		"component.json is out of date, run go generate in api/types/jsonschema")
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// KeyRemoval selects label or annotation keys to remove.
+// A key is removed if it matches any of Keys, Prefixes or Regexes.
+type KeyRemoval struct {
+	// Keys to remove, e.g. app.kubernetes.io/managed-by.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// Prefixes of the keys to remove, e.g. helm.sh/.
+	Prefixes []string `json:"prefixes,omitempty" yaml:"prefixes,omitempty"`
+	// Regexes matching the whole keys to remove, e.g. .*\.example\.com/.*.
+	Regexes []string `json:"regexes,omitempty" yaml:"regexes,omitempty"`
+	// FieldSpecs are additional fields to remove the keys from, such as the
+	// labels of custom resources. They are merged with the builtin fieldSpecs.
+	FieldSpecs []FieldSpec `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// IsEmpty returns true if no keys are selected for removal.
+func (r *KeyRemoval) IsEmpty() bool {
+	return r == nil ||
+		len(r.Keys) == 0 && len(r.Prefixes) == 0 && len(r.Regexes) == 0
+}
@@ -4,14 +4,10 @@
 package types
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"reflect"
 
-	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -66,6 +62,14 @@ type Kustomization struct {
 	// CommonAnnotations to add to all objects.
 	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty" yaml:"commonAnnotations,omitempty"`
 
+	// RemoveLabels selects labels to remove from all objects,
+	// including from selectors and pod templates.
+	RemoveLabels *KeyRemoval `json:"removeLabels,omitempty" yaml:"removeLabels,omitempty"`
+
+	// RemoveAnnotations selects annotations to remove from all objects,
+	// including from pod templates.
+	RemoveAnnotations *KeyRemoval `json:"removeAnnotations,omitempty" yaml:"removeAnnotations,omitempty"`
+
 	// Deprecated: Use the Patches field instead, which provides a superset of the functionality of PatchesStrategicMerge.
 	// PatchesStrategicMerge specifies the relative path to a file
 	// containing a strategic merge patch.  Format documented at
@@ -193,26 +197,45 @@ const (
 	deprecatedCommonLabelsWarningMessage       = "# Warning: 'commonLabels' is deprecated. Please use 'labels' instead." + " " + deprecatedWarningToRunEditFix
 )
 
+// deprecatedFields are the deprecated fields of a Kustomization, with
+// their warnings and whether a Kustomization sets them.
+var deprecatedFields = []struct {
+	name    string
+	message string
+	isSet   func(k *Kustomization) bool
+}{
+	{"bases", deprecatedBaseWarningMessage,
+		func(k *Kustomization) bool { return k.Bases != nil }},
+	{"commonLabels", deprecatedCommonLabelsWarningMessage,
+		func(k *Kustomization) bool { return k.CommonLabels != nil }},
+	{"imageTags", deprecatedImageTagsWarningMessage,
+		func(k *Kustomization) bool { return k.ImageTags != nil }},
+	{"patchesJson6902", deprecatedPatchesJson6902Message,
+		func(k *Kustomization) bool { return k.PatchesJson6902 != nil }},
+	{"patchesStrategicMerge", deprecatedPatchesStrategicMergeMessage,
+		func(k *Kustomization) bool { return k.PatchesStrategicMerge != nil }},
+	{"vars", deprecatedVarsMessage,
+		func(k *Kustomization) bool { return k.Vars != nil }},
+}
+
+// deprecatedFieldMessage returns the warning of the field of
+// a Kustomization with the given name, if it's deprecated.
+func deprecatedFieldMessage(name string) (string, bool) {
+	for _, f := range deprecatedFields {
+		if f.name == name {
+			return f.message, true
+		}
+	}
+	return "", false
+}
+
 // CheckDeprecatedFields check deprecated field is used or not.
 func (k *Kustomization) CheckDeprecatedFields() *[]string {
 	var warningMessages []string
-	if k.Bases != nil {
-		warningMessages = append(warningMessages, deprecatedBaseWarningMessage)
-	}
-	if k.CommonLabels != nil {
-		warningMessages = append(warningMessages, deprecatedCommonLabelsWarningMessage)
-	}
-	if k.ImageTags != nil {
-		warningMessages = append(warningMessages, deprecatedImageTagsWarningMessage)
-	}
-	if k.PatchesJson6902 != nil {
-		warningMessages = append(warningMessages, deprecatedPatchesJson6902Message)
-	}
-	if k.PatchesStrategicMerge != nil {
-		warningMessages = append(warningMessages, deprecatedPatchesStrategicMergeMessage)
-	}
-	if k.Vars != nil {
-		warningMessages = append(warningMessages, deprecatedVarsMessage)
+	for _, f := range deprecatedFields {
+		if f.isSet(k) {
+			warningMessages = append(warningMessages, f.message)
+		}
 	}
 	return &warningMessages
 }
@@ -336,22 +359,13 @@ func (k *Kustomization) EnforceFields() []string {
 	return errs
 }
 
-// Unmarshal replace k with the content in YAML input y
+// Unmarshal replace k with the content in YAML input y.
+// See DecodeKustomization for the errors it returns.
 func (k *Kustomization) Unmarshal(y []byte) error {
-	// TODO: switch to strict decoding to catch duplicate keys.
-	// We can't do so until there is a yaml decoder that supports anchors AND case-insensitive keys.
-	// See https://github.com/nholuongut/kustomize/issues/5061
-	j, err := yaml.YAMLToJSON(y)
-	if err != nil {
-		return errors.WrapPrefixf(err, "invalid Kustomization")
-	}
-	dec := json.NewDecoder(bytes.NewReader(j))
-	dec.DisallowUnknownFields()
-	var nk Kustomization
-	err = dec.Decode(&nk)
+	nk, _, err := DecodeKustomization(y, "")
 	if err != nil {
-		return errors.WrapPrefixf(err, "invalid Kustomization")
+		return err
 	}
-	*k = nk
+	*k = *nk
 	return nil
 }
@@ -287,7 +287,7 @@ unknown: foo`)
 	if err == nil {
 		t.Fatalf("expect an error")
 	}
-	expect := "invalid Kustomization: json: unknown field \"unknown\""
+	expect := "invalid Kustomization: line 4, column 1: unknown field \"unknown\""
 	if err.Error() != expect {
 		t.Fatalf("expect %v but got: %v", expect, err.Error())
 	}
@@ -304,7 +304,7 @@ func TestUnmarshal_Failed(t *testing.T) {
 			kustomizationYamls: []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
 kind: Kustomization
 unknown`),
-			errMsg: "invalid Kustomization: yaml: line 4: could not find expected ':'",
+			errMsg: "invalid Kustomization: yaml: line 3: could not find expected ':'",
 		},
 	}
 	for _, tt := range tests {
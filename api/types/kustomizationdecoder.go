@@ -0,0 +1,456 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	deprecatedWarningPrefix = "# Warning: "
+	mergeKey                = "<<"
+	nullTag                 = "!!null"
+	mergeTag                = "!!merge"
+	boolTag                 = "!!bool"
+	intTag                  = "!!int"
+	floatTag                = "!!float"
+)
+
+var (
+	kustomizationType = reflect.TypeOf(Kustomization{})
+	anyType           = reflect.TypeOf((*interface{})(nil)).Elem()
+	unmarshalerType   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// DecodeKustomization decodes the content of the kustomization file
+// at path, which is only used to position the diagnostics.
+//
+// Unlike a plain YAML to JSON conversion, it rejects duplicate keys,
+// including keys naming the same field in different cases, while
+// supporting anchors, aliases and merge keys.  The errors are a
+// *DiagnosticsError of the problems positioned in the file, with
+// suggestions for misspelled fields.  The returned diagnostics are
+// the warnings about the file, e.g. about deprecated fields.
+func DecodeKustomization(content []byte, path string) (*Kustomization, []Diagnostic, error) {
+	var doc kyaml.Node
+	if err := kyaml.NewDecoder(bytes.NewReader(content)).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return &Kustomization{}, nil, nil
+		}
+		return nil, nil, errors.WrapPrefixf(err, "invalid Kustomization")
+	}
+	if len(doc.Content) == 0 {
+		return &Kustomization{}, nil, nil
+	}
+	d := &kustomizationDecoder{path: path, visited: map[visit]bool{}}
+	d.walk(doc.Content[0], kustomizationType, "")
+	if len(d.errors) > 0 {
+		return nil, d.warnings, errors.WrapPrefixf(
+			&DiagnosticsError{Diagnostics: d.errors}, "invalid Kustomization")
+	}
+	// the nodes are valid, decode them the way kustomize always has,
+	// through JSON
+	v, err := d.jsonValue(doc.Content[0])
+	if err != nil {
+		return nil, d.warnings, errors.WrapPrefixf(err, "invalid Kustomization")
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, d.warnings, errors.WrapPrefixf(err, "invalid Kustomization")
+	}
+	dec := json.NewDecoder(bytes.NewReader(j))
+	dec.DisallowUnknownFields()
+	var k Kustomization
+	if err = dec.Decode(&k); err != nil {
+		return nil, d.warnings, errors.WrapPrefixf(err, "invalid Kustomization")
+	}
+	return &k, d.warnings, nil
+}
+
+// visit is a node walked as a type.
+type visit struct {
+	node *kyaml.Node
+	t    reflect.Type
+}
+
+// kustomizationDecoder walks the nodes of a kustomization file
+// as the Go types they decode to, collecting diagnostics.
+type kustomizationDecoder struct {
+	path     string
+	errors   []Diagnostic
+	warnings []Diagnostic
+	// visited avoids walking aliased nodes more than once.
+	visited map[visit]bool
+}
+
+func (d *kustomizationDecoder) diagnose(
+	severity DiagnosticSeverity, n *kyaml.Node, format string, args ...interface{}) {
+	diag := Diagnostic{
+		Severity: severity,
+		File:     d.path,
+		Line:     n.Line,
+		Column:   n.Column,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if severity == DiagnosticError {
+		d.errors = append(d.errors, diag)
+	} else {
+		d.warnings = append(d.warnings, diag)
+	}
+}
+
+// walk checks the node n, at the field path p, decodes to the type t.
+func (d *kustomizationDecoder) walk(n *kyaml.Node, t reflect.Type, p string) {
+	for n.Kind == kyaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind == kyaml.ScalarNode && n.Tag == nullTag {
+		return
+	}
+	if d.visited[visit{node: n, t: t}] {
+		return
+	}
+	d.visited[visit{node: n, t: t}] = true
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		// the type decodes itself, only check for duplicate keys
+		t = anyType
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		d.walkAny(n, p)
+	case reflect.Struct:
+		if d.expect(n, kyaml.MappingNode, p) {
+			d.walkStruct(n, t, p)
+		}
+	case reflect.Map:
+		if d.expect(n, kyaml.MappingNode, p) {
+			d.walkMap(n, t.Elem(), p)
+		}
+	case reflect.Slice, reflect.Array:
+		if d.expect(n, kyaml.SequenceNode, p) {
+			for i, item := range n.Content {
+				d.walk(item, t.Elem(), fmt.Sprintf("%s[%d]", p, i))
+			}
+		}
+	default:
+		d.expect(n, kyaml.ScalarNode, p)
+	}
+}
+
+// expect checks the node n, at the field path p, is of the given kind.
+func (d *kustomizationDecoder) expect(n *kyaml.Node, kind kyaml.Kind, p string) bool {
+	if n.Kind == kind {
+		return true
+	}
+	if p == "" {
+		d.diagnose(DiagnosticError, n, "expected %s, got %s", kindName(kind), kindName(n.Kind))
+	} else {
+		d.diagnose(DiagnosticError, n, "%s: expected %s, got %s", p, kindName(kind), kindName(n.Kind))
+	}
+	return false
+}
+
+func kindName(kind kyaml.Kind) string {
+	switch kind {
+	case kyaml.MappingNode:
+		return "a mapping"
+	case kyaml.SequenceNode:
+		return "a sequence"
+	default:
+		return "a scalar"
+	}
+}
+
+func (d *kustomizationDecoder) walkAny(n *kyaml.Node, p string) {
+	switch n.Kind {
+	case kyaml.MappingNode:
+		d.walkMap(n, anyType, p)
+	case kyaml.SequenceNode:
+		for i, item := range n.Content {
+			d.walk(item, anyType, fmt.Sprintf("%s[%d]", p, i))
+		}
+	default:
+	}
+}
+
+func (d *kustomizationDecoder) walkMap(n *kyaml.Node, elem reflect.Type, p string) {
+	seen := map[string]*kyaml.Node{}
+	for _, e := range d.mappingEntries(n, p) {
+		if first, found := seen[e.key.Value]; found {
+			if !e.merged {
+				d.duplicate(e.key, first, e.key.Value, p)
+			}
+			continue
+		}
+		seen[e.key.Value] = e.key
+		d.walk(e.value, elem, fieldPath(p, e.key.Value))
+	}
+}
+
+func (d *kustomizationDecoder) walkStruct(n *kyaml.Node, t reflect.Type, p string) {
+	fields := jsonFields(t)
+	seen := map[string]*kyaml.Node{}
+	for _, e := range d.mappingEntries(n, p) {
+		f := lookupField(fields, e.key.Value)
+		if f == nil {
+			if !e.merged {
+				d.unknown(e.key, fields, p)
+			}
+			continue
+		}
+		if first, found := seen[f.name]; found {
+			if !e.merged {
+				d.duplicate(e.key, first, f.name, p)
+			}
+			continue
+		}
+		seen[f.name] = e.key
+		if msg, deprecated := deprecatedFieldMessage(f.name); deprecated &&
+			t == kustomizationType && p == "" && e.value.Tag != nullTag {
+			d.diagnose(DiagnosticWarning, e.key, "%s",
+				strings.TrimPrefix(msg, deprecatedWarningPrefix))
+		}
+		d.walk(e.value, f.typ, fieldPath(p, f.name))
+	}
+}
+
+func (d *kustomizationDecoder) duplicate(key, first *kyaml.Node, name, p string) {
+	d.diagnose(DiagnosticError, key, "duplicate field %q%s, already defined at line %d",
+		name, inPath(p), first.Line)
+}
+
+func (d *kustomizationDecoder) unknown(key *kyaml.Node, fields []jsonField, p string) {
+	names := make([]string, len(fields))
+	for i := range fields {
+		names[i] = fields[i].name
+	}
+	if suggestion := suggest(key.Value, names); suggestion != "" {
+		d.diagnose(DiagnosticError, key, "unknown field %q%s, did you mean %q?",
+			key.Value, inPath(p), suggestion)
+		return
+	}
+	d.diagnose(DiagnosticError, key, "unknown field %q%s", key.Value, inPath(p))
+}
+
+func inPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	return " in " + p
+}
+
+func fieldPath(p, name string) string {
+	if p == "" {
+		return name
+	}
+	return p + "." + name
+}
+
+// jsonValue returns the value of the node n, as converted to JSON,
+// following aliases and merge keys.
+func (d *kustomizationDecoder) jsonValue(n *kyaml.Node) (interface{}, error) {
+	for n.Kind == kyaml.AliasNode {
+		n = n.Alias
+	}
+	switch n.Kind {
+	case kyaml.MappingNode:
+		m := map[string]interface{}{}
+		for _, e := range d.mappingEntries(n, "") {
+			if _, found := m[e.key.Value]; found {
+				// overridden
+				continue
+			}
+			v, err := d.jsonValue(e.value)
+			if err != nil {
+				return nil, err
+			}
+			m[e.key.Value] = v
+		}
+		return m, nil
+	case kyaml.SequenceNode:
+		s := make([]interface{}, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := d.jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	default:
+		switch n.ShortTag() {
+		case nullTag:
+			return nil, nil
+		case boolTag, intTag, floatTag:
+			var v interface{}
+			if err := n.Decode(&v); err != nil {
+				return nil, errors.Wrap(err)
+			}
+			return v, nil
+		default:
+			// strings, and timestamps as written
+			return n.Value, nil
+		}
+	}
+}
+
+// entry is a key and value of a mapping.
+type entry struct {
+	key, value *kyaml.Node
+	// merged entries come from merge keys, and are
+	// overridden by the entries of the mapping itself.
+	merged bool
+}
+
+// mappingEntries returns the entries of the mapping n, followed by
+// the entries merged into it in order of precedence.
+func (d *kustomizationDecoder) mappingEntries(n *kyaml.Node, p string) []entry {
+	var entries, merged []entry
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Tag != mergeTag {
+			entries = append(entries, entry{key: key, value: value})
+			continue
+		}
+		for value.Kind == kyaml.AliasNode {
+			value = value.Alias
+		}
+		sources := []*kyaml.Node{value}
+		if value.Kind == kyaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			for source.Kind == kyaml.AliasNode {
+				source = source.Alias
+			}
+			if source.Kind != kyaml.MappingNode {
+				d.diagnose(DiagnosticError, source, "%s: expected a mapping to merge, got %s",
+					fieldPath(p, mergeKey), kindName(source.Kind))
+				continue
+			}
+			for _, e := range d.mappingEntries(source, p) {
+				e.merged = true
+				merged = append(merged, e)
+			}
+		}
+	}
+	return append(entries, merged...)
+}
+
+// jsonField is a field of a struct, as encoded by encoding/json.
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonFields returns the fields of the struct t, following the
+// rules of encoding/json: the fields of embedded structs without
+// a JSON name are promoted, unless shadowed.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	names := map[string]bool{}
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded = append(embedded, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+		fields = append(fields, jsonField{name: name, typ: f.Type})
+	}
+	for _, e := range embedded {
+		for _, f := range jsonFields(e) {
+			if !names[f.name] {
+				names[f.name] = true
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// lookupField returns the field of the given key, like encoding/json,
+// preferring an exact match to a case-insensitive one.
+func lookupField(fields []jsonField, key string) *jsonField {
+	var folded *jsonField
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+		if folded == nil && strings.EqualFold(fields[i].name, key) {
+			folded = &fields[i]
+		}
+	}
+	return folded
+}
+
+// minContainedLength is the minimal length of a misspelled
+// name to suggest the names containing it.
+const minContainedLength = 3
+
+// suggest returns the name closest to the misspelled name, if any
+// is close enough.
+func suggest(name string, names []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range names {
+		lower, lowerCandidate := strings.ToLower(name), strings.ToLower(candidate)
+		distance := levenshtein(lower, lowerCandidate)
+		similar := distance <= max(1, len(candidate)/3) ||
+			(len(name) >= minContainedLength && strings.Contains(lowerCandidate, lower)) ||
+			(len(candidate) >= minContainedLength && strings.Contains(lower, lowerCandidate))
+		if similar && (bestDistance < 0 || distance < bestDistance) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/api/types"
+)
+
+func TestDecodeKustomization(t *testing.T) {
+	k, diags, err := DecodeKustomization([]byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+commonAnnotations: &annotations
+  team: a
+labels:
+- pairs: *annotations
+patches:
+- &patch
+  path: patch.yaml
+  target: &target
+    kind: Deployment
+- <<: *patch
+  target:
+    <<: *target
+    name: b
+`), "kustomization.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+	assert.Equal(t, map[string]string{"team": "a"}, k.CommonAnnotations)
+	assert.Equal(t, map[string]string{"team": "a"}, k.Labels[0].Pairs)
+	require.Len(t, k.Patches, 2)
+	assert.Equal(t, "patch.yaml", k.Patches[1].Path)
+	assert.Equal(t, "Deployment", k.Patches[1].Target.Kind)
+	assert.Equal(t, "b", k.Patches[1].Target.Name)
+}
+
+func TestDecodeKustomizationScalars(t *testing.T) {
+	k, _, err := DecodeKustomization([]byte(`
+commonAnnotations:
+  date: 2001-12-14
+  version: "1.10"
+replicas:
+- name: app
+  count: 3
+generatorOptions:
+  disableNameSuffixHash: true
+`), "kustomization.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"date": "2001-12-14", "version": "1.10"}, k.CommonAnnotations)
+	assert.Equal(t, int64(3), k.Replicas[0].Count)
+	assert.True(t, k.GeneratorOptions.DisableNameSuffixHash)
+
+	_, _, err = DecodeKustomization([]byte("namePrefix: 1\n"), "kustomization.yaml")
+	require.Error(t, err)
+}
+
+func TestDecodeKustomizationEmpty(t *testing.T) {
+	k, diags, err := DecodeKustomization(nil, "kustomization.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+	assert.Equal(t, &Kustomization{}, k)
+}
+
+func TestDecodeKustomizationCaseInsensitiveFields(t *testing.T) {
+	k, _, err := DecodeKustomization([]byte(`
+Resources:
+- a.yaml
+patchesJSON6902:
+- path: patch.yaml
+`), "kustomization.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.yaml"}, k.Resources)
+	assert.Len(t, k.PatchesJson6902, 1)
+}
+
+func TestDecodeKustomizationErrors(t *testing.T) {
+	testCases := map[string]struct {
+		content  string
+		expected []Diagnostic
+	}{
+		"duplicate field": {
+			content: `resources:
+- a.yaml
+namePrefix: a-
+resources:
+- b.yaml
+`,
+			expected: []Diagnostic{{Line: 4, Column: 1,
+				Message: `duplicate field "resources", already defined at line 1`}},
+		},
+		"duplicate field in different cases": {
+			content: `resources:
+- a.yaml
+Resources:
+- b.yaml
+`,
+			expected: []Diagnostic{{Line: 3, Column: 1,
+				Message: `duplicate field "resources", already defined at line 1`}},
+		},
+		"duplicate map key": {
+			content: `commonAnnotations:
+  a: b
+  a: c
+`,
+			expected: []Diagnostic{{Line: 3, Column: 3,
+				Message: `duplicate field "a" in commonAnnotations, already defined at line 2`}},
+		},
+		"misspelled field": {
+			content: `nameSufix: -a
+`,
+			expected: []Diagnostic{{Line: 1, Column: 1,
+				Message: `unknown field "nameSufix", did you mean "nameSuffix"?`}},
+		},
+		"partial field": {
+			content: `suffix: -a
+`,
+			expected: []Diagnostic{{Line: 1, Column: 1,
+				Message: `unknown field "suffix", did you mean "nameSuffix"?`}},
+		},
+		"unknown nested field": {
+			content: `patches:
+- path: patch.yaml
+  target:
+    knd: Deployment
+    unrelated: true
+`,
+			expected: []Diagnostic{
+				{Line: 4, Column: 5,
+					Message: `unknown field "knd" in patches[0].target, did you mean "kind"?`},
+				{Line: 5, Column: 5,
+					Message: `unknown field "unrelated" in patches[0].target`},
+			},
+		},
+		"wrong kind of node": {
+			content: `resources: a.yaml
+namespace:
+- a
+`,
+			expected: []Diagnostic{
+				{Line: 1, Column: 12, Message: `resources: expected a sequence, got a scalar`},
+				{Line: 3, Column: 1, Message: `namespace: expected a scalar, got a sequence`},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := DecodeKustomization([]byte(tc.content), "kustomization.yaml")
+			require.Error(t, err)
+			var diagsErr *DiagnosticsError
+			require.True(t, errors.As(err, &diagsErr), err.Error())
+			for i := range tc.expected {
+				tc.expected[i].Severity = DiagnosticError
+				tc.expected[i].File = "kustomization.yaml"
+			}
+			assert.Equal(t, tc.expected, diagsErr.Diagnostics)
+		})
+	}
+}
+
+func TestDecodeKustomizationErrorMessage(t *testing.T) {
+	_, _, err := DecodeKustomization([]byte(`namePrefix: a-
+nameprefix: b-
+`), "overlay/kustomization.yaml")
+	require.EqualError(t, err, "invalid Kustomization: overlay/kustomization.yaml:2:1: "+
+		`duplicate field "namePrefix", already defined at line 1`)
+}
+
+func TestDecodeKustomizationDeprecatedFields(t *testing.T) {
+	_, diags, err := DecodeKustomization([]byte(`bases:
+- base
+imageTags:
+vars: []
+`), "kustomization.yaml")
+	require.NoError(t, err)
+	require.Len(t, diags, 2)
+	assert.Equal(t, "kustomization.yaml:1:1: warning: 'bases' is deprecated. "+
+		"Please use 'resources' instead. Run 'kustomize edit fix' to update your Kustomization automatically.",
+		diags[0].String())
+	assert.Equal(t, DiagnosticWarning, diags[1].Severity)
+	assert.Equal(t, 4, diags[1].Line)
+	assert.Contains(t, diags[1].Message, "'vars' is deprecated")
+}
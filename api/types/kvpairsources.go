@@ -33,4 +33,41 @@ type KvPairSources struct {
 	// On edits (e.g. `kustomize fix`) this is merged into the plural form
 	// for consistency with LiteralSources and FileSources.
 	EnvSource string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// StructuredSources is a list of structured files
+	// whose leaves are projected into key value pairs.
+	StructuredSources []StructuredSource `json:"structured,omitempty" yaml:"structured,omitempty"`
+}
+
+// StructuredSource reads a YAML, JSON, TOML or properties
+// file and emits one key value pair per leaf value.
+// The key of a leaf is the path to it from the root
+// of the file (or of SubPath), e.g. given
+//
+//	database:
+//	  host: db
+//	  ports: [5432, 5433]
+//
+// the pairs are `database.host=db`, `database.ports.0=5432`
+// and `database.ports.1=5433`.  A YAML file must hold a
+// single document.  TOML dates and times are in RFC 3339
+// format.
+type StructuredSource struct {
+	// Path of the file.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Format of the file, one of 'yaml', 'json', 'toml'
+	// or 'properties'. Defaults to the format matching
+	// the extension of Path.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// SubPath selects the map or list to project,
+	// e.g. `database` projects `host=db`, `ports.0=5432`
+	// and `ports.1=5433` from the file above.
+	// Path elements are separated by '.'.
+	SubPath string `json:"subPath,omitempty" yaml:"subPath,omitempty"`
+
+	// Separator joins the path elements of a key.
+	// Defaults to '.', e.g. '__' yields `database__host`.
+	Separator string `json:"separator,omitempty" yaml:"separator,omitempty"`
 }
@@ -26,7 +26,7 @@ type Patch struct {
 // Equals return true if p equals o.
 func (p *Patch) Equals(o Patch) bool {
 	targetEqual := (p.Target == o.Target) ||
-		(p.Target != nil && o.Target != nil && *p.Target == *o.Target)
+		(p.Target != nil && o.Target != nil && reflect.DeepEqual(*p.Target, *o.Target))
 	return p.Path == o.Path &&
 		p.Patch == o.Patch &&
 		targetEqual &&
@@ -0,0 +1,34 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// PluginManifestFileName is the name of the manifest of an Exec,
+// RPC or Go plugin, found in the directory of the plugin.
+const PluginManifestFileName = "plugin.yaml"
+
+// PluginManifestChecksumPrefix prefixes the hex-encoded SHA-256
+// digests of plugin files in manifests.
+const PluginManifestChecksumPrefix = "sha256:"
+
+// PluginManifest describes an Exec, RPC or Go plugin.  The loader
+// refuses to load a plugin that doesn't match its manifest.
+type PluginManifest struct {
+	// Name of the plugin.
+	Name string `json:"name" yaml:"name"`
+
+	// Version of the plugin, a semantic version.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Kinds of the configurations the plugin supports,
+	// any kind if empty.
+	Kinds []string `json:"kinds,omitempty" yaml:"kinds,omitempty"`
+
+	// KustomizeVersion is the range of kustomize versions the plugin
+	// supports, e.g. ">=5.0.0 <6.0.0", any version if empty.
+	KustomizeVersion string `json:"kustomizeVersion,omitempty" yaml:"kustomizeVersion,omitempty"`
+
+	// Checksum of the plugin file, the PluginManifestChecksumPrefix
+	// followed by the hex-encoded SHA-256 digest of the file.
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+}
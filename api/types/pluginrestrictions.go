@@ -3,6 +3,8 @@
 
 package types
 
+import "sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
+
 // Some plugin classes
 // - builtin: plugins defined in the kustomize repo.
 //   May be freely used and re-configured.
@@ -57,4 +59,10 @@ type FnPluginLoadingOptions struct {
 	AsCurrentUser bool
 	// Run in this working directory
 	WorkingDir string
+	// Cache function outputs in this directory
+	CacheDir string
+	// Run exec functions and exec plugins in restricted mode
+	ExecSandbox *sandbox.Config
+	// Container runtime to run functions with, e.g. docker or podman
+	ContainerRuntime string
 }
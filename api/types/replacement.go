@@ -53,7 +53,7 @@ type TargetSelector struct {
 	// Include objects that match this.
 	Select *Selector `json:"select" yaml:"select"`
 
-	// From the allowed set, remove objects that match this.
+	// From the allowed set, remove objects that match any of these.
 	Reject []*Selector `json:"reject,omitempty" yaml:"reject,omitempty"`
 
 	// Structured field paths expected in each allowed object.
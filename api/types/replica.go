@@ -11,6 +11,37 @@ type Replica struct {
 	// The name of the resource to change the replica count
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
+	// Selector selects the resources to change the replica count of.
+	// If both Name and Selector are set, resources must match both.
+	Selector *Selector `json:"selector,omitempty" yaml:"selector,omitempty"`
+
 	// The number of replicas required.
+	// When Adjust, MinCount or MaxCount is set, a Count of zero is ignored.
 	Count int64 `json:"count" yaml:"count"`
+
+	// Adjust changes the replica counts relative to their current values
+	// rather than setting them to Count.  It is one of "+N" or "-N" to add
+	// or remove N replicas, "*N" to multiply them by N, or "N%" to scale
+	// them to N percent, rounded up, e.g. "*2", "+1" or "150%".
+	// The minReplicas and maxReplicas of HorizontalPodAutoscalers are
+	// adjusted too.
+	Adjust string `json:"adjust,omitempty" yaml:"adjust,omitempty"`
+
+	// MinCount and MaxCount set the minReplicas and maxReplicas
+	// of HorizontalPodAutoscalers.
+	MinCount *int64 `json:"minCount,omitempty" yaml:"minCount,omitempty"`
+	MaxCount *int64 `json:"maxCount,omitempty" yaml:"maxCount,omitempty"`
+
+	// FieldPaths are the paths of the fields holding the replica count of
+	// the resources, e.g. spec/size for a custom resource.  They replace
+	// the spec/replicas field of the kinds known to hold replicas, so that
+	// resources of any kind can be selected.
+	FieldPaths []string `json:"fieldPaths,omitempty" yaml:"fieldPaths,omitempty"`
+}
+
+// SetsCount returns true if the replica sets or adjusts the replica count
+// fields of workloads, i.e. unless it only sets the counts of
+// HorizontalPodAutoscalers.
+func (r Replica) SetsCount() bool {
+	return r.Adjust != "" || r.Count != 0 || (r.MinCount == nil && r.MaxCount == nil)
 }
@@ -12,7 +12,9 @@ import (
 
 // Selector specifies a set of resources.
 // Any resource that matches intersection of all conditions
-// is included in this set.
+// is included in this set.  The same rule applies wherever a selector
+// is used, so a reject of a replacement target only excludes the
+// resources matching its ResId and all of its other conditions.
 type Selector struct {
 	// ResId refers to a GVKN/Ns of a resource.
 	resid.ResId `json:",inline,omitempty" yaml:",inline,omitempty"`
@@ -26,27 +28,60 @@ type Selector struct {
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#api
 	// It matches with the resource labels.
 	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
+
+	// FieldSelector is a comma-separated list of field requirements, such as
+	// "spec.type=LoadBalancer,spec.replicas!=1". Each field is a path in the
+	// format used by replacements, and the operators are =, == and !=.
+	// It matches with the values of the resource fields.
+	FieldSelector string `json:"fieldSelector,omitempty" yaml:"fieldSelector,omitempty"`
+
+	// OriginPath is a regex matching the path of the file the resource was
+	// read from, or of the kustomization file of the generator that made it.
+	// The regex matches trailing segments of the path, so "deployment.yaml"
+	// matches both "deployment.yaml" and "../base/deployment.yaml".
+	OriginPath string `json:"originPath,omitempty" yaml:"originPath,omitempty"`
+
+	// Not excludes the resources that match it from the resources
+	// matching the other conditions of the selector.
+	Not *Selector `json:"not,omitempty" yaml:"not,omitempty"`
 }
 
 func (s *Selector) Copy() Selector {
-	return *s
+	c := *s
+	if s.Not != nil {
+		not := s.Not.Copy()
+		c.Not = &not
+	}
+	return c
 }
 
 func (s *Selector) String() string {
-	return fmt.Sprintf(
+	result := fmt.Sprintf(
 		"%s:a=%s:l=%s", s.ResId, s.AnnotationSelector, s.LabelSelector)
+	if s.FieldSelector != "" {
+		result += ":f=" + s.FieldSelector
+	}
+	if s.OriginPath != "" {
+		result += ":o=" + s.OriginPath
+	}
+	if s.Not != nil {
+		result += ":not=(" + s.Not.String() + ")"
+	}
+	return result
 }
 
 // SelectorRegex is a Selector with regex in GVK
 // Any resource that matches intersection of all conditions
 // is included in this set.
 type SelectorRegex struct {
-	selector       *Selector
-	groupRegex     *regexp.Regexp
-	versionRegex   *regexp.Regexp
-	kindRegex      *regexp.Regexp
-	nameRegex      *regexp.Regexp
-	namespaceRegex *regexp.Regexp
+	selector        *Selector
+	groupRegex      *regexp.Regexp
+	versionRegex    *regexp.Regexp
+	kindRegex       *regexp.Regexp
+	nameRegex       *regexp.Regexp
+	namespaceRegex  *regexp.Regexp
+	originPathRegex *regexp.Regexp
+	not             *SelectorRegex
 }
 
 // NewSelectorRegex returns a pointer to a new SelectorRegex
@@ -75,6 +110,18 @@ func NewSelectorRegex(s *Selector) (*SelectorRegex, error) {
 	if err != nil {
 		return nil, err
 	}
+	if s.OriginPath != "" {
+		sr.originPathRegex, err = regexp.Compile("^(?:.*/)?(?:" + s.OriginPath + ")$")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s.Not != nil {
+		sr.not, err = NewSelectorRegex(s.Not)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return sr, nil
 }
 
@@ -122,3 +169,23 @@ func (s *SelectorRegex) MatchNamespace(ns string) bool {
 	}
 	return s.namespaceRegex.MatchString(ns)
 }
+
+// MatchOriginPath returns true if the origin path in selector is
+// empty or the p can be matched by the origin path in selector
+func (s *SelectorRegex) MatchOriginPath(p string) bool {
+	if s.selector.OriginPath == "" {
+		return true
+	}
+	return s.originPathRegex.MatchString(p)
+}
+
+// Selector returns the selector s was made from.
+func (s *SelectorRegex) Selector() *Selector {
+	return s.selector
+}
+
+// Not returns the SelectorRegex of the negated selector,
+// or nil if the selector has none.
+func (s *SelectorRegex) Not() *SelectorRegex {
+	return s.not
+}
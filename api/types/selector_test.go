@@ -245,3 +245,44 @@ func TestSelectorRegexMatchNamespace(t *testing.T) {
 		}
 	}
 }
+
+func TestSelectorRegexMatchOriginPath(t *testing.T) {
+	testcases := []struct {
+		OriginPath string
+		Path       string
+		Expected   bool
+	}{
+		{OriginPath: "", Path: "deployment.yaml", Expected: true},
+		{OriginPath: "deployment.yaml", Path: "deployment.yaml", Expected: true},
+		{OriginPath: "deployment.yaml", Path: "../base/deployment.yaml", Expected: true},
+		{OriginPath: "deployment.yaml", Path: "../base/my-deployment.yaml", Expected: false},
+		{OriginPath: "base/.*", Path: "../base/deployment.yaml", Expected: true},
+		{OriginPath: "base/.*", Path: "../overlay/deployment.yaml", Expected: false},
+		{OriginPath: "deployment.yaml", Path: "", Expected: false},
+	}
+	for _, tc := range testcases {
+		sr, err := NewSelectorRegex(&Selector{OriginPath: tc.OriginPath})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sr.MatchOriginPath(tc.Path) != tc.Expected {
+			t.Fatalf("unexpected result for selector origin path %s and path %s",
+				tc.OriginPath, tc.Path)
+		}
+	}
+}
+
+func TestSelectorCopy(t *testing.T) {
+	s := Selector{
+		ResId: resid.ResId{Name: "web"},
+		Not:   &Selector{ResId: resid.ResId{Name: "web-canary"}},
+	}
+	c := s.Copy()
+	c.Not.Name = "changed"
+	if s.Not.Name != "web-canary" {
+		t.Fatalf("copy shares the negated selector")
+	}
+	if s.String() != "[noKind].[noVer].[noGrp]/web.[noNs]:a=:l=:not=([noKind].[noVer].[noGrp]/web-canary.[noNs]:a=:l=)" {
+		t.Fatalf("unexpected string %s", s.String())
+	}
+}
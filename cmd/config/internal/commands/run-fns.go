@@ -13,7 +13,10 @@ import (
 	"sigs.k8s.io/kustomize/cmd/config/runner"
 
 	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 	"sigs.k8s.io/kustomize/kyaml/runfn"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 
@@ -37,6 +40,9 @@ func GetRunFnRunner(name string) *RunFnRunner {
 	r.Command = c
 	r.Command.Flags().BoolVar(
 		&r.DryRun, "dry-run", false, "print results to stdout")
+	r.Command.Flags().BoolVar(
+		&r.Diff, "diff", false,
+		"print a unified diff of the changes to the package, which are not made with --dry-run")
 	r.Command.Flags().BoolVar(
 		&r.GlobalScope, "global-scope", false, "set global scope for functions.")
 	r.Command.Flags().StringSliceVar(
@@ -51,6 +57,31 @@ func GetRunFnRunner(name string) *RunFnRunner {
 		"enable support for exec functions -- note: exec functions run arbitrary code -- do not use for untrusted configs!!! (Alpha)")
 	r.Command.Flags().StringVar(
 		&r.ExecPath, "exec-path", "", "run an executable as a function. (Alpha)")
+	r.Command.Flags().BoolVar(
+		&r.ExecSandbox, "exec-sandbox", false,
+		"run exec functions with a cleared environment and the limits set by the other exec-sandbox flags. (Alpha)")
+	r.Command.Flags().StringArrayVar(
+		&r.ExecSandboxConfig.EnvAllowList, "exec-sandbox-env", sandbox.DefaultEnvAllowList,
+		"environment variables passed through to sandboxed exec functions.")
+	r.Command.Flags().BoolVar(
+		&r.ExecSandboxConfig.ScratchDir, "exec-sandbox-scratch-dir", false,
+		"run sandboxed exec functions in an empty temporary directory.")
+	r.Command.Flags().BoolVar(
+		&r.ExecSandboxConfig.ReadOnlyWorkDir, "exec-sandbox-read-only-workdir", false,
+		"make the working directory, and the file system, read-only for sandboxed "+
+			"exec functions, except for a temporary directory set as TMPDIR. (Linux only)")
+	r.Command.Flags().DurationVar(
+		&r.ExecSandboxConfig.Timeout, "exec-sandbox-timeout", 0,
+		"wall-clock time limit for sandboxed exec functions, 0 for none.")
+	r.Command.Flags().DurationVar(
+		&r.ExecSandboxConfig.CPUTime, "exec-sandbox-cpu", 0,
+		"cpu time limit for sandboxed exec functions, 0 for none. (Linux only)")
+	r.Command.Flags().Uint64Var(
+		&r.ExecSandboxConfig.MemoryBytes, "exec-sandbox-memory", 0,
+		"address space limit in bytes for sandboxed exec functions, 0 for none. (Linux only)")
+	r.Command.Flags().Int64Var(
+		&r.ExecSandboxConfig.MaxOutputBytes, "exec-sandbox-max-output", 0,
+		"output size limit in bytes for sandboxed exec functions, 0 for none.")
 
 	r.Command.Flags().StringVar(
 		&r.ResultsDir, "results-dir", "", "write function results to this dir")
@@ -67,6 +98,14 @@ func GetRunFnRunner(name string) *RunFnRunner {
 		"a list of environment variables to be used by functions")
 	r.Command.Flags().BoolVar(
 		&r.AsCurrentUser, "as-current-user", false, "use the uid and gid of the command executor to run the function in the container")
+	r.Command.Flags().StringVar(
+		&r.ContainerRuntime, "container-runtime", "",
+		"the container runtime to run functions with, one of "+
+			strings.Join(container.RuntimeNames(), ", ")+
+			"; defaults to $"+container.RuntimeEnvVar+" or docker")
+	r.Command.Flags().StringVar(
+		&r.CacheDir, "cache-dir", "",
+		"cache function outputs in this dir, and skip functions whose input is unchanged; container functions are only cached if their image is pinned by digest")
 
 	return r
 }
@@ -80,6 +119,7 @@ type RunFnRunner struct {
 	IncludeSubpackages bool
 	Command            *cobra.Command
 	DryRun             bool
+	Diff               bool
 	GlobalScope        bool
 	FnPaths            []string
 	Image              string
@@ -88,6 +128,8 @@ type RunFnRunner struct {
 	StarName           string
 	EnableExec         bool
 	ExecPath           string
+	ExecSandbox        bool
+	ExecSandboxConfig  sandbox.Config
 	RunFns             runfn.RunFns
 	ResultsDir         string
 	Network            bool
@@ -95,10 +137,29 @@ type RunFnRunner struct {
 	LogSteps           bool
 	Env                []string
 	AsCurrentUser      bool
+	CacheDir           string
+	ContainerRuntime   string
+
+	// overlay holds the changes to the package until all the
+	// functions succeed.
+	overlay *filesys.FsOverlay
 }
 
 func (r *RunFnRunner) runE(c *cobra.Command, args []string) error {
-	return runner.HandleError(c, r.RunFns.Execute())
+	if err := r.RunFns.Execute(); err != nil || r.overlay == nil {
+		return runner.HandleError(c, err)
+	}
+	if r.Diff {
+		diff, err := r.overlay.Diff()
+		if err != nil {
+			return runner.HandleError(c, err)
+		}
+		fmt.Fprint(c.OutOrStdout(), diff)
+	}
+	if r.DryRun {
+		return nil
+	}
+	return runner.HandleError(c, r.overlay.Commit())
 }
 
 // getContainerFunctions parses the commandline flags and arguments into explicit
@@ -272,8 +333,10 @@ func (r *RunFnRunner) preRunE(c *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		output = c.OutOrStdout()
 		input = c.InOrStdin()
-	} else if r.DryRun {
+	} else if r.DryRun && !r.Diff {
 		output = c.OutOrStdout()
+	} else {
+		r.overlay = filesys.MakeFsOverlay(filesys.MakeFsOnDisk())
 	}
 
 	// set the path if specified as an argument
@@ -291,21 +354,32 @@ func (r *RunFnRunner) preRunE(c *cobra.Command, args []string) error {
 		return err
 	}
 
+	var execSandbox *sandbox.Config
+	if r.ExecSandbox {
+		execSandbox = &r.ExecSandboxConfig
+	}
+
 	r.RunFns = runfn.RunFns{
-		FunctionPaths:  r.FnPaths,
-		GlobalScope:    r.GlobalScope,
-		Functions:      fns,
-		Output:         output,
-		Input:          input,
-		Path:           path,
-		Network:        r.Network,
-		EnableExec:     r.EnableExec,
-		StorageMounts:  storageMounts,
-		ResultsDir:     r.ResultsDir,
-		LogSteps:       r.LogSteps,
-		Env:            r.Env,
-		AsCurrentUser:  r.AsCurrentUser,
-		WorkingDir:     wd,
+		FunctionPaths:    r.FnPaths,
+		GlobalScope:      r.GlobalScope,
+		Functions:        fns,
+		Output:           output,
+		Input:            input,
+		Path:             path,
+		Network:          r.Network,
+		EnableExec:       r.EnableExec,
+		ExecSandbox:      execSandbox,
+		StorageMounts:    storageMounts,
+		ResultsDir:       r.ResultsDir,
+		LogSteps:         r.LogSteps,
+		Env:              r.Env,
+		AsCurrentUser:    r.AsCurrentUser,
+		WorkingDir:       wd,
+		CacheDir:         r.CacheDir,
+		ContainerRuntime: r.ContainerRuntime,
+	}
+	if r.overlay != nil {
+		r.RunFns.FileSystem.Set(r.overlay)
 	}
 
 	// don't consider args for the function
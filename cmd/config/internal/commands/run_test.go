@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/runfn"
 )
 
@@ -257,6 +258,16 @@ apiVersion: v1
 				WorkingDir: wd,
 			},
 		},
+		{
+			name: "dry run diff",
+			args: []string{"run", "dir", "--dry-run", "--diff"},
+			path: "dir",
+			expectedStruct: &runfn.RunFns{
+				Path:       "dir",
+				Env:        []string{},
+				WorkingDir: wd,
+			},
+		},
 		{
 			name: "as current user",
 			args: []string{"run", "dir", "--as-current-user"},
@@ -358,6 +369,9 @@ apiVersion: v1
 			}
 
 			if tt.expectedStruct != nil {
+				// the package is changed through an overlay
+				assert.IsType(t, &filesys.FsOverlay{}, r.RunFns.FileSystem.FileSystem)
+				r.RunFns.FileSystem = filesys.FileSystemOrOnDisk{}
 				r.RunFns.Functions = nil
 				tt.expectedStruct.FunctionPaths = tt.functionPaths
 				if !assert.Equal(t, *tt.expectedStruct, r.RunFns) {
@@ -8,6 +8,7 @@ builtinPlugins=(AnnotationsTransformer \
 	HashTransformer \
 	ImageTagTransformer \
 	LabelTransformer \
+	MetadataRemovalTransformer \
 	NamespaceTransformer \
 	PatchJson6902Transformer \
 	PatchStrategicMergeTransformer \
@@ -0,0 +1,141 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package affected
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/build"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// stdinArg is the --changed-files value reading the changed
+// files from stdin.
+const stdinArg = "-"
+
+type options struct {
+	changedFiles []string
+}
+
+// NewCmdAffected makes a new affected command.
+func NewCmdAffected(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
+	var o options
+	cmd := &cobra.Command{
+		Use:   "affected --changed-files FILE,... [DIR]...",
+		Short: "Prints the kustomization roots affected by changed files",
+		Long: `Prints the kustomization roots under the DIRs, which default to the
+current directory, whose builds read any of the changed files, one per line.
+
+Every root is built, with the build flags given, recording the files
+it reads: its kustomization files, resources, patches, generator
+sources, components and bases, and the files of its local Helm chart
+homes.  A root failing to build is reported, and printed as affected.`,
+		Example: `
+	# Print the overlays to rebuild for the files changed by a pull request
+	git diff --name-only origin/main | kustomize affected --changed-files - overlays
+
+	# Print the roots reading either file
+	kustomize affected --changed-files base/deployment.yaml,base/config.env
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := build.Validate(nil); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				args = []string{filesys.SelfDir}
+			}
+			opts := build.HonorKustomizeFlags(krusty.MakeDefaultOptions(), cmd.Flags())
+			return o.run(fSys, args, opts, cmd.InOrStdin(), w, cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().StringSliceVar(&o.changedFiles, "changed-files", nil,
+		"The changed files, or '-' to read them from stdin, one per line")
+	_ = cmd.MarkFlagRequired("changed-files")
+	build.AddFunctionBasicsFlags(cmd.Flags())
+	build.AddFlagLoadRestrictor(cmd.Flags())
+	build.AddFlagReorderOutput(cmd.Flags())
+	build.AddFlagEnablePlugins(cmd.Flags())
+	build.AddFlagEnableHelm(cmd.Flags())
+	return cmd
+}
+
+func (o *options) run(fSys filesys.FileSystem, dirs []string, opts *krusty.Options,
+	stdin io.Reader, w, stderr io.Writer) error {
+	changed, err := o.readChangedFiles(fSys, stdin)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		roots, err := krusty.DiscoverRoots(fSys, dir)
+		if err != nil {
+			return err
+		}
+		for _, root := range roots {
+			inputs, err := krusty.MakeKustomizer(opts).Inputs(fSys, root)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", root, err)
+			}
+			if err != nil || intersect(inputs, changed) {
+				if _, err = fmt.Fprintln(w, root); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// readChangedFiles returns the absolute paths of the changed files,
+// with the symbolic links of their directories evaluated, like the
+// paths of the inputs of builds.
+func (o *options) readChangedFiles(fSys filesys.FileSystem, stdin io.Reader) ([]string, error) {
+	var files []string
+	for _, f := range o.changedFiles {
+		if f != stdinArg {
+			files = append(files, f)
+			continue
+		}
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				files = append(files, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	changed := make([]string, 0, len(files))
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
+		}
+		// the file may have been deleted, but not its directory
+		if dir, _, err := fSys.CleanedAbs(filepath.Dir(abs)); err == nil {
+			abs = dir.Join(filepath.Base(abs))
+		}
+		changed = append(changed, abs)
+	}
+	return changed, nil
+}
+
+// intersect returns whether any of the changed files is
+// an input, or in an input directory.
+func intersect(inputs, changed []string) bool {
+	for _, input := range inputs {
+		for _, c := range changed {
+			if c == input || strings.HasPrefix(c, input+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package affected
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makeRepo(t *testing.T) filesys.FileSystem {
+	t.Helper()
+	fSys := filesys.MakeFsInMemory()
+	for path, content := range map[string]string{
+		"/repo/base/kustomization.yaml": `
+resources:
+- service.yaml
+configMapGenerator:
+- name: config
+  envs:
+  - config.env
+`,
+		"/repo/base/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"/repo/base/config.env":    "A=B\n",
+		"/repo/base/unrelated.txt": "",
+		"/repo/components/debug/kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+patches:
+- path: patch.yaml
+`,
+		"/repo/components/debug/patch.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  labels:
+    debug: "true"
+`,
+		"/repo/overlays/dev/kustomization.yaml": `
+resources:
+- ../../base
+`,
+		"/repo/overlays/prod/kustomization.yaml": `
+resources:
+- ../../base
+components:
+- ../../components/debug
+`,
+	} {
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	return fSys
+}
+
+func TestAffected(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		stdin    string
+		expected []string
+	}{
+		"base file": {
+			args:     []string{"--changed-files", "/repo/base/config.env", "/repo"},
+			expected: []string{"/repo/base", "/repo/overlays/dev", "/repo/overlays/prod"},
+		},
+		"component file": {
+			args:     []string{"--changed-files", "/repo/components/debug/patch.yaml", "/repo"},
+			expected: []string{"/repo/overlays/prod"},
+		},
+		"kustomization file": {
+			args:     []string{"--changed-files", "/repo/overlays/dev/kustomization.yaml", "/repo"},
+			expected: []string{"/repo/overlays/dev"},
+		},
+		"unread file": {
+			args:     []string{"--changed-files", "/repo/base/unrelated.txt,/repo/README.md", "/repo"},
+			expected: []string{},
+		},
+		"several roots": {
+			args: []string{"--changed-files", "/repo/base/service.yaml",
+				"/repo/overlays/prod", "/repo/overlays/dev"},
+			expected: []string{"/repo/overlays/prod", "/repo/overlays/dev"},
+		},
+		"stdin": {
+			args:     []string{"--changed-files", "-", "/repo/overlays"},
+			stdin:    "\n/repo/components/debug/patch.yaml\n/repo/base/unrelated.txt\n",
+			expected: []string{"/repo/overlays/prod"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := NewCmdAffected(makeRepo(t), &out)
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			cmd.SetArgs(tc.args)
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tc.expected, strings.Fields(out.String()))
+		})
+	}
+}
+
+func TestAffectedBrokenRoot(t *testing.T) {
+	fSys := makeRepo(t)
+	require.NoError(t, fSys.WriteFile("/repo/overlays/dev/kustomization.yaml", []byte(`
+resources:
+- missing.yaml
+`)))
+	var out, stderr bytes.Buffer
+	cmd := NewCmdAffected(fSys, &out)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--changed-files", "/repo/components/debug/patch.yaml", "/repo"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "/repo/overlays/dev\n/repo/overlays/prod\n", out.String())
+	assert.Contains(t, stderr.String(), "/repo/overlays/dev: ")
+	assert.Contains(t, stderr.String(), "missing.yaml")
+}
@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 )
 
 var theArgs struct {
@@ -34,6 +35,12 @@ var theFlags struct {
 	loadRestrictor  string
 	reorderOutput   string
 	fnOptions       types.FnPluginLoadingOptions
+	execSandbox     struct {
+		enabled bool
+		config  sandbox.Config
+	}
+	all      bool
+	parallel int
 }
 
 type Help struct {
@@ -53,6 +60,13 @@ The DIR argument must be a path to a directory containing
 '%s', or a git repository URL with a path suffix
 specifying same with respect to the repository root.
 If DIR is omitted, '.' is assumed.
+
+Many targets, given as several DIRs, or found in the trees
+at the DIRs with --all, are built at once into the --output
+directory, one file DIR.yaml per target, sharing the files
+parsed and the repositories cloned.  A target is built into
+the directory DIR instead, one file per resource, if there's
+one.  A failed target doesn't stop the others.
 `, fN, fN),
 		Example: fmt.Sprintf(`# Build the current working directory
   %s %s
@@ -62,7 +76,10 @@ If DIR is omitted, '.' is assumed.
 
 # Build from github
   %s %s https://github.com/nholuongut/kustomize.git/examples/helloWorld?ref=v1.0.6
-`, pgmName, cmdName, pgmName, cmdName, pgmName, cmdName),
+
+# Build every kustomization under overlays, e.g. overlays/prod into out/prod.yaml
+  %s %s --all overlays -o out
+`, pgmName, cmdName, pgmName, cmdName, pgmName, cmdName, pgmName, cmdName),
 	}
 }
 
@@ -79,10 +96,15 @@ func NewCmdBuild(
 			if err := Validate(args); err != nil {
 				return err
 			}
-			k := krusty.MakeKustomizer(
-				HonorKustomizeFlags(krusty.MakeDefaultOptions(), cmd.Flags()),
-			)
+			kOpts := HonorKustomizeFlags(krusty.MakeDefaultOptions(), cmd.Flags())
+			if isBuildingMany(args) {
+				return buildMany(fSys, args, kOpts, cmd.ErrOrStderr())
+			}
+			k := krusty.MakeKustomizer(kOpts)
 			m, err := k.Run(fSys, theArgs.kustomizationPath)
+			for _, d := range k.Diagnostics() {
+				fmt.Fprintln(cmd.ErrOrStderr(), d)
+			}
 			if err != nil {
 				return err
 			}
@@ -110,6 +132,7 @@ func NewCmdBuild(
 	AddFlagEnablePlugins(cmd.Flags())
 	AddFlagReorderOutput(cmd.Flags())
 	AddFlagEnableManagedbyLabel(cmd.Flags())
+	AddFlagsBuildMany(cmd.Flags())
 
 	if err := AddFlagLoadRestrictorCompletion(cmd); err != nil {
 		log.Fatalf("Error adding completion for flag '--%s': %v", flagLoadRestrictorName, err)
@@ -133,12 +156,11 @@ func NewCmdBuild(
 
 // Validate validates build command args and flags.
 func Validate(args []string) error {
-	if len(args) > 1 {
-		return fmt.Errorf(
-			"specify one path to " +
-				konfig.DefaultKustomizationFileName())
-	}
-	if len(args) == 0 {
+	if isBuildingMany(args) {
+		if err := validateFlagsBuildMany(); err != nil {
+			return err
+		}
+	} else if len(args) == 0 {
 		theArgs.kustomizationPath = filesys.SelfDir
 	} else {
 		theArgs.kustomizationPath = args[0]
@@ -157,6 +179,7 @@ func HonorKustomizeFlags(kOpts *krusty.Options, flags *flag.FlagSet) *krusty.Opt
 	if theFlags.enable.plugins {
 		c := types.EnabledPluginConfig(types.BploUseStaticallyLinked)
 		c.FnpLoadingOptions = theFlags.fnOptions
+		c.FnpLoadingOptions.ExecSandbox = execSandboxConfig()
 		kOpts.PluginConfig = c
 	} else {
 		kOpts.PluginConfig.HelmConfig.Enabled = theFlags.enable.helm
@@ -136,6 +136,23 @@ func TestBuild(t *testing.T) {
 	}
 }
 
+func TestBuildDiagnostics(t *testing.T) {
+	fSys := filesys.MakeFsInMemory()
+	loadFileSystem(fSys)
+	stderr := new(bytes.Buffer)
+	cmd := NewCmdBuild(fSys, MakeHelp("foo", "bar"), new(bytes.Buffer))
+	cmd.SetErr(stderr)
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	if len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "/kustomization.yaml:7:1: warning: 'commonLabels' is deprecated.") ||
+		!strings.HasPrefix(lines[1], "/kustomization.yaml:25:1: warning: 'patchesJson6902' is deprecated.") {
+		t.Fatalf("unexpected diagnostics:\n%s", stderr)
+	}
+}
+
 func TestBuildWithShardedOutput(t *testing.T) {
 	var err error
 	fSys := filesys.MakeFsInMemory()
@@ -225,8 +242,7 @@ func TestValidation(t *testing.T) {
 		"file":      {[]string{"beans"}, "'beans' doesn't exist"},
 		"directory": {[]string{"a/b/c"}, "'a/b/c' doesn't exist"},
 		"tooManyArgs": {[]string{"too", "many"},
-			"specify one path to " +
-				konfig.DefaultKustomizationFileName()},
+			"specify an output directory with --output to build more than one target"},
 	}
 	for n := range cases {
 		tc := cases[n]
@@ -0,0 +1,207 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	flagAllName      = "all"
+	flagParallelName = "parallel"
+)
+
+// AddFlagsBuildMany adds the flags of builds of many targets.
+func AddFlagsBuildMany(set *pflag.FlagSet) {
+	set.BoolVar(
+		&theFlags.all,
+		flagAllName,
+		false,
+		"Build every kustomization root in the trees at the DIRs, i.e. every "+
+			"directory with a kustomization file that isn't a Component.")
+	set.IntVar(
+		&theFlags.parallel,
+		flagParallelName,
+		runtime.NumCPU(),
+		"The number of targets built at once, when building many.")
+}
+
+// isBuildingMany returns whether the args and flags
+// ask for a build of many targets.
+func isBuildingMany(args []string) bool {
+	return len(args) > 1 || theFlags.all
+}
+
+func validateFlagsBuildMany() error {
+	if theFlags.outputPath == "" {
+		return fmt.Errorf(
+			"specify an output directory with --output to build more than one target")
+	}
+	if theFlags.parallel < 1 {
+		return fmt.Errorf(
+			"illegal flag value --%s %d; must be at least 1",
+			flagParallelName, theFlags.parallel)
+	}
+	return nil
+}
+
+// target is a kustomization to build, and the name of
+// its output in the output directory.
+type target struct {
+	path string
+	name string
+}
+
+// targetResult is the result of the build of a target.
+type targetResult struct {
+	m           resmap.ResMap
+	diagnostics []types.Diagnostic
+	err         error
+}
+
+// buildMany builds the targets in parallel, sharing the resources
+// parsed and the repositories cloned, and writes each result in the
+// output directory.  The targets whose openapi schemas or CRDs differ
+// are built one after another, as the schema of a build is global.
+// The diagnostics and errors of the builds are reported in the order
+// of the targets; a failed build doesn't stop the others.
+func buildMany(fSys filesys.FileSystem, args []string,
+	kOpts *krusty.Options, stderr io.Writer) error {
+	targets, err := findTargets(fSys, args)
+	if err != nil {
+		return err
+	}
+	cache := krusty.NewCache()
+	defer func() { _ = cache.Cleanup() }()
+	kOpts.Cache = cache
+
+	results := make([]chan targetResult, len(targets))
+	for i := range results {
+		results[i] = make(chan targetResult, 1)
+	}
+	go func() {
+		workers := make(chan struct{}, theFlags.parallel)
+		for i := range targets {
+			workers <- struct{}{}
+			go func(t target, result chan<- targetResult) {
+				defer func() { <-workers }()
+				// A Kustomizer holds the diagnostics of its last run.
+				k := krusty.MakeKustomizer(kOpts)
+				m, err := k.Run(fSys, t.path)
+				result <- targetResult{m: m, diagnostics: k.Diagnostics(), err: err}
+			}(targets[i], results[i])
+		}
+	}()
+
+	failed := 0
+	for i, t := range targets {
+		r := <-results[i]
+		for _, d := range r.diagnostics {
+			fmt.Fprintln(stderr, d)
+		}
+		err := r.err
+		if err == nil {
+			err = writeTarget(fSys, filepath.Join(theFlags.outputPath, t.name), r.m)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", t.path, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets failed to build", failed, len(targets))
+	}
+	return nil
+}
+
+// writeTarget writes m in the directory at path if there's one,
+// else in the file at path with a .yaml extension.
+func writeTarget(fSys filesys.FileSystem, path string, m resmap.ResMap) error {
+	if fSys.IsDir(path) {
+		return MakeWriter(fSys).WriteIndividualFiles(path, m)
+	}
+	yml, err := m.AsYaml()
+	if err != nil {
+		return err
+	}
+	if err = fSys.MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return fSys.WriteFile(path+".yaml", yml)
+}
+
+// findTargets returns the targets of the args: the kustomization
+// roots in the trees at the args with --all, named by their paths
+// relative to the trees, else the args, named by their paths.
+func findTargets(fSys filesys.FileSystem, args []string) ([]target, error) {
+	if len(args) == 0 {
+		args = []string{filesys.SelfDir}
+	}
+	var targets []target
+	byName := map[string]string{}
+	add := func(path, name string) error {
+		if other, ok := byName[name]; ok {
+			return fmt.Errorf(
+				"targets '%s' and '%s' have the same output '%s'", other, path, name)
+		}
+		byName[name] = path
+		targets = append(targets, target{path: path, name: name})
+		return nil
+	}
+	for _, arg := range args {
+		if !theFlags.all {
+			if err := add(arg, outputName(fSys, arg, arg)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		roots, err := krusty.DiscoverRoots(fSys, arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, root := range roots {
+			rel, err := filepath.Rel(arg, root)
+			if err != nil {
+				return nil, err
+			}
+			if err = add(root, outputName(fSys, arg, rel)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return targets, nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._/-]`)
+
+// outputName returns the name of the output of the target at the
+// given path, relative to dir or not: the path, without its leading
+// slashes and parent directories, and with its characters that
+// aren't safe in file names replaced.  The name of dir itself is
+// the name of its directory.
+func outputName(fSys filesys.FileSystem, dir, path string) string {
+	name := filepath.ToSlash(filepath.Clean(path))
+	name = strings.TrimLeft(name, "/")
+	for strings.HasPrefix(name, "../") {
+		name = strings.TrimPrefix(name, "../")
+	}
+	if name == "" || name == "." || name == ".." {
+		name = filepath.Base(dir)
+		if d, _, err := fSys.CleanedAbs(dir); err == nil {
+			name = filepath.Base(d.String())
+		}
+	}
+	return filepath.FromSlash(unsafeNameChars.ReplaceAllString(name, "_"))
+}
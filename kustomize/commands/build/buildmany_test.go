@@ -0,0 +1,268 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/kustomize/v5/commands/build"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makeTargets(t *testing.T) (filesys.FileSystem, string) {
+	t.Helper()
+	fSys := filesys.MakeFsOnDisk()
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"repo/base/kustomization.yaml": `
+resources:
+- service.yaml
+`,
+		"repo/base/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"repo/components/debug/kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+commonAnnotations:
+  debug: "true"
+`,
+		"repo/overlays/dev/kustomization.yaml": `
+namePrefix: dev-
+resources:
+- ../../base
+components:
+- ../../components/debug
+`,
+		"repo/overlays/prod/kustomization.yaml": `
+namePrefix: prod-
+resources:
+- ../../base
+`,
+	} {
+		path = filepath.Join(dir, path)
+		require.NoError(t, fSys.MkdirAll(filepath.Dir(path)))
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	return fSys, dir
+}
+
+func runBuild(t *testing.T, fSys filesys.FileSystem,
+	flags map[string]string, args ...string) (string, error) {
+	t.Helper()
+	stderr := new(bytes.Buffer)
+	cmd := NewCmdBuild(fSys, MakeHelp("foo", "bar"), new(bytes.Buffer))
+	cmd.SetErr(stderr)
+	for name, value := range flags {
+		require.NoError(t, cmd.Flags().Set(name, value))
+	}
+	err := cmd.RunE(cmd, args)
+	return stderr.String(), err
+}
+
+func TestBuildAll(t *testing.T) {
+	fSys, dir := makeTargets(t)
+	out := filepath.Join(dir, "out")
+	// A directory output holds one file per resource.
+	require.NoError(t, fSys.MkdirAll(filepath.Join(out, "overlays", "prod")))
+
+	stderr, err := runBuild(t, fSys,
+		map[string]string{"all": "true", "output": out, "parallel": "2"},
+		filepath.Join(dir, "repo"))
+	require.NoError(t, err)
+	assert.Empty(t, stderr)
+
+	for path, expected := range map[string]string{
+		"base.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+		"overlays/dev.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    debug: "true"
+  name: dev-app
+`,
+		"overlays/prod/v1_service_prod-app.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: prod-app
+`,
+	} {
+		actual, err := fSys.ReadFile(filepath.Join(out, path))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(actual), path)
+	}
+	assert.False(t, fSys.Exists(filepath.Join(out, "components")))
+}
+
+func TestBuildManyTargets(t *testing.T) {
+	fSys, dir := makeTargets(t)
+	out := filepath.Join(dir, "out")
+	require.NoError(t, fSys.WriteFile(
+		filepath.Join(dir, "repo", "overlays", "dev", "kustomization.yaml"), []byte(`
+resources:
+- missing.yaml
+`)))
+
+	stderr, err := runBuild(t, fSys, map[string]string{"output": out},
+		filepath.Join(dir, "repo", "overlays", "dev"),
+		filepath.Join(dir, "repo", "overlays", "prod"))
+	require.EqualError(t, err, "1 of 2 targets failed to build")
+	assert.True(t, strings.HasPrefix(stderr,
+		filepath.Join(dir, "repo", "overlays", "dev")+": "), stderr)
+	assert.Contains(t, stderr, "missing.yaml")
+
+	// The other target is built.
+	name := strings.TrimLeft(filepath.Join(dir, "repo", "overlays", "prod"), "/")
+	actual, err := fSys.ReadFile(filepath.Join(out, name+".yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: Service
+metadata:
+  name: prod-app
+`, string(actual))
+}
+
+func TestBuildManyValidation(t *testing.T) {
+	fSys, dir := makeTargets(t)
+	repo := filepath.Join(dir, "repo")
+	out := filepath.Join(dir, "out")
+	for name, tc := range map[string]struct {
+		flags map[string]string
+		args  []string
+		erMsg string
+	}{
+		"noOutput": {
+			flags: map[string]string{"all": "true"},
+			args:  []string{repo},
+			erMsg: "specify an output directory with --output to build more than one target",
+		},
+		"noWorkers": {
+			flags: map[string]string{"all": "true", "output": out, "parallel": "0"},
+			args:  []string{repo},
+			erMsg: "illegal flag value --parallel 0; must be at least 1",
+		},
+		"sameOutput": {
+			flags: map[string]string{"output": out},
+			args:  []string{repo + "/base", repo + "/./base"},
+			erMsg: "have the same output",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := runBuild(t, fSys, tc.flags, tc.args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.erMsg)
+		})
+	}
+}
+
+func TestBuildManyTargetsWithDifferentSchemas(t *testing.T) {
+	fSys := filesys.MakeFsOnDisk()
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		require.NoError(t, fSys.MkdirAll(filepath.Dir(path)))
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	write("base/kustomization.yaml", `
+resources:
+- app.yaml
+`)
+	write("base/app.yaml", `
+apiVersion: example.com/v1alpha1
+kind: MyApp
+metadata:
+  name: app
+spec:
+  containers:
+  - name: a
+    image: a:1
+  - name: b
+    image: b:1
+`)
+	// The containers of MyApp are merged by name by the custom
+	// schema, and replaced without it.
+	const schema = `{"definitions": {"v1alpha1.MyApp": {
+  "type": "object",
+  "properties": {"spec": {"type": "object", "properties": {"containers": {
+    "type": "array",
+    "items": {"type": "object"},
+    "x-kubernetes-patch-merge-key": "name",
+    "x-kubernetes-patch-strategy": "merge"}}}},
+  "x-kubernetes-group-version-kind": [
+    {"group": "example.com", "kind": "MyApp", "version": "v1alpha1"}]}}}`
+	const patch = `
+patches:
+- patch: |-
+    apiVersion: example.com/v1alpha1
+    kind: MyApp
+    metadata:
+      name: app
+    spec:
+      containers:
+      - name: a
+        image: a:2
+`
+	const targets = 4
+	for i := 0; i < targets; i++ {
+		write(fmt.Sprintf("merge%d/kustomization.yaml", i), `
+resources:
+- ../base
+openapi:
+  path: schema.json
+`+patch)
+		write(fmt.Sprintf("merge%d/schema.json", i), schema)
+		write(fmt.Sprintf("replace%d/kustomization.yaml", i), `
+resources:
+- ../base
+openapi:
+  version: v1.21.2
+`+patch)
+	}
+	out := filepath.Join(dir, "out")
+
+	stderr, err := runBuild(t, fSys,
+		map[string]string{"all": "true", "output": out, "parallel": "8"}, dir)
+	require.NoError(t, err)
+	assert.Empty(t, stderr)
+	for i := 0; i < targets; i++ {
+		actual, err := fSys.ReadFile(filepath.Join(out, fmt.Sprintf("merge%d.yaml", i)))
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: example.com/v1alpha1
+kind: MyApp
+metadata:
+  name: app
+spec:
+  containers:
+  - image: a:2
+    name: a
+  - image: b:1
+    name: b
+`, string(actual))
+		actual, err = fSys.ReadFile(filepath.Join(out, fmt.Sprintf("replace%d.yaml", i)))
+		require.NoError(t, err)
+		assert.Equal(t, `apiVersion: example.com/v1alpha1
+kind: MyApp
+metadata:
+  name: app
+spec:
+  containers:
+  - image: a:2
+    name: a
+`, string(actual))
+	}
+}
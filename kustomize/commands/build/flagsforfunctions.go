@@ -4,7 +4,11 @@
 package build
 
 import (
+	"strings"
+
 	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 )
 
 func AddFunctionBasicsFlags(set *pflag.FlagSet) {
@@ -23,6 +27,14 @@ func AddFunctionBasicsFlags(set *pflag.FlagSet) {
 	set.BoolVar(
 		&theFlags.fnOptions.AsCurrentUser, "as-current-user", false,
 		"use the uid and gid of the command executor to run the function in the container")
+	set.StringVar(
+		&theFlags.fnOptions.CacheDir, "fn-cache-dir", "",
+		"cache function outputs in this directory, and skip functions whose input is unchanged; container functions are only cached if their image is pinned by digest")
+	set.StringVar(
+		&theFlags.fnOptions.ContainerRuntime, "container-runtime", "",
+		"the container runtime to run functions with, one of "+
+			strings.Join(container.RuntimeNames(), ", ")+
+			"; defaults to $"+container.RuntimeEnvVar+" or docker")
 }
 
 func AddFunctionAlphaEnablementFlags(set *pflag.FlagSet) {
@@ -30,4 +42,40 @@ func AddFunctionAlphaEnablementFlags(set *pflag.FlagSet) {
 		&theFlags.fnOptions.EnableExec, "enable-exec", false,
 		"enable support for exec functions (raw executables); "+
 			"do not use for untrusted configs! (Alpha)")
+	set.BoolVar(
+		&theFlags.execSandbox.enabled, "exec-sandbox", false,
+		"run exec functions and exec plugins with a cleared environment "+
+			"and the limits set by the other exec-sandbox flags (Alpha)")
+	set.StringArrayVar(
+		&theFlags.execSandbox.config.EnvAllowList, "exec-sandbox-env", sandbox.DefaultEnvAllowList,
+		"environment variables passed through to sandboxed executables")
+	set.BoolVar(
+		&theFlags.execSandbox.config.ScratchDir, "exec-sandbox-scratch-dir", false,
+		"run sandboxed executables in an empty temporary directory")
+	set.BoolVar(
+		&theFlags.execSandbox.config.ReadOnlyWorkDir, "exec-sandbox-read-only-workdir", false,
+		"make the working directory, and the file system, read-only for sandboxed "+
+			"executables, except for a temporary directory set as TMPDIR (Linux only)")
+	set.DurationVar(
+		&theFlags.execSandbox.config.Timeout, "exec-sandbox-timeout", 0,
+		"wall-clock time limit for sandboxed executables, 0 for none")
+	set.DurationVar(
+		&theFlags.execSandbox.config.CPUTime, "exec-sandbox-cpu", 0,
+		"cpu time limit for sandboxed executables, 0 for none (Linux only)")
+	set.Uint64Var(
+		&theFlags.execSandbox.config.MemoryBytes, "exec-sandbox-memory", 0,
+		"address space limit in bytes for sandboxed executables, 0 for none (Linux only)")
+	set.Int64Var(
+		&theFlags.execSandbox.config.MaxOutputBytes, "exec-sandbox-max-output", 0,
+		"output size limit in bytes for sandboxed executables, 0 for none")
+}
+
+// execSandboxConfig returns the sandbox configuration from the flags,
+// or nil if the sandbox is not enabled.
+func execSandboxConfig() *sandbox.Config {
+	if !theFlags.execSandbox.enabled {
+		return nil
+	}
+	c := theFlags.execSandbox.config
+	return &c
 }
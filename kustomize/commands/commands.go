@@ -14,11 +14,14 @@ import (
 	"sigs.k8s.io/kustomize/api/provider"
 	"sigs.k8s.io/kustomize/cmd/config/completion"
 	"sigs.k8s.io/kustomize/cmd/config/configcobra"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/affected"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/build"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/create"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/edit"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/graph"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/localize"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/openapi"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/plugin"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/version"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
@@ -55,6 +58,9 @@ See https://sigs.k8s.io/kustomize
 		version.NewCmdVersion(stdOut),
 		openapi.NewCmdOpenAPI(stdOut),
 		localize.NewCmdLocalize(fSys),
+		plugin.NewCmdPlugin(fSys, stdOut),
+		graph.NewCmdGraph(fSys, stdOut),
+		affected.NewCmdAffected(fSys, stdOut),
 	)
 	configcobra.AddCommands(c, konfig.ProgramName)
 
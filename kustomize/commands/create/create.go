@@ -32,7 +32,8 @@ type createFlags struct {
 }
 
 // NewCmdCreate returns an instance of 'create' subcommand.
-func NewCmdCreate(fSys filesys.FileSystem, rf *resource.Factory) *cobra.Command {
+func NewCmdCreate(baseFSys filesys.FileSystem, rf *resource.Factory) *cobra.Command {
+	fSys := filesys.MakeFsOverlay(baseFSys)
 	opts := createFlags{path: filesys.SelfDir}
 	c := &cobra.Command{
 		Use:     "create",
@@ -93,6 +94,7 @@ func NewCmdCreate(fSys filesys.FileSystem, rf *resource.Factory) *cobra.Command
 		"recursive",
 		false,
 		"Enable recursive directory searching for resource auto-detection.")
+	util.AddDryRunFlags(c, fSys)
 	return c
 }
 
@@ -0,0 +1,185 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/internal/kustfile"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	iamPolicyGeneratorKind = "IAMPolicyGenerator"
+	// azureUseLabel is the label of the pods using
+	// Azure Workload Identity.
+	azureUseLabel = "azure.workload.identity/use"
+)
+
+type addIAMPolicyOptions struct {
+	args  types.IAMPolicyGeneratorArgs
+	cloud string
+	gke   types.ServiceAccount
+	aws   types.AWSRole
+	azure types.AzureIdentity
+}
+
+// iamPolicyGenerator is the inline configuration of the
+// IAMPolicyGenerator added to the generators field.
+type iamPolicyGenerator struct {
+	APIVersion        string                  `yaml:"apiVersion"`
+	Kind              string                  `yaml:"kind"`
+	Metadata          types.ObjectMeta        `yaml:"metadata"`
+	Cloud             types.Cloud             `yaml:"cloud"`
+	KubernetesService types.KubernetesService `yaml:"kubernetesService"`
+	ServiceAccount    *types.ServiceAccount   `yaml:"serviceAccount,omitempty"`
+	AWS               *types.AWSRole          `yaml:"aws,omitempty"`
+	Azure             *types.AzureIdentity    `yaml:"azure,omitempty"`
+}
+
+// newCmdAddIAMPolicy adds an IAMPolicyGenerator to the generators field
+// of the kustomization file.
+func newCmdAddIAMPolicy(fSys filesys.FileSystem) *cobra.Command {
+	var o addIAMPolicyOptions
+	cmd := &cobra.Command{
+		Use:   "iampolicy NAME",
+		Short: "Adds an IAMPolicyGenerator to the kustomization file",
+		Long: `Adds an inline IAMPolicyGenerator to the generators field of the kustomization file.
+It generates the Kubernetes service account NAME, bound to a cloud provider identity:
+
+ - gke: a Google service account, with Workload Identity
+ - eks: an AWS IAM role, with IAM roles for service accounts
+ - aks: an Azure managed identity, with Azure Workload Identity
+
+With aks, the pods using the service account must have the
+azure.workload.identity/use: "true" label, which the generator doesn't add.
+`,
+		Example: `
+	# Adds a service account bound to a Google service account
+	kustomize edit add iampolicy app --cloud gke --gcp-service-account app --gcp-project-id my-project
+
+	# Adds a service account assuming an AWS IAM role
+	kustomize edit add iampolicy app --cloud eks --aws-account-id 111122223333 --aws-role-name app
+
+	# Adds a service account using an Azure managed identity
+	kustomize edit add iampolicy app --namespace apps --cloud aks --azure-client-id {client id}
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := o.Validate(args)
+			if err != nil {
+				return err
+			}
+			return o.RunAddIAMPolicy(fSys)
+		},
+	}
+	cmd.Flags().StringVar(&o.cloud, "cloud", "",
+		"Cloud provider of the identity, one of gke, eks or aks")
+	cmd.Flags().StringVar(&o.args.KubernetesService.Namespace, "namespace", "",
+		"Namespace of the Kubernetes service account")
+	cmd.Flags().StringVar(&o.gke.Name, "gcp-service-account", "",
+		"Name of the Google service account, for gke")
+	cmd.Flags().StringVar(&o.gke.ProjectId, "gcp-project-id", "",
+		"ID of the project of the Google service account, for gke")
+	cmd.Flags().StringVar(&o.aws.AccountId, "aws-account-id", "",
+		"ID of the AWS account of the IAM role, for eks")
+	cmd.Flags().StringVar(&o.aws.RoleName, "aws-role-name", "",
+		"Name of the IAM role, for eks")
+	cmd.Flags().StringVar(&o.aws.Partition, "aws-partition", "",
+		"Partition of the AWS account, aws by default, for eks")
+	cmd.Flags().StringVar(&o.azure.ClientId, "azure-client-id", "",
+		"Client ID of the managed identity, for aks")
+	cmd.Flags().StringVar(&o.azure.TenantId, "azure-tenant-id", "",
+		"ID of the tenant of the managed identity, for aks")
+	return cmd
+}
+
+// Validate validates add iampolicy command.
+func (o *addIAMPolicyOptions) Validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("must specify the name of the Kubernetes service account")
+	}
+	o.args.KubernetesService.Name = args[0]
+	o.args.Cloud = types.Cloud(o.cloud)
+	switch o.args.Cloud {
+	case types.GKE:
+		if o.gke.Name == "" || o.gke.ProjectId == "" {
+			return errors.New("gke requires --gcp-service-account and --gcp-project-id")
+		}
+		o.args.ServiceAccount = o.gke
+	case types.EKS:
+		if o.aws.AccountId == "" || o.aws.RoleName == "" {
+			return errors.New("eks requires --aws-account-id and --aws-role-name")
+		}
+		o.args.AWS = &o.aws
+	case types.AKS:
+		if o.azure.ClientId == "" {
+			return errors.New("aks requires --azure-client-id")
+		}
+		o.args.Azure = &o.azure
+	default:
+		return fmt.Errorf("--cloud must be one of %s, %s or %s", types.GKE, types.EKS, types.AKS)
+	}
+	return nil
+}
+
+// RunAddIAMPolicy runs add iampolicy command (do real work).
+func (o *addIAMPolicyOptions) RunAddIAMPolicy(fSys filesys.FileSystem) error {
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	if err != nil {
+		return err
+	}
+	m, err := mf.Read()
+	if err != nil {
+		return err
+	}
+	for _, g := range m.Generators {
+		if o.isGenerated(g) {
+			return fmt.Errorf("%s %s already in kustomization file",
+				iamPolicyGeneratorKind, o.args.KubernetesService.Name)
+		}
+	}
+	generator := iamPolicyGenerator{
+		APIVersion:        "builtin",
+		Kind:              iamPolicyGeneratorKind,
+		Metadata:          types.ObjectMeta{Name: o.args.KubernetesService.Name},
+		Cloud:             o.args.Cloud,
+		KubernetesService: o.args.KubernetesService,
+		AWS:               o.args.AWS,
+		Azure:             o.args.Azure,
+	}
+	if o.args.Cloud == types.GKE {
+		generator.ServiceAccount = &o.args.ServiceAccount
+	}
+	b, err := yaml.Marshal(generator)
+	if err != nil {
+		return err
+	}
+	m.Generators = append(m.Generators, string(b))
+	if err = mf.Write(m); err != nil {
+		return err
+	}
+	if o.args.Cloud == types.AKS {
+		log.Printf("the pods using service account %s must have the label %s: \"true\"",
+			o.args.KubernetesService.Name, azureUseLabel)
+	}
+	return nil
+}
+
+// isGenerated returns true if the generators entry g is an inline
+// IAMPolicyGenerator of the same service account.
+func (o *addIAMPolicyOptions) isGenerated(g string) bool {
+	var existing iamPolicyGenerator
+	if err := yaml.Unmarshal([]byte(g), &existing); err != nil {
+		// not an inline config
+		return false
+	}
+	return existing.Kind == iamPolicyGeneratorKind &&
+		existing.KubernetesService.Name == o.args.KubernetesService.Name &&
+		existing.KubernetesService.Namespace == o.args.KubernetesService.Namespace
+}
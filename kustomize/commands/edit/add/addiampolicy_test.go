@@ -0,0 +1,75 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package add
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/internal/kustfile"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestAddIAMPolicy(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	require.NoError(t, fSys.WriteFile("kustomization.yaml", []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`)))
+
+	cmd := newCmdAddIAMPolicy(fSys)
+	require.NoError(t, cmd.Flags().Set("cloud", "eks"))
+	require.NoError(t, cmd.Flags().Set("namespace", "apps"))
+	require.NoError(t, cmd.Flags().Set("aws-account-id", "111122223333"))
+	require.NoError(t, cmd.Flags().Set("aws-role-name", "s3-reader"))
+	require.NoError(t, cmd.RunE(cmd, []string{"app"}))
+
+	mf, err := kustfile.NewKustomizationFile(fSys)
+	require.NoError(t, err)
+	m, err := mf.Read()
+	require.NoError(t, err)
+	require.Len(t, m.Generators, 1)
+	assert.Equal(t, `apiVersion: builtin
+kind: IAMPolicyGenerator
+metadata:
+  name: app
+cloud: eks
+kubernetesService:
+  name: app
+  namespace: apps
+aws:
+  accountId: "111122223333"
+  roleName: s3-reader
+`, m.Generators[0])
+
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, ".")
+	require.NoError(t, err)
+	yml, err := resMap.AsYaml()
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  annotations:
+    eks.amazonaws.com/role-arn: arn:aws:iam::111122223333:role/s3-reader
+  name: app
+  namespace: apps
+`, string(yml))
+
+	err = cmd.RunE(cmd, []string{"app"})
+	assert.EqualError(t, err, "IAMPolicyGenerator app already in kustomization file")
+}
+
+func TestAddIAMPolicyInvalid(t *testing.T) {
+	fSys := filesys.MakeEmptyDirInMemory()
+	cmd := newCmdAddIAMPolicy(fSys)
+	assert.EqualError(t, cmd.RunE(cmd, nil),
+		"must specify the name of the Kubernetes service account")
+	assert.EqualError(t, cmd.RunE(cmd, []string{"app"}),
+		"--cloud must be one of gke, eks or aks")
+	require.NoError(t, cmd.Flags().Set("cloud", "aks"))
+	assert.EqualError(t, cmd.RunE(cmd, []string{"app"}),
+		"aks requires --azure-client-id")
+}
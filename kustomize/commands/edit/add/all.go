@@ -47,6 +47,9 @@ func NewCmdAdd(
 
 	# Adds a transformer configuration to the kustomization
 	kustomize edit add transformer <filepath>
+
+	# Adds a service account bound to a cloud provider identity to the kustomization
+	kustomize edit add iampolicy NAME --cloud eks --aws-account-id {account id} --aws-role-name {role name}
 `,
 		Args: cobra.MinimumNArgs(1),
 	}
@@ -62,6 +65,7 @@ func NewCmdAdd(
 		newCmdAddAnnotation(fSys, ldr.Validator().MakeAnnotationValidator()),
 		newCmdAddTransformer(fSys),
 		newCmdAddGenerator(fSys),
+		newCmdAddIAMPolicy(fSys),
 	)
 	return c
 }
@@ -16,13 +16,17 @@ import (
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/edit/listbuiltin"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/edit/remove"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/edit/set"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/internal/util"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
 // NewCmdEdit returns an instance of 'edit' subcommand.
+// The subcommands change the files through a copy-on-write
+// overlay of fSys, applied once they succeed.
 func NewCmdEdit(
-	fSys filesys.FileSystem, v ifc.Validator, rf *resource.Factory,
+	baseFSys filesys.FileSystem, v ifc.Validator, rf *resource.Factory,
 	w io.Writer) *cobra.Command {
+	fSys := filesys.MakeFsOverlay(baseFSys)
 	c := &cobra.Command{
 		Use:   "edit",
 		Short: "Edits a kustomization file",
@@ -36,6 +40,9 @@ func NewCmdEdit(
 
 	# Sets the namesuffix field
 	kustomize edit set namesuffix <suffix-value>
+
+	# Prints the changes to the kustomization file, without making them
+	kustomize edit set image nginx=nginx:1.27 --dry-run --diff
 `,
 		Args: cobra.MinimumNArgs(1),
 	}
@@ -54,5 +61,6 @@ func NewCmdEdit(
 		remove.NewCmdRemove(fSys, v),
 		listbuiltin.NewCmdListBuiltinPlugin(),
 	)
+	util.AddDryRunFlags(c, fSys)
 	return c
 }
@@ -6,11 +6,14 @@ package fix
 import (
 	"bytes"
 	"fmt"
-	"path"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/konfig"
+	ldrhelper "sigs.k8s.io/kustomize/api/pkg/loader"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/kio"
@@ -19,7 +22,23 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+// ConvertVarsToReplacements replaces the vars of k with equivalent
+// replacements, and replaces each use of a var in the files contributing to
+// the build with a placeholder value.  It returns an error if a local or
+// remote base or component of k defines vars itself.  Files in remote bases, and local files
+// outside the kustomization root (e.g. in a shared base), are only read: their
+// uses of a var become replacement targets but the files are not rewritten.
 func ConvertVarsToReplacements(fSys filesys.FileSystem, k *types.Kustomization) error {
+	ldr := ldrhelper.NewFileLoaderAtCwd(fSys)
+	c := &fileCollector{root: filesys.ConfirmedDir(ldr.Root())}
+	defer c.cleanup()
+	if err := c.collect(&types.Kustomization{
+		Resources:  append(slices.Clone(k.Resources), k.Bases...),
+		Components: k.Components,
+		Patches:    k.Patches,
+	}, ldr); err != nil {
+		return err
+	}
 	if k.Vars == nil {
 		return nil
 	}
@@ -27,18 +46,13 @@ func ConvertVarsToReplacements(fSys filesys.FileSystem, k *types.Kustomization)
 	k.Resources = append(k.Resources, k.Bases...)
 	k.Replacements = []types.ReplacementField{}
 
-	files, err := filesTouchedByKustomize(k, "", fSys)
-	if err != nil {
-		return err
-	}
-
 	for _, v := range k.Vars {
 		repl := &types.Replacement{}
-		if err := addTargets(repl, v.Name, files, fSys); err != nil {
+		if err := addTargets(repl, v.Name, c.files); err != nil {
 			return err
 		}
 		copySourceFromVars(repl, v)
-		if err := setPlaceholderValue(v.Name, files, fSys); err != nil {
+		if err := setPlaceholderValue(v.Name, c.files, fSys); err != nil {
 			return err
 		}
 		k.Replacements = append(k.Replacements, types.ReplacementField{Replacement: *repl})
@@ -49,50 +63,115 @@ func ConvertVarsToReplacements(fSys filesys.FileSystem, k *types.Kustomization)
 
 var patchTarget = make(map[string]types.Patch)
 
-func filesTouchedByKustomize(k *types.Kustomization, filepath string, fSys filesys.FileSystem) ([]string, error) {
-	var result []string
+// touchedFile is a file contributing resources or patches to the build.
+type touchedFile struct {
+	// ldr is the loader of the kustomization referring to the file.
+	ldr ifc.Loader
+	// path is the absolute path of the file, or its url.
+	path string
+	// name identifies the file in messages.
+	name string
+	// editable is true if the file is local and inside the
+	// kustomization root, so that it may be rewritten.
+	editable bool
+}
+
+// fileCollector finds the files touched by a kustomization and its bases.
+type fileCollector struct {
+	root    filesys.ConfirmedDir
+	files   []touchedFile
+	loaders []ifc.Loader
+}
+
+func (c *fileCollector) collect(k *types.Kustomization, ldr ifc.Loader) error {
 	for _, r := range k.Resources {
 		// first, try to read resource as a base/directory
-		files, err := fSys.ReadDir(r)
-		if err == nil && len(files) > 0 {
-			for _, file := range files {
-				if !slices.Contains([]string{
-					"kustomization.yaml",
-					"kustomization.yml",
-					"Kustomization",
-				}, file) {
-					continue
-				}
-
-				b, err := fSys.ReadFile(path.Join(r, file))
-				if err != nil {
-					continue
-				}
-
-				subKt := &types.Kustomization{}
-				if err := yaml.Unmarshal(b, subKt); err != nil {
-					return nil, err
-				}
-				paths, err := filesTouchedByKustomize(subKt, r, fSys)
-				if err != nil {
-					return nil, err
-				}
-				result = append(result, paths...)
+		if subLdr, err := ldr.New(r); err == nil {
+			if err := c.collectDir(subLdr, r, "base"); err != nil {
+				return err
 			}
+			continue
 		}
 		// read the resource as a file
-		result = append(result, path.Join(filepath, r))
+		c.add(ldr, r)
+	}
+
+	for _, path := range k.Components {
+		subLdr, err := ldr.New(path)
+		if err != nil {
+			return err
+		}
+		if err := c.collectDir(subLdr, path, "component"); err != nil {
+			return err
+		}
 	}
 
 	// aggregate all of the paths from the `patches` field
 	for _, p := range k.Patches {
 		if p.Path != "" {
-			patchPath := path.Join(filepath, p.Path)
-			result = append(result, patchPath)
-			patchTarget[patchPath] = p
+			patchTarget[c.add(ldr, p.Path)] = p
 		}
 	}
-	return result, nil
+	return nil
+}
+
+// collectDir collects the files of the base or component at path, whose
+// root is the root of ldr.  Only the vars of the kustomization being fixed
+// are converted, so a base or component defining vars is an error.
+func (c *fileCollector) collectDir(ldr ifc.Loader, path string, kind string) error {
+	c.loaders = append(c.loaders, ldr)
+	k, err := readKustomization(ldr)
+	if err != nil || k == nil {
+		return err
+	}
+	if k.Vars != nil {
+		return fmt.Errorf(
+			"the %s %s defines vars, which are not converted; convert them in the %s first", kind, path, kind)
+	}
+	return c.collect(k, ldr)
+}
+
+// add records the file at location relative to the root of ldr, and
+// returns its path.
+func (c *fileCollector) add(ldr ifc.Loader, location string) string {
+	f := touchedFile{ldr: ldr, path: location, name: location}
+	if !strings.Contains(location, "://") {
+		if !filepath.IsAbs(location) {
+			f.path = filepath.Join(ldr.Root(), location)
+		}
+		f.editable = ldr.Repo() == "" &&
+			filesys.ConfirmedDir(filepath.Dir(f.path)).HasPrefix(c.root)
+		if rel, err := filepath.Rel(c.root.String(), f.path); err == nil && ldr.Repo() == "" {
+			f.name = rel
+		} else {
+			f.name = ldr.Repo() + "//" + location
+		}
+	}
+	c.files = append(c.files, f)
+	return f.path
+}
+
+func (c *fileCollector) cleanup() {
+	for _, ldr := range c.loaders {
+		_ = ldr.Cleanup()
+	}
+}
+
+// readKustomization returns the kustomization at the root of ldr, or
+// nil if there is none.
+func readKustomization(ldr ifc.Loader) (*types.Kustomization, error) {
+	for _, name := range konfig.RecognizedKustomizationFileNames() {
+		b, err := ldr.Load(name)
+		if err != nil {
+			continue
+		}
+		k := &types.Kustomization{}
+		if err := yaml.Unmarshal(b, k); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	return nil, nil
 }
 
 func copySourceFromVars(repl *types.Replacement, v types.Var) {
@@ -107,18 +186,18 @@ func copySourceFromVars(repl *types.Replacement, v types.Var) {
 	repl.Source.FieldPath = v.FieldRef.FieldPath
 }
 
-func addTargets(repl *types.Replacement, varName string, files []string, fSys filesys.FileSystem) error {
-	for _, file := range files {
-		nodes, err := getNodesFromFile(file, fSys)
+func addTargets(repl *types.Replacement, varName string, files []touchedFile) error {
+	for _, f := range files {
+		nodes, err := getNodesFromFile(f)
 		if err != nil {
 			continue
 		}
 		for _, n := range nodes {
 			fieldPaths, options, err := findVarName(n, varName, []string{})
 			if err != nil {
-				return fmt.Errorf("error with %s: %s", file, err.Error())
+				return fmt.Errorf("error with %s: %s", f.name, err.Error())
 			}
-			targets, err := constructTargets(file, n, fieldPaths, options)
+			targets, err := constructTargets(f.path, n, fieldPaths, options)
 			if err != nil {
 				return err
 			}
@@ -128,8 +207,8 @@ func addTargets(repl *types.Replacement, varName string, files []string, fSys fi
 	return nil
 }
 
-func getNodesFromFile(fileName string, fSys filesys.FileSystem) ([]*kyaml.RNode, error) {
-	b, err := fSys.ReadFile(fileName)
+func getNodesFromFile(f touchedFile) ([]*kyaml.RNode, error) {
+	b, err := f.ldr.Load(f.path)
 	if err != nil {
 		return nil, err
 	}
@@ -313,8 +392,12 @@ func writePatchTargets(patch types.Patch, node *kyaml.RNode, fieldPaths []string
 	return result, nil
 }
 
-func setPlaceholderValue(varName string, files []string, fSys filesys.FileSystem) error {
-	for _, filename := range files {
+func setPlaceholderValue(varName string, files []touchedFile, fSys filesys.FileSystem) error {
+	for _, f := range files {
+		if !f.editable {
+			continue
+		}
+		filename := f.path
 		b, err := fSys.ReadFile(filename)
 		if err != nil {
 			continue
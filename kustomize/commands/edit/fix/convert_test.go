@@ -4,7 +4,9 @@
 package fix
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,6 +49,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("pod.yaml", pod))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -123,6 +127,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("pod.yaml", pod))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -201,6 +207,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("pod.yaml", pod))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -280,6 +288,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("pod.yaml", pod))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -417,6 +427,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -538,6 +550,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -662,6 +676,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -785,6 +801,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -915,6 +933,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -1028,6 +1048,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("base/kustomization.yaml", kustomizationBase))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -1108,6 +1130,8 @@ metadata:
 	require.NoError(t, fSys.WriteFile("pod.yaml", pod))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -1178,6 +1202,8 @@ spec:
 	require.NoError(t, fSys.WriteFile("patch.yaml", patch))
 	cmd := NewCmdFix(fSys, os.Stdout)
 	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	// the unfixed kustomization doesn't build
+	require.NoError(t, cmd.Flags().Set("force", "true"))
 	require.NoError(t, cmd.RunE(cmd, nil))
 	content, err := testutils_test.ReadTestKustomization(fSys)
 	require.NoError(t, err)
@@ -1215,3 +1241,258 @@ spec:
         - name: CERTIFICATE_NAMESPACE_PLACEHOLDER
 `, string(content))
 }
+
+func TestFixVarsVerified(t *testing.T) {
+	kustomization := []byte(`
+resources:
+- resources.yaml
+
+vars:
+- name: SOME_SECRET_NAME
+  objref:
+    kind: Secret
+    name: my-secret
+    apiVersion: v1
+`)
+	resources := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - image: myimage
+    name: hello
+    env:
+    - name: SECRET_TOKEN
+      value: $(SOME_SECRET_NAME)
+`)
+
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, kustomization)
+	require.NoError(t, fSys.WriteFile("resources.yaml", resources))
+	var out bytes.Buffer
+	cmd := NewCmdFix(fSys, &out)
+	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	require.NoError(t, cmd.RunE(cmd, nil))
+	assert.NotContains(t, out.String(), "Warning")
+
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "replacements:")
+	assert.NotContains(t, string(content), "vars:")
+	content, err = fSys.ReadFile("resources.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "value: SOME_SECRET_NAME_PLACEHOLDER")
+}
+
+func TestFixVarsRollback(t *testing.T) {
+	// replacing the first var inserts a delimiter, which shifts the
+	// position of the second var in the value
+	kustomization := []byte(`
+resources:
+- resources.yaml
+
+vars:
+- name: FIRST
+  objref:
+    kind: Secret
+    name: my-secret
+    apiVersion: v1
+- name: SECOND
+  objref:
+    kind: Secret
+    name: other
+    apiVersion: v1
+`)
+	resources := []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: other
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - image: myimage
+    name: hello
+    env:
+    - name: SECRET_TOKEN
+      value: $(FIRST)-$(SECOND)
+`)
+
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, kustomization)
+	require.NoError(t, fSys.WriteFile("resources.yaml", resources))
+	var out bytes.Buffer
+	cmd := NewCmdFix(fSys, &out)
+	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	err := cmd.RunE(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no files were changed")
+	assert.Contains(t, out.String(), `changed Pod.v1.[noGrp]/my-pod.[noNs]:
+`)
+	assert.Contains(t, out.String(), `
+-      value: my-secret-other
+`)
+
+	// the tree is untouched
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	require.NoError(t, err)
+	assert.Equal(t, string(kustomization), string(content))
+	content, err = fSys.ReadFile("resources.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, string(resources), string(content))
+}
+
+func TestFixVarsSharedBase(t *testing.T) {
+	dir := t.TempDir()
+	fSys := filesys.MakeFsOnDisk()
+	require.NoError(t, fSys.MkdirAll(filepath.Join(dir, "base")))
+	require.NoError(t, fSys.MkdirAll(filepath.Join(dir, "overlay")))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "base", "kustomization.yaml"), []byte(`
+resources:
+- pod.yaml
+`)))
+	pod := []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - image: myimage
+    name: hello
+    env:
+    - name: SECRET_TOKEN
+      value: $(SOME_SECRET_NAME)
+`)
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "base", "pod.yaml"), pod))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "overlay", "kustomization.yaml"), []byte(`
+resources:
+- ../base
+- secret.yaml
+
+vars:
+- name: SOME_SECRET_NAME
+  objref:
+    kind: Secret
+    name: my-secret
+    apiVersion: v1
+`)))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "overlay", "secret.yaml"), []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+`)))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join(dir, "overlay")))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	cmd := NewCmdFix(fSys, &bytes.Buffer{})
+	require.NoError(t, cmd.Flags().Set("vars", "true"))
+	require.NoError(t, cmd.RunE(cmd, nil))
+
+	content, err := fSys.ReadFile(filepath.Join(dir, "overlay", "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `replacements:
+- source:
+    kind: Secret
+    name: my-secret
+    version: v1
+  targets:
+  - fieldPaths:
+    - spec.containers.0.env.0.value
+    select:
+      kind: Pod
+      name: my-pod
+      version: v1
+`)
+	// the base outside the kustomization root is not edited
+	content, err = fSys.ReadFile(filepath.Join(dir, "base", "pod.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(pod), string(content))
+}
+
+func TestFixVarsDefinedByBaseOrComponent(t *testing.T) {
+	vars := `
+vars:
+- name: SOME_SECRET_NAME
+  objref:
+    kind: Secret
+    name: my-secret
+    apiVersion: v1
+`
+	for name, tc := range map[string]struct {
+		kustomization string
+		dir           string
+		header        string
+		expectedErr   string
+	}{
+		"base": {
+			kustomization: `
+resources:
+- base
+`,
+			dir: "base",
+			header: `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+`,
+			expectedErr: "the base base defines vars, which are not converted; convert them in the base first",
+		},
+		"component": {
+			kustomization: `
+resources:
+- secret.yaml
+components:
+- component
+`,
+			dir: "component",
+			header: `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+`,
+			expectedErr: "the component component defines vars, which are not converted; convert them in the component first",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fSys := filesys.MakeFsInMemory()
+			testutils_test.WriteTestKustomizationWith(fSys, []byte(tc.kustomization+vars))
+			require.NoError(t, fSys.WriteFile("secret.yaml", []byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-secret
+`)))
+			dirKustomization := []byte(tc.header + vars)
+			require.NoError(t, fSys.WriteFile(tc.dir+"/kustomization.yaml", dirKustomization))
+
+			cmd := NewCmdFix(fSys, &bytes.Buffer{})
+			require.NoError(t, cmd.Flags().Set("vars", "true"))
+			require.NoError(t, cmd.Flags().Set("force", "true"))
+			err := cmd.RunE(cmd, nil)
+			require.EqualError(t, err, tc.expectedErr)
+
+			// no files were changed
+			content, err := testutils_test.ReadTestKustomization(fSys)
+			require.NoError(t, err)
+			assert.Equal(t, tc.kustomization+vars, string(content))
+			content, err = fSys.ReadFile(tc.dir + "/kustomization.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, string(dirKustomization), string(content))
+		})
+	}
+}
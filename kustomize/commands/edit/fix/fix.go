@@ -4,20 +4,19 @@
 package fix
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"sigs.k8s.io/kustomize/api/konfig"
-	"sigs.k8s.io/kustomize/kustomize/v5/commands/build"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/internal/kustfile"
+	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
 var flags struct {
-	vars bool
+	vars  bool
+	force bool
 }
 
 // NewCmdFix returns an instance of 'fix' subcommand.
@@ -36,16 +35,20 @@ func NewCmdFix(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
 		},
 	}
 	AddFlagVars(cmd.Flags())
+	AddFlagForce(cmd.Flags())
 	return cmd
 }
 
-// RunFix runs `fix` command
+// RunFix runs `fix` command.  The fix is staged in memory and only written
+// if the fixed kustomization builds to the same output as the original one.
+// Otherwise a diff of the resources is printed and no files are changed.
+// If the original kustomization fails to build, the fix can't be verified,
+// and it's only written with --force.
 func RunFix(fSys filesys.FileSystem, w io.Writer) error {
-	var oldOutput bytes.Buffer
-	oldBuildCmd := build.NewCmdBuild(fSys, build.MakeHelp(konfig.ProgramName, "build"), &oldOutput)
-	oldBuildCmd.RunE(oldBuildCmd, nil)
+	oldOutput, oldErr := runBuild(fSys)
 
-	mf, err := kustfile.NewKustomizationFile(fSys)
+	staged := filesys.MakeFsOverlay(fSys)
+	mf, err := kustfile.NewKustomizationFile(staged)
 	if err != nil {
 		return err
 	}
@@ -55,12 +58,12 @@ func RunFix(fSys filesys.FileSystem, w io.Writer) error {
 		return err
 	}
 
-	if err := m.FixKustomizationPreMarshalling(fSys); err != nil {
+	if err := m.FixKustomizationPreMarshalling(staged); err != nil {
 		return err
 	}
 
 	if flags.vars {
-		err = ConvertVarsToReplacements(fSys, m)
+		err = ConvertVarsToReplacements(staged, m)
 		if err != nil {
 			return err
 		}
@@ -79,23 +82,32 @@ Fixed fields:
 
 To convert vars -> replacements, run the command `+"`kustomize edit fix --vars`"+`
 
-WARNING: Converting vars to replacements will potentially overwrite many resource files 
-and the resulting files may not produce the same output when `+"`kustomize build`"+` is run. 
-We recommend doing this in a clean git repository where the change is easy to undo.`)
+Converting vars to replacements rewrites resource files inside the kustomization root.
+The files are only written if `+"`kustomize build`"+` produces the same output afterwards.`)
 	}
 
-	writeErr := mf.Write(m)
-
-	var fixedOutput bytes.Buffer
-	fixedBuildCmd := build.NewCmdBuild(fSys, build.MakeHelp(konfig.ProgramName, "build"), &fixedOutput)
-	err = fixedBuildCmd.RunE(fixedBuildCmd, nil)
-	if err != nil {
-		fmt.Fprintf(w, "Warning: 'Fixed' kustomization now produces the error when running `kustomize build`: %s\n", err.Error())
-	} else if fixedOutput.String() != oldOutput.String() {
-		fmt.Fprintf(w, "Warning: 'Fixed' kustomization now produces different output when running `kustomize build`:\n...%s...\n", fixedOutput.String())
+	if err := mf.Write(m); err != nil {
+		return err
 	}
 
-	return writeErr
+	fixedOutput, err := runBuild(staged)
+	switch {
+	case oldErr != nil && !flags.force:
+		return errors.Errorf("the original kustomization fails to build, so the fix can't be verified, no files were changed; use --force to fix it anyway: %s", oldErr.Error())
+	case oldErr != nil:
+		// there is no output to preserve
+		fmt.Fprintf(w, "Warning: the original kustomization fails to build, so the fix could not be verified: %s\n", oldErr.Error())
+	case err != nil:
+		return errors.Errorf("'Fixed' kustomization produces the error when running `kustomize build`, no files were changed: %s", err.Error())
+	case fixedOutput != oldOutput:
+		diff, err := resourceDiff(oldOutput, fixedOutput)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "'Fixed' kustomization produces different output when running `kustomize build`:\n%s", diff)
+		return errors.Errorf("'Fixed' kustomization changes the output of `kustomize build`, no files were changed")
+	}
+	return staged.Commit()
 }
 
 func AddFlagVars(set *pflag.FlagSet) {
@@ -103,6 +115,15 @@ func AddFlagVars(set *pflag.FlagSet) {
 		&flags.vars,
 		"vars",
 		false, // default
-		`If specified, kustomize will attempt to convert vars to replacements. 
-We recommend doing this in a clean git repository where the change is easy to undo.`)
+		`If specified, kustomize will attempt to convert vars to replacements.
+Files are only changed if the converted kustomization builds to the same output.`)
+}
+
+func AddFlagForce(set *pflag.FlagSet) {
+	set.BoolVar(
+		&flags.force,
+		"force",
+		false, // default
+		`If specified, the kustomization is fixed even if it fails to build
+before the fix, so that the fix can't be verified.`)
 }
@@ -4,6 +4,7 @@
 package fix
 
 import (
+	"bytes"
 	"os"
 	"testing"
 
@@ -376,6 +377,8 @@ kind: Kustomization
 				require.NoError(t, fSys.WriteFile(filename, []byte(content)))
 			}
 			cmd := NewCmdFix(fSys, os.Stdout)
+			// the patches of some cases don't exist, so they don't build
+			require.NoError(t, cmd.Flags().Set("force", "true"))
 			require.NoError(t, cmd.RunE(cmd, nil))
 
 			content, err := testutils_test.ReadTestKustomization(fSys)
@@ -405,3 +408,28 @@ labels:
 	require.Error(t, err)
 	assert.Equal(t, err.Error(), "label name 'foo' exists in both commonLabels and labels")
 }
+
+func TestFixOriginalBuildFails(t *testing.T) {
+	kustomization := []byte(`
+patchesStrategicMerge:
+- missing.yaml
+`)
+	fSys := filesys.MakeFsInMemory()
+	testutils_test.WriteTestKustomizationWith(fSys, kustomization)
+	cmd := NewCmdFix(fSys, &bytes.Buffer{})
+	err := cmd.RunE(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no files were changed; use --force")
+	content, err := testutils_test.ReadTestKustomization(fSys)
+	require.NoError(t, err)
+	assert.Equal(t, string(kustomization), string(content))
+
+	var out bytes.Buffer
+	cmd = NewCmdFix(fSys, &out)
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	require.NoError(t, cmd.RunE(cmd, nil))
+	assert.Contains(t, out.String(), "Warning: the original kustomization fails to build")
+	content, err = testutils_test.ReadTestKustomization(fSys)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "patches:")
+}
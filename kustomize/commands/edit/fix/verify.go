@@ -0,0 +1,74 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package fix
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// runBuild returns the output of `kustomize build` in the current directory,
+// built with the default options rather than the flags of the build command.
+func runBuild(fSys filesys.FileSystem) (string, error) {
+	m, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, filesys.SelfDir)
+	if err != nil {
+		return "", err
+	}
+	yml, err := m.AsYaml()
+	if err != nil {
+		return "", err
+	}
+	return string(yml), nil
+}
+
+// resourceDiff compares two build outputs resource by resource, and returns
+// a report of the resources added, removed or changed by the second one.
+func resourceDiff(before, after string) (string, error) {
+	beforeNodes, err := readResources(before)
+	if err != nil {
+		return "", err
+	}
+	afterNodes, err := readResources(after)
+	if err != nil {
+		return "", err
+	}
+	changes, err := kio.DiffResources(beforeNodes, afterNodes)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, c := range changes {
+		switch {
+		case c.After == "":
+			fmt.Fprintf(&b, "removed %s\n", c.ID)
+		case c.Before == "":
+			fmt.Fprintf(&b, "added %s\n", c.ID)
+		default:
+			diff, err := c.UnifiedDiff("before", "after")
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "changed %s:\n%s", c.ID, diff)
+		}
+	}
+	if b.Len() == 0 {
+		// the same resources in a different order
+		b.WriteString("resources are in a different order\n")
+	}
+	return b.String(), nil
+}
+
+// readResources returns the resources of a build output.
+func readResources(s string) ([]*yaml.RNode, error) {
+	return (&kio.ByteReader{
+		Reader:                strings.NewReader(s),
+		OmitReaderAnnotations: true,
+	}).Read()
+}
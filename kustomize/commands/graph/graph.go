@@ -0,0 +1,90 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	formatJSON = "json"
+	formatDOT  = "dot"
+)
+
+type options struct {
+	format  string
+	reverse bool
+}
+
+// NewCmdGraph makes a new graph command.
+func NewCmdGraph(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
+	var o options
+	cmd := &cobra.Command{
+		Use:   "graph [DIR]",
+		Short: "Prints the dependency graph of a kustomization",
+		Long: `Prints the dependency graph of the kustomization in DIR, which
+defaults to the current directory: the kustomizations, components,
+remote references, Helm charts and files it refers to, directly or not,
+and the fields referring to them.
+
+The graph is read from the kustomization files alone: no generator or
+transformer runs, and remote repositories aren't fetched.`,
+		Example: `
+	# Print the graph of an overlay as JSON
+	kustomize graph overlays/prod
+
+	# Render the graph with Graphviz
+	kustomize graph overlays/prod --format dot | dot -Tsvg > prod.svg
+
+	# Print the graph from the files to the kustomizations using them
+	kustomize graph overlays/prod --reverse
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := filesys.SelfDir
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			return o.run(fSys, dir, w, cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().StringVar(&o.format, "format", formatJSON,
+		"Format of the graph, one of 'json' or 'dot'")
+	cmd.Flags().BoolVar(&o.reverse, "reverse", false,
+		"Reverse the edges, from the nodes to the kustomizations referring to them")
+	return cmd
+}
+
+func (o *options) run(fSys filesys.FileSystem, dir string, w, stderr io.Writer) error {
+	if o.format != formatJSON && o.format != formatDOT {
+		return fmt.Errorf("unknown format %q, expected %q or %q", o.format, formatJSON, formatDOT)
+	}
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	g, err := k.Graph(fSys, dir)
+	for _, d := range k.Diagnostics() {
+		fmt.Fprintln(stderr, d)
+	}
+	if err != nil {
+		return err
+	}
+	if o.reverse {
+		g = g.Reverse()
+	}
+	if o.format == formatDOT {
+		_, err = io.WriteString(w, g.DOT())
+		return err
+	}
+	out, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
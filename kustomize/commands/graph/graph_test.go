@@ -0,0 +1,105 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makeKustomizations(t *testing.T) filesys.FileSystem {
+	t.Helper()
+	fSys := filesys.MakeFsInMemory()
+	for path, content := range map[string]string{
+		"/app/base/kustomization.yaml":    "resources:\n- service.yaml\n",
+		"/app/overlay/kustomization.yaml": "resources:\n- ../base\ncommonLabels:\n  app: a\n",
+	} {
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	return fSys
+}
+
+func TestGraph(t *testing.T) {
+	testCases := map[string]struct {
+		args     []string
+		expected string
+	}{
+		"json": {
+			args: []string{"/app/overlay"},
+			expected: `{
+  "nodes": [
+    {
+      "id": "/app/overlay",
+      "type": "kustomization"
+    },
+    {
+      "id": "/app/base",
+      "type": "kustomization"
+    },
+    {
+      "id": "/app/base/service.yaml",
+      "type": "file"
+    }
+  ],
+  "edges": [
+    {
+      "from": "/app/overlay",
+      "to": "/app/base",
+      "field": "resources"
+    },
+    {
+      "from": "/app/base",
+      "to": "/app/base/service.yaml",
+      "field": "resources"
+    }
+  ]
+}
+`,
+		},
+		"dot": {
+			args: []string{"/app/overlay", "--format", "dot"},
+			expected: `digraph kustomize {
+  "/app/overlay" [shape=box];
+  "/app/base" [shape=box];
+  "/app/base/service.yaml" [shape=note];
+  "/app/overlay" -> "/app/base" [label="resources"];
+  "/app/base" -> "/app/base/service.yaml" [label="resources"];
+}
+`,
+		},
+		"reverse": {
+			args: []string{"/app/overlay", "--format", "dot", "--reverse"},
+			expected: `digraph kustomize {
+  "/app/overlay" [shape=box];
+  "/app/base" [shape=box];
+  "/app/base/service.yaml" [shape=note];
+  "/app/base" -> "/app/overlay" [label="resources"];
+  "/app/base/service.yaml" -> "/app/base" [label="resources"];
+}
+`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var out, stderr bytes.Buffer
+			cmd := NewCmdGraph(makeKustomizations(t), &out)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(tc.args)
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tc.expected, out.String())
+			assert.Contains(t, stderr.String(),
+				"/app/overlay/kustomization.yaml:3:1: warning: 'commonLabels' is deprecated.")
+		})
+	}
+}
+
+func TestGraphUnknownFormat(t *testing.T) {
+	cmd := NewCmdGraph(makeKustomizations(t), &bytes.Buffer{})
+	cmd.SetArgs([]string{"/app/overlay", "--format", "svg"})
+	require.EqualError(t, cmd.Execute(), `unknown format "svg", expected "json" or "dot"`)
+}
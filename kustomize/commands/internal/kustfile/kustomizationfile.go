@@ -50,6 +50,8 @@ func determineFieldOrder() []string {
 		"CommonLabels",
 		"Labels",
 		"CommonAnnotations",
+		"RemoveLabels",
+		"RemoveAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
 		"Patches",
@@ -167,8 +169,8 @@ func (mf *kustomizationFile) Read() (*types.Kustomization, error) {
 		return nil, err
 	}
 
-	var k types.Kustomization
-	if err := k.Unmarshal(data); err != nil {
+	k, _, err := types.DecodeKustomization(data, mf.path)
+	if err != nil {
 		return nil, err
 	}
 
@@ -177,7 +179,7 @@ func (mf *kustomizationFile) Read() (*types.Kustomization, error) {
 	if err := mf.parseCommentedFields(data); err != nil {
 		return nil, err
 	}
-	return &k, nil
+	return k, nil
 }
 
 func (mf *kustomizationFile) Write(kustomization *types.Kustomization) error {
@@ -32,6 +32,8 @@ func TestFieldOrder(t *testing.T) {
 		"CommonLabels",
 		"Labels",
 		"CommonAnnotations",
+		"RemoveLabels",
+		"RemoveAnnotations",
 		"PatchesStrategicMerge",
 		"PatchesJson6902",
 		"Patches",
@@ -162,6 +164,12 @@ func TestReadAndWriteDummy(t *testing.T) {
 			Name:  "name",
 			Count: 1,
 		}},
+		RemoveLabels: &types.KeyRemoval{
+			Keys: []string{"label"},
+		},
+		RemoveAnnotations: &types.KeyRemoval{
+			Prefixes: []string{"annotation/"},
+		},
 		SortOptions: &types.SortOptions{
 			Order: types.LegacySortOrder,
 			LegacySortOptions: &types.LegacySortOptions{
@@ -534,7 +542,7 @@ foo:
 	}
 
 	_, err = mf.Read()
-	if err == nil || err.Error() != "invalid Kustomization: json: unknown field \"foo\"" {
+	if err == nil || err.Error() != "invalid Kustomization: kustomization.yaml:2:1: unknown field \"foo\"" {
 		t.Fatalf("Expect an unknown field error but got: %v", err)
 	}
 }
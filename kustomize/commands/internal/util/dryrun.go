@@ -0,0 +1,66 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// AddDryRunFlags adds the --dry-run and --diff flags to cmd and its
+// subcommands, which change files through fSys.
+//
+// Their RunE is wrapped to apply the changes held by fSys once they
+// succeed, so that a command failing halfway through changes no file.
+// With --dry-run, the changes are discarded instead, and with --diff,
+// they are printed first.  The changes to the temporary directory,
+// e.g. to the clones of remote bases, are applied directly.
+func AddDryRunFlags(cmd *cobra.Command, fSys *filesys.FsOverlay) {
+	var dryRun, diff bool
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false,
+		"Do not change any file, only run the command")
+	cmd.PersistentFlags().BoolVar(&diff, "diff", false,
+		"Print a unified diff of the files changed by the command")
+
+	tmp := os.TempDir()
+	fSys.PassThrough(tmp)
+	if deLinked, err := filepath.EvalSymlinks(tmp); err == nil && deLinked != tmp {
+		fSys.PassThrough(deLinked)
+	}
+	wrapRunE(cmd, func(c *cobra.Command) error {
+		if diff {
+			d, err := fSys.Diff()
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(c.OutOrStdout(), d)
+		}
+		if dryRun {
+			fSys.Reset()
+			return nil
+		}
+		return fSys.Commit()
+	}, fSys.Reset)
+}
+
+// wrapRunE wraps the RunE of cmd and its subcommands to call
+// apply after they succeed, else discard.
+func wrapRunE(cmd *cobra.Command, apply func(*cobra.Command) error, discard func()) {
+	if runE := cmd.RunE; runE != nil {
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			if err := runE(c, args); err != nil {
+				discard()
+				return err
+			}
+			return apply(c)
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapRunE(sub, apply, discard)
+	}
+}
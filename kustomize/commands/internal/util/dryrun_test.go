@@ -0,0 +1,104 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makeDryRunCommand(t *testing.T) (*cobra.Command, filesys.FileSystem, *bytes.Buffer) {
+	t.Helper()
+	base := filesys.MakeFsInMemory()
+	require.NoError(t, base.WriteFile("/app/kustomization.yaml", []byte("namePrefix: dev-\n")))
+	fSys := filesys.MakeFsOverlay(base)
+
+	parent := &cobra.Command{Use: "edit"}
+	parent.AddCommand(&cobra.Command{
+		Use: "prefix",
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := fSys.WriteFile("/app/kustomization.yaml",
+				[]byte("namePrefix: "+args[0]+"\n")); err != nil {
+				return err
+			}
+			if args[0] == "fail-" {
+				return errors.New("failed halfway through")
+			}
+			return fSys.WriteFile("/app/other.yaml", []byte("kind: Other\n"))
+		},
+	})
+	AddDryRunFlags(parent, fSys)
+	var out bytes.Buffer
+	parent.SetOut(&out)
+	parent.SetErr(&out)
+	return parent, base, &out
+}
+
+func TestAddDryRunFlags(t *testing.T) {
+	diff := `--- a/app/kustomization.yaml
++++ b/app/kustomization.yaml
+@@ -1 +1 @@
+-namePrefix: dev-
++namePrefix: prod-
+--- /dev/null
++++ b/app/other.yaml
+@@ -0,0 +1 @@
++kind: Other
+`
+	testCases := map[string]struct {
+		args          []string
+		expectedOut   string
+		expectedFile  string
+		expectedError string
+	}{
+		"commit": {
+			args:         []string{"prefix", "prod-"},
+			expectedFile: "namePrefix: prod-\n",
+		},
+		"diff": {
+			args:         []string{"prefix", "prod-", "--diff"},
+			expectedOut:  diff,
+			expectedFile: "namePrefix: prod-\n",
+		},
+		"dry-run": {
+			args:         []string{"--dry-run", "prefix", "prod-"},
+			expectedFile: "namePrefix: dev-\n",
+		},
+		"dry-run diff": {
+			args:         []string{"prefix", "prod-", "--dry-run", "--diff"},
+			expectedOut:  diff,
+			expectedFile: "namePrefix: dev-\n",
+		},
+		"failure": {
+			args:          []string{"prefix", "fail-"},
+			expectedError: "failed halfway through",
+			expectedFile:  "namePrefix: dev-\n",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cmd, base, out := makeDryRunCommand(t)
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedOut, out.String())
+			content, err := base.ReadFile("/app/kustomization.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFile, string(content))
+			assert.Equal(t, tc.expectedFile == "namePrefix: prod-\n", base.Exists("/app/other.yaml"))
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	lclzr "sigs.k8s.io/kustomize/api/krusty/localizer"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/build"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/internal/util"
 	"sigs.k8s.io/kustomize/kyaml/copyutil"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
@@ -29,7 +30,8 @@ type flags struct {
 }
 
 // NewCmdLocalize returns a new localize command.
-func NewCmdLocalize(fs filesys.FileSystem) *cobra.Command {
+func NewCmdLocalize(baseFs filesys.FileSystem) *cobra.Command {
+	fs := filesys.MakeFsOverlay(baseFs)
 	var f flags
 	var buildBuffer bytes.Buffer
 	buildCmd := build.NewCmdBuild(fs, &build.Help{}, &buildBuffer)
@@ -111,6 +113,7 @@ If not specified for local target, scope defaults to target.
 		`Does not verify that the outputs of kustomize build for target and newDir are the same after localization.
 		If not specified, this flag defaults to false and will run kustomize build.
 	`)
+	util.AddDryRunFlags(cmd, fs)
 	return cmd
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/openapi/fetch"
 	"sigs.k8s.io/kustomize/kustomize/v5/commands/openapi/info"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/openapi/schema"
 )
 
 // NewCmdOpenAPI makes a new openapi command.
@@ -22,5 +23,6 @@ func NewCmdOpenAPI(w io.Writer) *cobra.Command {
 
 	openApiCmd.AddCommand(info.NewCmdInfo(w))
 	openApiCmd.AddCommand(fetch.NewCmdFetch(w))
+	openApiCmd.AddCommand(schema.NewCmdSchema(w))
 	return openApiCmd
 }
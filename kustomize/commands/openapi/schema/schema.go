@@ -0,0 +1,58 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/types/jsonschema"
+)
+
+type options struct {
+	kustomization bool
+	component     bool
+}
+
+// NewCmdSchema makes a new schema command.
+func NewCmdSchema(w io.Writer) *cobra.Command {
+	var o options
+	schemaCmd := cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON Schema of kustomization files, for editors and validators",
+		Example: `
+	# Save the schema of kustomization files for an editor
+	kustomize openapi schema --kustomization > kustomization.schema.json
+
+	# Print the schema of components
+	kustomize openapi schema --component
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.run(w)
+		},
+	}
+	schemaCmd.Flags().BoolVar(&o.kustomization, "kustomization", false,
+		"Print the schema of kustomization files of kind Kustomization")
+	schemaCmd.Flags().BoolVar(&o.component, "component", false,
+		"Print the schema of kustomization files of kind Component")
+	return &schemaCmd
+}
+
+func (o *options) run(w io.Writer) error {
+	var schema []byte
+	switch {
+	case o.kustomization && o.component:
+		return fmt.Errorf("--kustomization and --component are mutually exclusive")
+	case o.kustomization:
+		schema = jsonschema.Kustomization()
+	case o.component:
+		schema = jsonschema.Component()
+	default:
+		return fmt.Errorf("one of --kustomization or --component is required")
+	}
+	_, err := w.Write(schema)
+	return err
+}
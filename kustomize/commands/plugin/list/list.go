@@ -0,0 +1,83 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package list
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/pkg/pluginmanifest"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	typeBuiltin = "builtin"
+	// padding separates the columns of the list.
+	padding = 2
+)
+
+type options struct {
+	builtin bool
+}
+
+// NewCmdList makes a new list command.
+func NewCmdList(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
+	var o options
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the plugins in the plugin home, with their manifests",
+		Example: `
+	# List the plugins in $KUSTOMIZE_PLUGIN_HOME
+	KUSTOMIZE_PLUGIN_HOME=~/plugins kustomize plugin list
+
+	# Also list the builtin plugins
+	kustomize plugin list --builtin
+`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return o.run(fSys, w)
+		},
+	}
+	cmd.Flags().BoolVar(&o.builtin, "builtin", false,
+		"Also list the builtin plugins")
+	return cmd
+}
+
+func (o *options) run(fSys filesys.FileSystem, w io.Writer) error {
+	home, err := konfig.DefaultAbsPluginHome(fSys)
+	if err != nil {
+		return err
+	}
+	plugins, discoverErr := pluginmanifest.Discover(fSys, home)
+	if discoverErr != nil && plugins == nil {
+		return discoverErr
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, "APIVERSION\tKIND\tTYPE\tNAME\tVERSION\tPATH")
+	if o.builtin {
+		for _, name := range krusty.GetBuiltinPluginNames() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t-\t-\t-\n",
+				konfig.BuiltinPluginApiVersion, name, typeBuiltin)
+		}
+	}
+	for _, p := range plugins {
+		name, version := "-", "-"
+		if p.Manifest != nil {
+			name = p.Manifest.Name
+			if p.Manifest.Version != "" {
+				version = p.Manifest.Version
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.APIVersion, p.Kind, p.Type, name, version, p.Path)
+	}
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+	return discoverErr
+}
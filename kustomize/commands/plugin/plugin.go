@@ -0,0 +1,32 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/plugin/list"
+	"sigs.k8s.io/kustomize/kustomize/v5/commands/plugin/verify"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// NewCmdPlugin makes a new plugin command.
+func NewCmdPlugin(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
+	pluginCmd := &cobra.Command{
+		Use:   "plugin",
+		Short: "Commands for inspecting the plugins in the plugin home",
+		Example: `
+	# List the plugins, and their manifests
+	kustomize plugin list
+
+	# Verify the plugins against their manifests
+	kustomize plugin verify
+`,
+	}
+
+	pluginCmd.AddCommand(list.NewCmdList(fSys, w))
+	pluginCmd.AddCommand(verify.NewCmdVerify(fSys, w))
+	return pluginCmd
+}
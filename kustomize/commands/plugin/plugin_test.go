@@ -0,0 +1,88 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func makePluginHome(t *testing.T) filesys.FileSystem {
+	t.Helper()
+	t.Setenv(konfig.KustomizePluginHomeEnv, "/plugins")
+	fSys := filesys.MakeFsInMemory()
+	for path, content := range map[string]string{
+		"/plugins/example.com/v1/somegenerator/SomeGenerator": "#!/bin/sh\n",
+		"/plugins/example.com/v1/somegenerator/plugin.yaml":   "name: some-generator\nversion: v1.0.0\n",
+		"/plugins/example.com/v1/tampered/Tampered.rpc":       "#!/bin/sh\n",
+		"/plugins/example.com/v1/tampered/plugin.yaml":        "name: tampered\nchecksum: sha256:0123\n",
+		"/plugins/v1/unmanifested/Unmanifested.so":            "",
+	} {
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	return fSys
+}
+
+func TestList(t *testing.T) {
+	fSys := makePluginHome(t)
+	var out bytes.Buffer
+	cmd := NewCmdPlugin(fSys, &out)
+	cmd.SetArgs([]string{"list"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, `APIVERSION      KIND           TYPE  NAME            VERSION  PATH
+example.com/v1  SomeGenerator  exec  some-generator  v1.0.0   /plugins/example.com/v1/somegenerator/SomeGenerator
+example.com/v1  Tampered       rpc   tampered        -        /plugins/example.com/v1/tampered/Tampered.rpc
+v1              Unmanifested   go    -               -        /plugins/v1/unmanifested/Unmanifested.so
+`, out.String())
+}
+
+func TestVerify(t *testing.T) {
+	testCases := map[string]struct {
+		args          []string
+		expectedOut   string
+		expectedError string
+	}{
+		"all": {
+			expectedOut: `example.com/v1 SomeGenerator: verified some-generator
+example.com/v1 Tampered: plugin file /plugins/example.com/v1/tampered/Tampered.rpc ` +
+				`has checksum sha256:a8076d3d28d21e02012b20eaf7dbf75409a6277134439025f282e368e3305abf, ` +
+				`but its manifest expects sha256:0123; the plugin may have been tampered with
+v1 Unmanifested: no manifest
+`,
+			expectedError: "1 of 3 plugins failed verification",
+		},
+		"some kinds": {
+			args: []string{"SomeGenerator", "Unmanifested"},
+			expectedOut: `example.com/v1 SomeGenerator: verified some-generator
+v1 Unmanifested: no manifest
+`,
+		},
+		"no kinds found": {
+			args:          []string{"Missing"},
+			expectedError: "no plugin found in /plugins",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fSys := makePluginHome(t)
+			var out bytes.Buffer
+			cmd := NewCmdPlugin(fSys, &out)
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(append([]string{"verify"}, tc.args...))
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package verify
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/pkg/pluginmanifest"
+	"sigs.k8s.io/kustomize/api/provenance"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// NewCmdVerify makes a new verify command.
+func NewCmdVerify(fSys filesys.FileSystem, w io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify [KIND]...",
+		Short: "Verifies the plugins in the plugin home against their manifests",
+		Long: `Verifies the plugins in the plugin home, or only those of the given kinds,
+against their manifests, as the loader does before using them: the kind
+must be supported, the kustomize version in the required range, and the
+checksum of the plugin file as expected.
+
+Plugins without a manifest are reported, but are not an error.`,
+		Example: `
+	# Verify all the plugins
+	kustomize plugin verify
+
+	# Verify the plugins of some kinds
+	kustomize plugin verify SomeServiceGenerator SecretsFromDatabase
+`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return run(fSys, w, args)
+		},
+	}
+}
+
+func run(fSys filesys.FileSystem, w io.Writer, kinds []string) error {
+	home, err := konfig.DefaultAbsPluginHome(fSys)
+	if err != nil {
+		return err
+	}
+	plugins, err := pluginmanifest.Discover(fSys, home)
+	if err != nil {
+		return err
+	}
+	version := provenance.GetProvenance().Semver()
+	failed, verified := 0, 0
+	for _, p := range plugins {
+		if len(kinds) > 0 && !slices.Contains(kinds, p.Kind) {
+			continue
+		}
+		verified++
+		if p.Manifest == nil {
+			fmt.Fprintf(w, "%s %s: no manifest\n", p.APIVersion, p.Kind)
+			continue
+		}
+		if err = pluginmanifest.Verify(fSys, p.Manifest, p.Path, p.Kind, version); err != nil {
+			failed++
+			fmt.Fprintf(w, "%s %s: %v\n", p.APIVersion, p.Kind, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s: verified %s\n", p.APIVersion, p.Kind, p.Manifest.Name)
+	}
+	if verified == 0 {
+		return fmt.Errorf("no plugin found in %s", home)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d plugins failed verification", failed, verified)
+	}
+	return nil
+}
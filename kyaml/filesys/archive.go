@@ -0,0 +1,66 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// MakeFsFromTar makes a read-only FileSystem of the directories
+// and regular files of the tar archive read from r, loaded in memory.
+// Other entries, e.g. symbolic links, are ignored.
+// Compressed archives must be decompressed by r, e.g. with gzip.NewReader.
+func MakeFsFromTar(r io.Reader) (FileSystem, error) {
+	fSys := MakeFsInMemory()
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read tar archive: %w", err)
+		}
+		name := strings.TrimLeft(path.Clean("/"+hdr.Name), "/")
+		if name == "" {
+			continue
+		}
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("invalid path '%s' in tar archive", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = fSys.MkdirAll(name)
+		case tar.TypeReg:
+			var content []byte
+			content, err = io.ReadAll(tr)
+			if err == nil {
+				err = fSys.WriteFile(name, content)
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to load '%s' from tar archive: %w", hdr.Name, err)
+		}
+	}
+	return MakeFsFromFS(AsFS(fSys, Separator)), nil
+}
+
+// MakeFsFromZip makes a read-only FileSystem of the files of the
+// zip archive of the given size read from r.
+func MakeFsFromZip(r io.ReaderAt, size int64) (FileSystem, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read zip archive: %w", err)
+	}
+	return MakeFsFromFS(zr), nil
+}
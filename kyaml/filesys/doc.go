@@ -3,5 +3,7 @@
 
 // Package filesys provides a file system abstraction,
 // a subset of that provided by golang.org/pkg/os,
-// with an on-disk and in-memory representation.
+// with an on-disk and in-memory representation,
+// and read-only representations of io/fs.FS, tar and
+// zip archives.
 package filesys
@@ -0,0 +1,181 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var _ FileSystem = fsFS{}
+
+// fsFS implements a read-only FileSystem over an io/fs.FS.
+//
+// The root of the fs.FS is the root directory of the FileSystem,
+// which is also its working directory: relative paths are relative
+// to the root, and CleanedAbs returns paths below Separator.
+type fsFS struct {
+	fsys fs.FS
+}
+
+// MakeFsFromFS makes a read-only FileSystem of the files of fsys,
+// e.g. an embed.FS, a zip.Reader or the fs.FS of AsFS.
+// The methods changing the FileSystem return an error
+// wrapping fs.ErrPermission.
+func MakeFsFromFS(fsys fs.FS) FileSystem {
+	return fsFS{fsys: fsys}
+}
+
+// name returns the name in the fs.FS of the given path.
+func (fsFS) name(p string) string {
+	name := strings.TrimLeft(
+		filepath.ToSlash(filepath.Clean(Separator+StripLeadingSeps(p))), "/")
+	if name == "" {
+		return SelfDir
+	}
+	return name
+}
+
+// abs returns the absolute path of the given name in the fs.FS.
+func (fsFS) abs(name string) string {
+	if name == SelfDir {
+		return Separator
+	}
+	return Separator + filepath.FromSlash(name)
+}
+
+// readOnlyError returns the error of the given operation changing path.
+func readOnlyError(op, p string) error {
+	return &fs.PathError{Op: op, Path: p, Err: fs.ErrPermission}
+}
+
+// Create returns an error, the file system is read-only.
+func (fsFS) Create(p string) (File, error) {
+	return nil, readOnlyError("create", p)
+}
+
+// Mkdir returns an error, the file system is read-only.
+func (fsFS) Mkdir(p string) error { return readOnlyError("mkdir", p) }
+
+// MkdirAll returns an error, the file system is read-only.
+func (fsFS) MkdirAll(p string) error { return readOnlyError("mkdir", p) }
+
+// RemoveAll returns an error, the file system is read-only.
+func (fsFS) RemoveAll(p string) error { return readOnlyError("remove", p) }
+
+// WriteFile returns an error, the file system is read-only.
+func (fsFS) WriteFile(p string, _ []byte) error { return readOnlyError("write", p) }
+
+// Open delegates to fs.FS.Open.
+func (x fsFS) Open(p string) (File, error) {
+	f, err := x.fsys.Open(x.name(p))
+	if err != nil {
+		return nil, err
+	}
+	return fsFile{File: f, path: p}, nil
+}
+
+// IsDir delegates to fs.Stat and FileInfo.IsDir.
+func (x fsFS) IsDir(p string) bool {
+	info, err := fs.Stat(x.fsys, x.name(p))
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// ReadDir delegates to fs.ReadDir, returning the names in the directory.
+func (x fsFS) ReadDir(p string) ([]string, error) {
+	entries, err := fs.ReadDir(x.fsys, x.name(p))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(entries))
+	for i := range entries {
+		result[i] = entries[i].Name()
+	}
+	return result, nil
+}
+
+// CleanedAbs converts the given path into a
+// directory and a file name, where the directory
+// is represented as a ConfirmedDir and all that implies.
+// If the entire path is a directory, the file component
+// is an empty string.
+func (x fsFS) CleanedAbs(p string) (ConfirmedDir, string, error) {
+	name := x.name(p)
+	info, err := fs.Stat(x.fsys, name)
+	if err != nil {
+		return "", "", notExistError(p)
+	}
+	if info.IsDir() {
+		return ConfirmedDir(x.abs(name)), "", nil
+	}
+	return ConfirmedDir(x.abs(path.Dir(name))), path.Base(name), nil
+}
+
+// Exists returns true if fs.Stat succeeds.
+func (x fsFS) Exists(p string) bool {
+	_, err := fs.Stat(x.fsys, x.name(p))
+	return err == nil
+}
+
+// Glob returns the list of matching files, absolute if the
+// pattern is, else relative to the root.
+func (x fsFS) Glob(pattern string) ([]string, error) {
+	matches, err := fs.Glob(x.fsys, x.name(pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		if filepath.IsAbs(pattern) {
+			matches[i] = x.abs(matches[i])
+		} else {
+			matches[i] = filepath.FromSlash(matches[i])
+		}
+	}
+	if IsHiddenFilePath(pattern) {
+		return matches, nil
+	}
+	return RemoveHiddenFiles(matches), nil
+}
+
+// ReadFile delegates to fs.ReadFile.
+func (x fsFS) ReadFile(p string) ([]byte, error) {
+	return fs.ReadFile(x.fsys, x.name(p))
+}
+
+// Walk delegates to fs.WalkDir, calling walkFn with
+// the paths below p, like filepath.Walk.
+func (x fsFS) Walk(p string, walkFn filepath.WalkFunc) error {
+	root := x.name(p)
+	return fs.WalkDir(x.fsys, root, func(name string, d fs.DirEntry, err error) error {
+		var info fs.FileInfo
+		if d != nil {
+			var infoErr error
+			info, infoErr = d.Info()
+			if err == nil {
+				err = infoErr
+			}
+		}
+		rel := strings.TrimPrefix(name, root)
+		if root == SelfDir {
+			rel = name
+		}
+		return walkFn(filepath.Join(p, filepath.FromSlash(rel)), info, err)
+	})
+}
+
+// fsFile implements File over a read-only fs.File.
+type fsFile struct {
+	fs.File
+	path string
+}
+
+// Write returns an error, the file is read-only.
+func (f fsFile) Write([]byte) (int, error) {
+	return 0, readOnlyError("write", f.path)
+}
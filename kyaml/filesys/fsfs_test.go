@@ -0,0 +1,189 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveFiles = map[string]string{
+	"app/kustomization.yaml": "resources:\n- deployment.yaml\n",
+	"app/deployment.yaml":    "kind: Deployment\n",
+	"app/.hidden.yaml":       "kind: Secret\n",
+	"README.md":              "readme\n",
+}
+
+func makeTar(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "./app/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, name := range []string{
+		"app/kustomization.yaml", "app/deployment.yaml", "app/.hidden.yaml", "README.md"} {
+		content := archiveFiles[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "app/link.yaml", Typeflag: tar.TypeSymlink, Linkname: "deployment.yaml"}))
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range archiveFiles {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func makeReadOnlyFileSystems(t *testing.T) map[string]FileSystem {
+	t.Helper()
+	mapFS := fstest.MapFS{}
+	for name, content := range archiveFiles {
+		mapFS[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	tarFs, err := MakeFsFromTar(bytes.NewReader(makeTar(t)))
+	require.NoError(t, err)
+	zipData := makeZip(t)
+	zipFs, err := MakeFsFromZip(bytes.NewReader(zipData), int64(len(zipData)))
+	require.NoError(t, err)
+	return map[string]FileSystem{
+		"fs":  MakeFsFromFS(mapFS),
+		"tar": tarFs,
+		"zip": zipFs,
+	}
+}
+
+func TestReadOnlyFileSystems(t *testing.T) {
+	for name, fSys := range makeReadOnlyFileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			content, err := fSys.ReadFile("app/deployment.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, "kind: Deployment\n", string(content))
+			content, err = fSys.ReadFile(filepath.Join(Separator, "app", "kustomization.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, archiveFiles["app/kustomization.yaml"], string(content))
+
+			assert.True(t, fSys.Exists("README.md"))
+			assert.False(t, fSys.Exists("app/link.yaml"))
+			assert.True(t, fSys.IsDir("app"))
+			assert.True(t, fSys.IsDir(Separator))
+			assert.False(t, fSys.IsDir("README.md"))
+
+			names, err := fSys.ReadDir("app")
+			require.NoError(t, err)
+			assert.Equal(t, []string{".hidden.yaml", "deployment.yaml", "kustomization.yaml"}, names)
+
+			d, f, err := fSys.CleanedAbs("app/../app/deployment.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, ConfirmedDir(filepath.Join(Separator, "app")), d)
+			assert.Equal(t, "deployment.yaml", f)
+			d, f, err = fSys.CleanedAbs("app")
+			require.NoError(t, err)
+			assert.Equal(t, ConfirmedDir(filepath.Join(Separator, "app")), d)
+			assert.Empty(t, f)
+			_, _, err = fSys.CleanedAbs("missing")
+			require.ErrorIs(t, err, fs.ErrNotExist)
+
+			matches, err := fSys.Glob("app/*.yaml")
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				filepath.Join("app", "deployment.yaml"),
+				filepath.Join("app", "kustomization.yaml"),
+			}, matches)
+			matches, err = fSys.Glob(filepath.Join(Separator, "*.md"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{filepath.Join(Separator, "README.md")}, matches)
+
+			var walked []string
+			require.NoError(t, fSys.Walk("app", func(path string, info os.FileInfo, err error) error {
+				require.NoError(t, err)
+				walked = append(walked, path)
+				return nil
+			}))
+			assert.Equal(t, []string{
+				"app",
+				filepath.Join("app", ".hidden.yaml"),
+				filepath.Join("app", "deployment.yaml"),
+				filepath.Join("app", "kustomization.yaml"),
+			}, walked)
+
+			file, err := fSys.Open("README.md")
+			require.NoError(t, err)
+			info, err := file.Stat()
+			require.NoError(t, err)
+			assert.Equal(t, "README.md", info.Name())
+			_, err = file.Write([]byte("changed"))
+			require.ErrorIs(t, err, fs.ErrPermission)
+			require.NoError(t, file.Close())
+
+			require.ErrorIs(t, fSys.WriteFile("README.md", nil), fs.ErrPermission)
+			require.ErrorIs(t, fSys.MkdirAll("other"), fs.ErrPermission)
+			require.ErrorIs(t, fSys.RemoveAll("app"), fs.ErrPermission)
+			_, err = fSys.Create("other.yaml")
+			require.ErrorIs(t, err, fs.ErrPermission)
+		})
+	}
+}
+
+func TestMakeFsFromTarInvalidPath(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name: "a/../../etc/passwd", Typeflag: tar.TypeReg}))
+	require.NoError(t, tw.Close())
+
+	// the path is cleaned below the root
+	fSys, err := MakeFsFromTar(&buf)
+	require.NoError(t, err)
+	assert.True(t, fSys.Exists("etc/passwd"))
+
+	_, err = MakeFsFromTar(bytes.NewReader([]byte("not a tar archive")))
+	require.Error(t, err)
+}
+
+func TestAsFS(t *testing.T) {
+	dir := t.TempDir()
+	fSys := MakeFsOnDisk()
+	for name, content := range archiveFiles {
+		path := filepath.Join(dir, "root", filepath.FromSlash(name))
+		require.NoError(t, fSys.MkdirAll(filepath.Dir(path)))
+		require.NoError(t, fSys.WriteFile(path, []byte(content)))
+	}
+	fsys := AsFS(fSys, filepath.Join(dir, "root"))
+	require.NoError(t, fstest.TestFS(fsys,
+		"app/kustomization.yaml", "app/deployment.yaml", "app/.hidden.yaml", "README.md"))
+
+	_, err := fs.ReadFile(fsys, "../root/README.md")
+	require.ErrorIs(t, err, fs.ErrInvalid)
+	_, err = fs.ReadFile(fsys, "missing.yaml")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	inMemory := MakeFsInMemory()
+	require.NoError(t, inMemory.WriteFile("/app/kustomization.yaml", []byte("resources: []\n")))
+	matches, err := fs.Glob(AsFS(inMemory, Separator), "*/*.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app/kustomization.yaml"}, matches)
+}
@@ -0,0 +1,751 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+const (
+	// newFileMode is the mode of the files created on disk by Commit.
+	newFileMode = 0644
+	// diffContext is the number of lines of context in Diff.
+	diffContext = 3
+	// devNull is the name of the missing side of the diff of
+	// created and removed files.
+	devNull = "/dev/null"
+)
+
+var _ FileSystem = &FsOverlay{}
+
+// FsOverlay is a copy-on-write FileSystem.
+//
+// It reads through to a base FileSystem, but holds the files written,
+// the directories made and the paths removed in memory, until Commit
+// applies them to the base FileSystem.  A command that fails halfway
+// through its changes can then leave the base FileSystem untouched,
+// and on disk, Commit applies all of the changes or none of them.
+type FsOverlay struct {
+	base FileSystem
+	// root is the absolute working directory of base.
+	root    string
+	files   map[string][]byte
+	dirs    map[string]bool
+	removed map[string]bool
+	// passThrough are the directories changed directly.
+	passThrough []string
+}
+
+// MakeFsOverlay makes an FsOverlay over the base FileSystem.
+func MakeFsOverlay(base FileSystem) *FsOverlay {
+	return &FsOverlay{
+		base:    base,
+		files:   map[string][]byte{},
+		dirs:    map[string]bool{},
+		removed: map[string]bool{},
+	}
+}
+
+// key returns the cleaned absolute path of path, which the
+// changes are held by.
+func (o *FsOverlay) key(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	if o.root == "" {
+		o.root = Separator
+		if d, _, err := o.base.CleanedAbs(SelfDir); err == nil {
+			o.root = d.String()
+		}
+	}
+	return filepath.Join(o.root, path)
+}
+
+// PassThrough makes the changes below dir, e.g. to the temporary
+// clones of remote bases, apply directly to the base FileSystem.
+func (o *FsOverlay) PassThrough(dir string) {
+	o.passThrough = append(o.passThrough, o.key(dir))
+}
+
+// passesThrough returns true if the changes to k apply directly
+// to the base FileSystem.
+func (o *FsOverlay) passesThrough(k string) bool {
+	for _, dir := range o.passThrough {
+		if strings.HasPrefix(k, dir+Separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRemoved returns true if k or one of its parents is removed.
+func (o *FsOverlay) isRemoved(k string) bool {
+	for {
+		if o.removed[k] {
+			return true
+		}
+		parent := filepath.Dir(k)
+		if parent == k {
+			return false
+		}
+		k = parent
+	}
+}
+
+// Create creates a file, held in memory until it is closed.
+func (o *FsOverlay) Create(path string) (File, error) {
+	if k := o.key(path); o.passesThrough(k) {
+		return o.base.Create(k)
+	}
+	if err := o.WriteFile(path, nil); err != nil {
+		return nil, err
+	}
+	return &fsOverlayFile{o: o, key: o.key(path), writable: true}, nil
+}
+
+// Mkdir makes a directory in an existing directory.
+func (o *FsOverlay) Mkdir(path string) error {
+	k := o.key(path)
+	if o.passesThrough(k) {
+		return o.base.Mkdir(k)
+	}
+	if o.Exists(k) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrExist}
+	}
+	if !o.IsDir(filepath.Dir(k)) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fs.ErrNotExist}
+	}
+	o.dirs[k] = true
+	return nil
+}
+
+// MkdirAll makes a directory path, creating intervening directories.
+func (o *FsOverlay) MkdirAll(path string) error {
+	k := o.key(path)
+	if o.passesThrough(k) {
+		return o.base.MkdirAll(k)
+	}
+	if o.IsDir(k) {
+		return nil
+	}
+	if o.Exists(k) {
+		return &fs.PathError{Op: "mkdir", Path: path, Err: fmt.Errorf("not a directory")}
+	}
+	if err := o.MkdirAll(filepath.Dir(k)); err != nil {
+		return err
+	}
+	o.dirs[k] = true
+	return nil
+}
+
+// RemoveAll removes path and any children it contains.
+func (o *FsOverlay) RemoveAll(path string) error {
+	k := o.key(path)
+	if o.passesThrough(k) {
+		return o.base.RemoveAll(k)
+	}
+	prefix := k + Separator
+	for f := range o.files {
+		if f == k || strings.HasPrefix(f, prefix) {
+			delete(o.files, f)
+		}
+	}
+	for d := range o.dirs {
+		if d == k || strings.HasPrefix(d, prefix) {
+			delete(o.dirs, d)
+		}
+	}
+	o.removed[k] = true
+	return nil
+}
+
+// Open opens the named file for reading.
+func (o *FsOverlay) Open(path string) (File, error) {
+	k := o.key(path)
+	if content, found := o.files[k]; found {
+		return &fsOverlayFile{o: o, key: k, buf: bytes.NewBuffer(content)}, nil
+	}
+	if o.dirs[k] {
+		return &fsOverlayFile{o: o, key: k, buf: &bytes.Buffer{}}, nil
+	}
+	if o.isRemoved(k) {
+		return nil, notExistError(path)
+	}
+	return o.base.Open(k)
+}
+
+// IsDir returns true if the path is a directory.
+func (o *FsOverlay) IsDir(path string) bool {
+	k := o.key(path)
+	if o.dirs[k] {
+		return true
+	}
+	if _, found := o.files[k]; found || o.isRemoved(k) {
+		return false
+	}
+	return o.base.IsDir(k)
+}
+
+// ReadDir returns a list of files and directories within a directory.
+func (o *FsOverlay) ReadDir(path string) ([]string, error) {
+	k := o.key(path)
+	if !o.IsDir(k) {
+		if !o.Exists(k) {
+			return nil, notExistError(path)
+		}
+		return nil, fmt.Errorf("%s is not a directory", path)
+	}
+	names := map[string]bool{}
+	if !o.dirs[k] || o.base.IsDir(k) && !o.isRemoved(k) {
+		baseNames, err := o.base.ReadDir(k)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range baseNames {
+			if !o.isRemoved(filepath.Join(k, name)) {
+				names[name] = true
+			}
+		}
+	}
+	for f := range o.files {
+		if filepath.Dir(f) == k {
+			names[filepath.Base(f)] = true
+		}
+	}
+	for d := range o.dirs {
+		if filepath.Dir(d) == k && d != k {
+			names[filepath.Base(d)] = true
+		}
+	}
+	return sortedKeys(names), nil
+}
+
+// CleanedAbs converts the given path into a
+// directory and a file name, where the directory
+// is represented as a ConfirmedDir and all that implies.
+// If the entire path is a directory, the file component
+// is an empty string.
+func (o *FsOverlay) CleanedAbs(path string) (ConfirmedDir, string, error) {
+	k := o.key(path)
+	if o.dirs[k] {
+		return ConfirmedDir(k), "", nil
+	}
+	if _, found := o.files[k]; found {
+		return ConfirmedDir(filepath.Dir(k)), filepath.Base(k), nil
+	}
+	if o.isRemoved(k) {
+		return "", "", notExistError(path)
+	}
+	return o.base.CleanedAbs(k)
+}
+
+// Exists is true if the path exists in the file system.
+func (o *FsOverlay) Exists(path string) bool {
+	k := o.key(path)
+	if _, found := o.files[k]; found || o.dirs[k] {
+		return true
+	}
+	return !o.isRemoved(k) && o.base.Exists(k)
+}
+
+// Glob returns the list of matching files,
+// emulating https://golang.org/pkg/path/filepath/#Glob
+func (o *FsOverlay) Glob(pattern string) ([]string, error) {
+	baseMatches, err := o.base.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matches := map[string]bool{}
+	for _, m := range baseMatches {
+		if !o.isRemoved(o.key(m)) {
+			matches[m] = true
+		}
+	}
+	keyPattern := o.key(pattern)
+	for _, changed := range [][]string{sortedKeys(o.files), sortedKeys(o.dirs)} {
+		for _, k := range changed {
+			match, err := filepath.Match(keyPattern, k)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+			if !filepath.IsAbs(pattern) {
+				if k, err = filepath.Rel(o.root, k); err != nil {
+					return nil, err
+				}
+			}
+			matches[k] = true
+		}
+	}
+	result := sortedKeys(matches)
+	if IsHiddenFilePath(pattern) {
+		return result, nil
+	}
+	return RemoveHiddenFiles(result), nil
+}
+
+// ReadFile returns the contents of the file at the given path.
+func (o *FsOverlay) ReadFile(path string) ([]byte, error) {
+	k := o.key(path)
+	if content, found := o.files[k]; found {
+		return append([]byte(nil), content...), nil
+	}
+	if o.dirs[k] {
+		return nil, fmt.Errorf("cannot read content from non-file '%s'", path)
+	}
+	if o.isRemoved(k) {
+		return nil, notExistError(path)
+	}
+	return o.base.ReadFile(k)
+}
+
+// WriteFile writes the data to a file at the given path,
+// overwriting anything that's already there.
+func (o *FsOverlay) WriteFile(path string, data []byte) error {
+	k := o.key(path)
+	if o.passesThrough(k) {
+		return o.base.WriteFile(k, data)
+	}
+	if o.IsDir(k) {
+		return fmt.Errorf("cannot write to directory '%s'", path)
+	}
+	if !o.IsDir(filepath.Dir(k)) {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	o.files[k] = append([]byte(nil), data...)
+	return nil
+}
+
+// Walk walks the file system with the given WalkFunc,
+// in lexical order like filepath.Walk.
+func (o *FsOverlay) Walk(path string, walkFn filepath.WalkFunc) error {
+	info, err := o.stat(path)
+	if err != nil {
+		err = walkFn(path, nil, err)
+	} else {
+		err = o.walk(path, info, walkFn)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func (o *FsOverlay) walk(path string, info os.FileInfo, walkFn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return walkFn(path, info, nil)
+	}
+	names, err := o.ReadDir(path)
+	err1 := walkFn(path, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+	for _, name := range names {
+		child := filepath.Join(path, name)
+		childInfo, err := o.stat(child)
+		if err != nil {
+			if err = walkFn(child, nil, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if err = o.walk(child, childInfo, walkFn); err != nil {
+			if !childInfo.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stat returns the FileInfo of path.
+func (o *FsOverlay) stat(path string) (os.FileInfo, error) {
+	f, err := o.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// Commit applies the changes to the base FileSystem, and clears them.
+//
+// On disk, the changes are all or nothing: the files in existing
+// directories are first written to temporary files, then the removed
+// paths and the replaced files are moved aside, the directories made and
+// the files renamed or written, and a failure rolls back the changes
+// made so far.  The paths moved aside are deleted once all the changes
+// are made.
+func (o *FsOverlay) Commit() error {
+	if _, onDisk := o.base.(fsOnDisk); onDisk {
+		if err := o.commitOnDisk(); err != nil {
+			return err
+		}
+		o.Reset()
+		return nil
+	}
+	for _, k := range sortedKeys(o.removed) {
+		if err := o.base.RemoveAll(k); err != nil {
+			return errors.WrapPrefixf(err, "unable to remove %s", k)
+		}
+	}
+	for _, k := range sortedKeys(o.dirs) {
+		if err := o.base.MkdirAll(k); err != nil {
+			return errors.WrapPrefixf(err, "unable to make directory %s", k)
+		}
+	}
+	for _, k := range sortedKeys(o.files) {
+		if err := o.base.WriteFile(k, o.files[k]); err != nil {
+			return errors.WrapPrefixf(err, "unable to write %s", k)
+		}
+	}
+	o.Reset()
+	return nil
+}
+
+// commitOnDisk applies the changes to the disk, see Commit.
+func (o *FsOverlay) commitOnDisk() (err error) {
+	staged, err := o.stageOnDisk()
+	if err != nil {
+		return err
+	}
+	// undo rolls back the changes made, in reverse order
+	var undo []func() error
+	// aside are the temporary directories of the paths moved aside
+	var aside []string
+	defer func() {
+		// the temporary files not renamed
+		for _, tmp := range staged {
+			_ = os.Remove(tmp)
+		}
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				_ = undo[i]()
+			}
+		}
+		for _, dir := range aside {
+			if err == nil {
+				_ = os.RemoveAll(dir)
+			} else {
+				_ = os.Remove(dir)
+			}
+		}
+	}()
+	moveAside := func(k string) error {
+		dir, err := moveAside(k)
+		if err != nil || dir == "" {
+			return err
+		}
+		aside = append(aside, dir)
+		undo = append(undo, func() error {
+			return os.Rename(filepath.Join(dir, filepath.Base(k)), k)
+		})
+		return nil
+	}
+	for _, k := range sortedKeys(o.removed) {
+		if err = moveAside(k); err != nil {
+			return errors.WrapPrefixf(err, "unable to remove %s", k)
+		}
+	}
+	for _, k := range sortedKeys(o.dirs) {
+		missing, err := missingDirs(k)
+		if err != nil {
+			return errors.WrapPrefixf(err, "unable to make directory %s", k)
+		}
+		for _, dir := range missing {
+			if err = os.Mkdir(dir, 0777|os.ModeDir); err != nil {
+				return errors.WrapPrefixf(err, "unable to make directory %s", k)
+			}
+			undo = append(undo, func() error { return os.Remove(dir) })
+		}
+	}
+	for _, k := range sortedKeys(o.files) {
+		if err = moveAside(k); err != nil {
+			return errors.WrapPrefixf(err, "unable to write %s", k)
+		}
+		if tmp, found := staged[k]; found {
+			err = os.Rename(tmp, k)
+			delete(staged, k)
+		} else {
+			err = errors.Wrap(os.WriteFile(k, o.files[k], newFileMode))
+		}
+		if err != nil {
+			return errors.WrapPrefixf(err, "unable to write %s", k)
+		}
+		undo = append(undo, func() error { return os.Remove(k) })
+	}
+	return nil
+}
+
+// moveAside moves the path k, if it exists, to a new temporary directory
+// next to it, and returns the temporary directory, or "" if k doesn't
+// exist.
+func moveAside(k string) (string, error) {
+	if _, err := os.Lstat(k); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", errors.Wrap(err)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(k), "."+filepath.Base(k)+".*.old")
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	if err = os.Rename(k, filepath.Join(dir, filepath.Base(k))); err != nil {
+		_ = os.Remove(dir)
+		return "", errors.Wrap(err)
+	}
+	return dir, nil
+}
+
+// missingDirs returns the directories to make for the directory k
+// to exist, from the outermost.
+func missingDirs(k string) ([]string, error) {
+	var missing []string
+	for dir := k; ; dir = filepath.Dir(dir) {
+		_, err := os.Stat(dir)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, errors.Wrap(err)
+		}
+		missing = append([]string{dir}, missing...)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return missing, nil
+}
+
+// Reset discards the changes.
+func (o *FsOverlay) Reset() {
+	o.files = map[string][]byte{}
+	o.dirs = map[string]bool{}
+	o.removed = map[string]bool{}
+}
+
+// stageOnDisk writes the files in existing directories to temporary files
+// next to them, and returns the temporary file of each file.
+// On error, the temporary files are removed.
+func (o *FsOverlay) stageOnDisk() (map[string]string, error) {
+	staged := map[string]string{}
+	for _, k := range sortedKeys(o.files) {
+		dir := filepath.Dir(k)
+		if o.isRemoved(dir) || !o.base.IsDir(dir) {
+			// written after the directory is made
+			continue
+		}
+		tmp, err := o.writeTemp(k)
+		if err != nil {
+			for _, t := range staged {
+				_ = os.Remove(t)
+			}
+			return nil, errors.WrapPrefixf(err, "unable to write %s", k)
+		}
+		staged[k] = tmp
+	}
+	return staged, nil
+}
+
+// writeTemp writes the content of the file k to a temporary file in its
+// directory, with the mode of the file if it exists, and returns its name.
+func (o *FsOverlay) writeTemp(k string) (string, error) {
+	mode := os.FileMode(newFileMode)
+	if info, err := os.Stat(k); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(k), "."+filepath.Base(k)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(o.files[k])
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), mode)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// Diff returns a unified diff of the changes to the files of the
+// base FileSystem, with the paths relative to its working directory.
+func (o *FsOverlay) Diff() (string, error) {
+	root := o.key(SelfDir)
+	changed := map[string]bool{}
+	for k := range o.files {
+		changed[k] = true
+	}
+	for k := range o.removed {
+		if !o.base.Exists(k) {
+			continue
+		}
+		err := o.base.Walk(k, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				changed[o.key(path)] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	for _, k := range sortedKeys(changed) {
+		var before, after []byte
+		var err error
+		beforeExists := o.base.Exists(k) && !o.base.IsDir(k)
+		if beforeExists {
+			if before, err = o.base.ReadFile(k); err != nil {
+				return "", err
+			}
+		}
+		after, afterExists := o.files[k]
+		if beforeExists && afterExists && bytes.Equal(before, after) {
+			continue
+		}
+		name := k
+		if rel, err := filepath.Rel(root, k); err == nil {
+			name = rel
+		}
+		name = filepath.ToSlash(name)
+		fromFile, toFile := "a/"+name, "b/"+name
+		if !beforeExists {
+			fromFile = devNull
+		}
+		if !afterExists {
+			toFile = devNull
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(before),
+			B:        splitLines(after),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  diffContext,
+		})
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(diff)
+	}
+	return b.String(), nil
+}
+
+// splitLines splits the content into lines ending with a newline.
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	lines[last] += "\n"
+	return lines
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fsOverlayFile is a File of an FsOverlay, read from or
+// written to memory.
+type fsOverlayFile struct {
+	o        *FsOverlay
+	key      string
+	buf      *bytes.Buffer
+	writable bool
+}
+
+// Read reads the file content.
+func (f *fsOverlayFile) Read(p []byte) (int, error) {
+	if f.buf == nil {
+		f.buf = &bytes.Buffer{}
+	}
+	return f.buf.Read(p)
+}
+
+// Write writes to the file content, until the file is closed.
+func (f *fsOverlayFile) Write(p []byte) (int, error) {
+	if !f.writable {
+		return 0, &fs.PathError{Op: "write", Path: f.key, Err: fs.ErrPermission}
+	}
+	if f.buf == nil {
+		f.buf = &bytes.Buffer{}
+	}
+	return f.buf.Write(p)
+}
+
+// Close writes the content of the file to the FsOverlay
+// if it was created.
+func (f *fsOverlayFile) Close() error {
+	if f.writable && f.buf != nil {
+		f.o.files[f.key] = append([]byte(nil), f.buf.Bytes()...)
+	}
+	return nil
+}
+
+// Stat returns the FileInfo of the file.
+func (f *fsOverlayFile) Stat() (os.FileInfo, error) {
+	info := fsOverlayFileInfo{name: filepath.Base(f.key), dir: f.o.dirs[f.key]}
+	if content, found := f.o.files[f.key]; found {
+		info.size = int64(len(content))
+	}
+	return info, nil
+}
+
+// fsOverlayFileInfo implements os.FileInfo for the files
+// and directories held by an FsOverlay.
+type fsOverlayFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+// Name returns the name of the file
+func (fi fsOverlayFileInfo) Name() string { return fi.name }
+
+// Size returns the size of the file
+func (fi fsOverlayFileInfo) Size() int64 { return fi.size }
+
+// Mode returns the file mode
+func (fi fsOverlayFileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0777
+	}
+	return 0666
+}
+
+// ModTime returns a bogus time
+func (fi fsOverlayFileInfo) ModTime() time.Time { return time.Time{} }
+
+// IsDir returns true if it is a directory
+func (fi fsOverlayFileInfo) IsDir() bool { return fi.dir }
+
+// Sys returns nil
+func (fi fsOverlayFileInfo) Sys() interface{} { return nil }
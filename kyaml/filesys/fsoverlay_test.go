@@ -0,0 +1,221 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeOverlayBase(t *testing.T) (FileSystem, string) {
+	t.Helper()
+	dir := t.TempDir()
+	fSys := MakeFsOnDisk()
+	require.NoError(t, fSys.MkdirAll(filepath.Join(dir, "app", "base")))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "app", "kustomization.yaml"),
+		[]byte("resources:\n- base\nnamePrefix: dev-\n")))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "app", "base", "service.yaml"),
+		[]byte("kind: Service\n")))
+	require.NoError(t, fSys.WriteFile(filepath.Join(dir, "app", "base", "pod.yaml"),
+		[]byte("kind: Pod\n")))
+	return fSys, dir
+}
+
+func TestFsOverlay(t *testing.T) {
+	base, dir := makeOverlayBase(t)
+	o := MakeFsOverlay(base)
+	app := filepath.Join(dir, "app")
+
+	require.NoError(t, o.WriteFile(filepath.Join(app, "kustomization.yaml"),
+		[]byte("resources:\n- base\nnamePrefix: prod-\n")))
+	require.NoError(t, o.RemoveAll(filepath.Join(app, "base", "pod.yaml")))
+	require.NoError(t, o.MkdirAll(filepath.Join(app, "overlay", "patches")))
+	f, err := o.Create(filepath.Join(app, "overlay", "patches", "patch.yaml"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("kind: Service\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the changes are visible in the overlay
+	content, err := o.ReadFile(filepath.Join(app, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n- base\nnamePrefix: prod-\n", string(content))
+	assert.False(t, o.Exists(filepath.Join(app, "base", "pod.yaml")))
+	assert.True(t, o.IsDir(filepath.Join(app, "overlay")))
+	names, err := o.ReadDir(app)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base", "kustomization.yaml", "overlay"}, names)
+	d, name, err := o.CleanedAbs(filepath.Join(app, "overlay", "patches", "patch.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmedDir(filepath.Join(app, "overlay", "patches")), d)
+	assert.Equal(t, "patch.yaml", name)
+	matches, err := o.Glob(filepath.Join(app, "*", "*.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(app, "base", "service.yaml")}, matches)
+
+	var walked []string
+	require.NoError(t, o.Walk(app, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		rel, err := filepath.Rel(app, path)
+		require.NoError(t, err)
+		walked = append(walked, rel)
+		return nil
+	}))
+	assert.Equal(t, []string{
+		".",
+		"base",
+		filepath.Join("base", "service.yaml"),
+		"kustomization.yaml",
+		"overlay",
+		filepath.Join("overlay", "patches"),
+		filepath.Join("overlay", "patches", "patch.yaml"),
+	}, walked)
+
+	// but not in the base
+	assert.True(t, base.Exists(filepath.Join(app, "base", "pod.yaml")))
+	assert.False(t, base.Exists(filepath.Join(app, "overlay")))
+
+	diff, err := o.Diff()
+	require.NoError(t, err)
+	rel, err := filepath.Rel(o.key(SelfDir), app)
+	require.NoError(t, err)
+	rel = filepath.ToSlash(rel)
+	assert.Equal(t, `--- a/`+rel+`/base/pod.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-kind: Pod
+--- a/`+rel+`/kustomization.yaml
++++ b/`+rel+`/kustomization.yaml
+@@ -1,3 +1,3 @@
+ resources:
+ - base
+-namePrefix: dev-
++namePrefix: prod-
+--- /dev/null
++++ b/`+rel+`/overlay/patches/patch.yaml
+@@ -0,0 +1 @@
++kind: Service
+`, diff)
+
+	require.NoError(t, o.Commit())
+	content, err = base.ReadFile(filepath.Join(app, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n- base\nnamePrefix: prod-\n", string(content))
+	content, err = base.ReadFile(filepath.Join(app, "overlay", "patches", "patch.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Service\n", string(content))
+	assert.False(t, base.Exists(filepath.Join(app, "base", "pod.yaml")))
+	names, err = base.ReadDir(app)
+	require.NoError(t, err)
+	// no temporary file is left
+	assert.ElementsMatch(t, []string{"base", "kustomization.yaml", "overlay"}, names)
+
+	diff, err = o.Diff()
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestFsOverlayErrors(t *testing.T) {
+	base, dir := makeOverlayBase(t)
+	o := MakeFsOverlay(base)
+	app := filepath.Join(dir, "app")
+
+	require.Error(t, o.WriteFile(filepath.Join(app, "missing", "file.yaml"), nil))
+	require.Error(t, o.WriteFile(filepath.Join(app, "base"), nil))
+	require.Error(t, o.Mkdir(filepath.Join(app, "base")))
+	require.Error(t, o.MkdirAll(filepath.Join(app, "kustomization.yaml", "dir")))
+
+	// a removed directory can be made again, without its files
+	require.NoError(t, o.RemoveAll(filepath.Join(app, "base")))
+	require.NoError(t, o.Mkdir(filepath.Join(app, "base")))
+	names, err := o.ReadDir(filepath.Join(app, "base"))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+	_, err = o.ReadFile(filepath.Join(app, "base", "service.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFsOverlayCommitAllOrNothing(t *testing.T) {
+	if os.Getuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	base, dir := makeOverlayBase(t)
+	o := MakeFsOverlay(base)
+	app := filepath.Join(dir, "app")
+
+	require.NoError(t, o.WriteFile(filepath.Join(app, "kustomization.yaml"), []byte("changed\n")))
+	require.NoError(t, o.WriteFile(filepath.Join(app, "base", "service.yaml"), []byte("changed\n")))
+	require.NoError(t, os.Chmod(filepath.Join(app, "base"), 0555))
+	defer func() { _ = os.Chmod(filepath.Join(app, "base"), 0755) }()
+
+	require.Error(t, o.Commit())
+	content, err := base.ReadFile(filepath.Join(app, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n- base\nnamePrefix: dev-\n", string(content))
+	names, err := base.ReadDir(app)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"base", "kustomization.yaml"}, names)
+}
+
+func TestFsOverlayCommitRollsBack(t *testing.T) {
+	base, dir := makeOverlayBase(t)
+	o := MakeFsOverlay(base)
+	app := filepath.Join(dir, "app")
+
+	require.NoError(t, o.RemoveAll(filepath.Join(app, "base")))
+	require.NoError(t, o.WriteFile(filepath.Join(app, "kustomization.yaml"), []byte("changed\n")))
+	require.NoError(t, o.MkdirAll(filepath.Join(app, "overlay")))
+	require.NoError(t, o.WriteFile(filepath.Join(app, "overlay", "kustomization.yaml"), []byte("new\n")))
+	// a file where the commit makes a directory
+	require.NoError(t, base.WriteFile(filepath.Join(app, "overlay"), []byte("blocker\n")))
+
+	require.Error(t, o.Commit())
+	content, err := base.ReadFile(filepath.Join(app, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n- base\nnamePrefix: dev-\n", string(content))
+	names, err := base.ReadDir(filepath.Join(app, "base"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pod.yaml", "service.yaml"}, names)
+	names, err = base.ReadDir(app)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"base", "kustomization.yaml", "overlay"}, names)
+}
+
+func TestFsOverlayInMemory(t *testing.T) {
+	base := MakeFsInMemory()
+	require.NoError(t, base.WriteFile("/app/kustomization.yaml", []byte("namePrefix: dev-\n")))
+	o := MakeFsOverlay(base)
+	require.NoError(t, o.WriteFile("app/kustomization.yaml", []byte("namePrefix: prod-\n")))
+	content, err := o.ReadFile("/app/kustomization.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "namePrefix: prod-\n", string(content))
+
+	require.NoError(t, o.Commit())
+	content, err = base.ReadFile("/app/kustomization.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "namePrefix: prod-\n", string(content))
+}
+
+func TestFsOverlayPassThroughAndReset(t *testing.T) {
+	base, dir := makeOverlayBase(t)
+	o := MakeFsOverlay(base)
+	app := filepath.Join(dir, "app")
+	o.PassThrough(filepath.Join(app, "base"))
+
+	require.NoError(t, o.RemoveAll(filepath.Join(app, "base", "pod.yaml")))
+	assert.False(t, base.Exists(filepath.Join(app, "base", "pod.yaml")))
+
+	require.NoError(t, o.WriteFile(filepath.Join(app, "kustomization.yaml"), []byte("changed\n")))
+	o.Reset()
+	content, err := o.ReadFile(filepath.Join(app, "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "resources:\n- base\nnamePrefix: dev-\n", string(content))
+	diff, err := o.Diff()
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
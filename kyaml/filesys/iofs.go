@@ -0,0 +1,100 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package filesys
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+var (
+	_ fs.ReadFileFS = ioFS{}
+	_ fs.ReadDirFS  = ioFS{}
+	_ fs.StatFS     = ioFS{}
+)
+
+// ioFS implements io/fs.FS over the files below a directory of a FileSystem.
+type ioFS struct {
+	fSys FileSystem
+	dir  string
+}
+
+// AsFS returns an io/fs.FS of the files below the directory dir
+// of fSys, e.g. to use fs.WalkDir or fs.Glob on them.
+func AsFS(fSys FileSystem, dir string) fs.FS {
+	return ioFS{fSys: fSys, dir: dir}
+}
+
+// path returns the path in the FileSystem of the given name,
+// or an error if the name is not valid in an fs.FS.
+func (x ioFS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(x.dir, filepath.FromSlash(name)), nil
+}
+
+// Open delegates to FileSystem.Open.
+func (x ioFS) Open(name string) (fs.File, error) {
+	p, err := x.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if !x.fSys.Exists(p) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return x.fSys.Open(p)
+}
+
+// ReadFile delegates to FileSystem.ReadFile.
+func (x ioFS) ReadFile(name string) ([]byte, error) {
+	p, err := x.path("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if !x.fSys.Exists(p) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return x.fSys.ReadFile(p)
+}
+
+// Stat returns the FileInfo of the opened file.
+func (x ioFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := x.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// ReadDir returns the entries of the directory, sorted by name.
+func (x ioFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := x.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !x.fSys.IsDir(p) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	names, err := x.fSys.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	entries := make([]fs.DirEntry, len(names))
+	for i, n := range names {
+		child := n
+		if name != SelfDir {
+			child = name + "/" + n
+		}
+		info, err := x.Stat(child)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = fs.FileInfoToDirEntry(info)
+	}
+	return entries, nil
+}
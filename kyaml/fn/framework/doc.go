@@ -110,6 +110,14 @@
 //
 // Functions may also access environment variables set by the caller.
 //
+// Typed Resources
+//
+// Instead of walking yaml.RNodes, functions may decode resources into Go structs that
+// declare only the fields they need, and write their changes back with MutateResource
+// or TypedMutator. Fields the struct doesn't declare, comments and field order are preserved.
+// SchemaFor and NewFunctionDefinition generate the OpenAPI schema of the functionConfig
+// from its Go type.
+//
 // Building a container image for the function
 //
 // The go program may be built into a container and run as a function.  The framework
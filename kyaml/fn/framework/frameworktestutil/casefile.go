@@ -0,0 +1,238 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package frameworktestutil
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	DefaultCaseFilename = "cases.yaml"
+
+	casesField          = "cases"
+	nameField           = "name"
+	functionConfigField = "functionConfig"
+	inputField          = "input"
+	expectedOutputField = "expectedOutput"
+	expectedErrorField  = "expectedError"
+)
+
+// CaseFileChecker runs table-driven test cases defined in a single YAML file
+// against a function, and compares the output resources to the expected ones.
+//
+// The case file contains a list of cases, each of which provides the
+// functionConfig and input items of a ResourceList, and either the expected
+// output items or the expected error:
+//
+//	cases:
+//	- name: sets replicas
+//	  functionConfig:
+//	    apiVersion: example.com/v1
+//	    kind: Scaler
+//	    spec:
+//	      replicas: 3
+//	  input:
+//	  - apiVersion: apps/v1
+//	    kind: Deployment
+//	    metadata:
+//	      name: app
+//	  expectedOutput:
+//	  - apiVersion: apps/v1
+//	    kind: Deployment
+//	    metadata:
+//	      name: app
+//	    spec:
+//	      replicas: 3
+//	- name: rejects negative replicas
+//	  functionConfig: ...
+//	  expectedError: |
+//	    replicas must not be negative
+//
+// Output resources are matched by their group, version, kind, namespace and
+// name, and a diff is reported for each resource that differs.
+//
+// The same case file can be checked against the function running in-process
+// with ProcessorRunner, and against the built function with ExecRunner or
+// ContainerRunner.
+type CaseFileChecker struct {
+	// CaseFile is the file containing the test cases.
+	// Defaults to "testdata/cases.yaml".
+	CaseFile string
+
+	// UpdateExpectedFromActual if set to true will write the actual results to the
+	// expectedOutput or expectedError of each case in the case file.
+	// This is also enabled by the -update test flag, see RegisterUpdateFlag.
+	UpdateExpectedFromActual bool
+
+	// ErrorAssertionFunc allows you to swap out the logic used to compare the expected error
+	// message from the case file to the actual error message.
+	// By default, it interprets each line of expectedError as a regex that the actual error must match.
+	ErrorAssertionFunc AssertionFunc
+
+	// Runner runs the function under test.
+	Runner Runner
+
+	testCasesRun []string
+}
+
+// Assert runs each case in the case file and verifies that the actual output
+// and error match the expectations of the case.
+func (c *CaseFileChecker) Assert(t *testing.T) bool {
+	t.Helper()
+	if c.CaseFile == "" {
+		c.CaseFile = filepath.Join(DefaultTestDataDirectory, DefaultCaseFilename)
+	}
+	if c.ErrorAssertionFunc == nil {
+		c.ErrorAssertionFunc = RequireEachLineMatches
+	}
+	require.NotNil(t, c.Runner, "CaseFileChecker requires a Runner")
+
+	file, err := yaml.ReadFile(c.CaseFile)
+	require.NoError(t, err)
+	cases, err := file.Pipe(yaml.Lookup(casesField))
+	require.NoError(t, err)
+	require.NotNil(t, cases, "no %s found in %s", casesField, c.CaseFile)
+	elements, err := cases.Elements()
+	require.NoError(t, err)
+
+	c.testCasesRun = []string{}
+	update := c.UpdateExpectedFromActual || updateFlagSet()
+	for _, tc := range elements {
+		var name string
+		if f := tc.Field(nameField); f != nil {
+			name = yaml.GetValue(f.Value)
+		}
+		require.NotEmpty(t, name, "test case in %s is missing a %s", c.CaseFile, nameField)
+		c.testCasesRun = append(c.testCasesRun, name)
+		t.Run(name, func(t *testing.T) {
+			c.runCase(t, tc, update)
+		})
+	}
+	require.NotZero(t, len(c.testCasesRun), "No test cases found in %s", c.CaseFile)
+
+	if update {
+		require.NoError(t, yaml.WriteFile(file, c.CaseFile))
+	}
+	return true
+}
+
+// TestCasesRun returns the names of the test cases that have been run.
+func (c *CaseFileChecker) TestCasesRun() []string {
+	return c.testCasesRun
+}
+
+func (c *CaseFileChecker) runCase(t *testing.T, tc *yaml.RNode, update bool) {
+	t.Helper()
+	expectedOutput := tc.Field(expectedOutputField)
+	expectedError := tc.Field(expectedErrorField)
+	if expectedOutput == nil && expectedError == nil && !update {
+		t.Fatalf("test case must include either %s or %s", expectedOutputField, expectedErrorField)
+	}
+
+	actualOutput, actualError, err := c.run(tc)
+	require.NoError(t, err)
+
+	// Configured to update the expectations instead of comparing them
+	if update {
+		require.NoError(t, updateCase(tc, actualOutput, actualError))
+		t.Skip("Updated expectations for test case")
+	}
+
+	if expectedError != nil {
+		// We expected an error, so make sure there was one
+		require.NotEmptyf(t, actualError, "test expected an error but message was empty")
+		c.ErrorAssertionFunc(t, yaml.GetValue(expectedError.Value), actualError)
+		return
+	}
+	require.Emptyf(t, actualError, "test expected no error but got an error message")
+	expected, err := expectedOutput.Value.Elements()
+	require.NoError(t, err)
+	diff, err := ResourceDiff(expected, actualOutput)
+	require.NoError(t, err)
+	if diff != "" {
+		require.Fail(t, "resources differ", diff)
+	}
+}
+
+// run executes the Runner with the ResourceList of the test case, and returns
+// the output items and error message.
+func (c *CaseFileChecker) run(tc *yaml.RNode) ([]*yaml.RNode, string, error) {
+	var input []*yaml.RNode
+	if f := tc.Field(inputField); f != nil {
+		var err error
+		if input, err = f.Value.Elements(); err != nil {
+			return nil, "", errors.WrapPrefixf(err, "reading %s", inputField)
+		}
+	}
+	var functionConfig *yaml.RNode
+	if f := tc.Field(functionConfigField); f != nil {
+		functionConfig = f.Value.Copy()
+	}
+
+	in := &bytes.Buffer{}
+	err := kio.ByteWriter{
+		Writer:             in,
+		WrappingAPIVersion: kio.ResourceListAPIVersion,
+		WrappingKind:       kio.ResourceListKind,
+		FunctionConfig:     functionConfig,
+	}.Write(copyNodes(input))
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := &bytes.Buffer{}
+	var actualError string
+	if err := c.Runner.Run(in, out); err != nil {
+		actualError = err.Error()
+		if actualError == "" {
+			return nil, "", errors.Errorf("function returned error with empty message")
+		}
+	}
+	if strings.TrimSpace(out.String()) == "" {
+		return nil, actualError, nil
+	}
+	output, err := (&kio.ByteReader{Reader: out, OmitReaderAnnotations: true}).Read()
+	if err != nil && actualError == "" {
+		return nil, "", errors.WrapPrefixf(err, "reading function output")
+	}
+	return output, actualError, nil
+}
+
+// updateCase replaces the expectations of tc with the actual results.
+func updateCase(tc *yaml.RNode, output []*yaml.RNode, actualError string) error {
+	if actualError != "" {
+		value := yaml.NewStringRNode(actualError)
+		if strings.Contains(actualError, "\n") {
+			value.YNode().Style = yaml.LiteralStyle
+		}
+		if err := tc.PipeE(yaml.Clear(expectedOutputField)); err != nil {
+			return err
+		}
+		return tc.PipeE(yaml.SetField(expectedErrorField, value))
+	}
+	items := yaml.NewRNode(&yaml.Node{Kind: yaml.SequenceNode})
+	for _, node := range output {
+		items.YNode().Content = append(items.YNode().Content, node.YNode())
+	}
+	if err := tc.PipeE(yaml.Clear(expectedErrorField)); err != nil {
+		return err
+	}
+	return tc.PipeE(yaml.SetField(expectedOutputField, items))
+}
+
+func copyNodes(nodes []*yaml.RNode) []*yaml.RNode {
+	result := make([]*yaml.RNode, len(nodes))
+	for i := range nodes {
+		result[i] = nodes[i].Copy()
+	}
+	return result
+}
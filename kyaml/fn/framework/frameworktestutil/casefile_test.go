@@ -0,0 +1,198 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package frameworktestutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestCaseFileChecker(t *testing.T) {
+	checker := CaseFileChecker{
+		CaseFile: filepath.FromSlash("testdata/casefile/cases.yaml"),
+		Runner:   ProcessorRunner(testProcessor),
+	}
+	checker.Assert(t)
+	assert.Equal(t,
+		[]string{"annotates resources", "no input"},
+		checker.TestCasesRun())
+}
+
+func TestCaseFileChecker_UpdateExpectedFromActual(t *testing.T) {
+	caseFile := filepath.Join(t.TempDir(), "cases.yaml")
+	require.NoError(t, os.WriteFile(caseFile, []byte(`cases:
+- name: stale expectation
+  input:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: test-1
+  expectedError: something
+- name: new case
+  input:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: test-2
+`), 0600))
+
+	checker := CaseFileChecker{
+		CaseFile:                 caseFile,
+		UpdateExpectedFromActual: true,
+		Runner:                   ProcessorRunner(testProcessor),
+	}
+	// This should result in the test cases being skipped.
+	checker.Assert(t)
+	b, err := os.ReadFile(caseFile)
+	require.NoError(t, err)
+	assert.Equal(t, `cases:
+- name: stale expectation
+  input:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: test-1
+  expectedOutput:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: test-1
+      annotations:
+        updated: "true"
+- name: new case
+  input:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: test-2
+  expectedOutput:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: test-2
+      annotations:
+        updated: "true"
+`, string(b))
+
+	checker.UpdateExpectedFromActual = false
+	// This time should inherently pass
+	checker.Assert(t)
+}
+
+func TestCaseFileChecker_ExecRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires sh")
+	}
+	caseFile := filepath.Join(t.TempDir(), "cases.yaml")
+	require.NoError(t, os.WriteFile(caseFile, []byte(`cases:
+- name: passes resources through
+  input:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: test-1
+  expectedOutput:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: test-1
+`), 0600))
+	checker := CaseFileChecker{CaseFile: caseFile, Runner: ExecRunner("sh", "-c", "cat")}
+	checker.Assert(t)
+
+	// stderr of a failed function is part of the error
+	require.NoError(t, os.WriteFile(caseFile, []byte(`cases:
+- name: fails
+  expectedError: |
+    invalid replicas
+    exit status 1
+`), 0600))
+	checker = CaseFileChecker{
+		CaseFile: caseFile,
+		Runner:   ExecRunner("sh", "-c", "echo invalid replicas >&2; exit 1"),
+	}
+	checker.Assert(t)
+}
+
+func TestResourceDiff(t *testing.T) {
+	parse := func(s string) []*yaml.RNode {
+		t.Helper()
+		nodes, err := readResources(s)
+		require.NoError(t, err)
+		return nodes
+	}
+	expected := parse(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`)
+
+	diff, err := ResourceDiff(expected, parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: ClusterIP
+`))
+	require.NoError(t, err)
+	assert.Empty(t, diff, "order should not matter")
+
+	diff, err = ResourceDiff(expected, parse(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: NodePort
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: c
+`))
+	require.NoError(t, err)
+	assert.Equal(t, `resource Service.v1.[noGrp]/a.[noNs] differs:
+--- expected
++++ actual
+@@ -3,4 +3,4 @@
+ metadata:
+   name: a
+ spec:
+-  type: ClusterIP
++  type: NodePort
+
+missing resource ConfigMap.v1.[noGrp]/b.[noNs]:
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: b
+
+unexpected resource Secret.v1.[noGrp]/c.[noNs]:
+  apiVersion: v1
+  kind: Secret
+  metadata:
+    name: c
+
+`, diff)
+}
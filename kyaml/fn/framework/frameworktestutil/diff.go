@@ -0,0 +1,70 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package frameworktestutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// RequireResourcesEqual is an AssertionFunc that parses expected and actual as
+// ResourceLists or streams of resources, and compares them resource by resource.
+// On a mismatch the failure message contains a diff of each resource that
+// differs, rather than a single diff of the whole output.
+func RequireResourcesEqual(t *testing.T, expected, actual string) {
+	t.Helper()
+	expectedNodes, err := readResources(expected)
+	require.NoError(t, err, "failed to parse expected output")
+	actualNodes, err := readResources(actual)
+	require.NoError(t, err, "failed to parse actual output:\n%s", actual)
+	diff, err := ResourceDiff(expectedNodes, actualNodes)
+	require.NoError(t, err)
+	if diff != "" {
+		require.Fail(t, "resources differ", diff)
+	}
+}
+
+func readResources(s string) ([]*yaml.RNode, error) {
+	return (&kio.ByteReader{
+		Reader:                strings.NewReader(s),
+		OmitReaderAnnotations: true,
+	}).Read()
+}
+
+// ResourceDiff compares expected and actual resource by resource, matching
+// resources by their group, version, kind, namespace and name.  It returns an
+// empty string if they are equal, and otherwise a report listing the missing
+// and unexpected resources and a unified diff of each resource that changed.
+// The order of the resources is not compared.
+func ResourceDiff(expected, actual []*yaml.RNode) (string, error) {
+	changes, err := kio.DiffResources(expected, actual)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, c := range changes {
+		switch {
+		case c.After == "":
+			fmt.Fprintf(&b, "missing resource %s:\n%s\n", c.ID, indent(c.Before))
+		case c.Before == "":
+			fmt.Fprintf(&b, "unexpected resource %s:\n%s\n", c.ID, indent(c.After))
+		default:
+			diff, err := c.UnifiedDiff("expected", "actual")
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "resource %s differs:\n%s\n", c.ID, diff)
+		}
+	}
+	return b.String(), nil
+}
+
+func indent(s string) string {
+	return "  " + strings.ReplaceAll(strings.TrimSuffix(s, "\n"), "\n", "\n  ") + "\n"
+}
@@ -6,6 +6,7 @@ package frameworktestutil
 
 import (
 	"bytes"
+	"flag"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -28,6 +29,25 @@ const (
 	DefaultErrorFilename       = "errors.txt"
 )
 
+// updateFlag is the -update test flag, nil unless registered by
+// RegisterUpdateFlag.
+var updateFlag *bool
+
+// RegisterUpdateFlag registers the -update test flag on flag.CommandLine.
+// When set, all checkers write the actual results to their expectations
+// instead of comparing them, as if UpdateExpectedFromActual were true.
+// Call it from an init function or TestMain of the test package, before
+// the flags are parsed, and at most once per binary.
+func RegisterUpdateFlag() {
+	updateFlag = flag.Bool("update", false,
+		"update the expectations of function tests from the actual results")
+}
+
+// updateFlagSet returns whether the -update test flag is registered and set.
+func updateFlagSet() bool {
+	return updateFlag != nil && *updateFlag
+}
+
 // CommandResultsChecker tests a command-wrapped function by running it with predefined inputs
 // and comparing the outputs to expected results.
 type CommandResultsChecker struct {
@@ -50,11 +70,13 @@ type CommandResultsChecker struct {
 
 	// UpdateExpectedFromActual if set to true will write the actual results to the
 	// expected testdata files.  This is useful for updating test data.
+	// This is also enabled by the -update test flag, see RegisterUpdateFlag.
 	UpdateExpectedFromActual bool
 
 	// OutputAssertionFunc allows you to swap out the logic used to compare the expected output
 	// from the fixture file to the actual output.
 	// By default, it performs a string comparison after normalizing whitespace.
+	// Use RequireResourcesEqual to compare and diff the output resource by resource.
 	OutputAssertionFunc AssertionFunc
 
 	// ErrorAssertionFunc allows you to swap out the logic used to compare the expected error
@@ -155,6 +177,7 @@ type ProcessorResultsChecker struct {
 
 	// UpdateExpectedFromActual if set to true will write the actual results to the
 	// expected testdata files.  This is useful for updating test data.
+	// This is also enabled by the -update test flag, see RegisterUpdateFlag.
 	UpdateExpectedFromActual bool
 
 	// InputFilename is the name of the file containing the ResourceList input.
@@ -165,6 +188,7 @@ type ProcessorResultsChecker struct {
 	// OutputAssertionFunc allows you to swap out the logic used to compare the expected output
 	// from the fixture file to the actual output.
 	// By default, it performs a string comparison after normalizing whitespace.
+	// Use RequireResourcesEqual to compare and diff the output resource by resource.
 	OutputAssertionFunc AssertionFunc
 
 	// ErrorAssertionFunc allows you to swap out the logic used to compare the expected error
@@ -331,7 +355,7 @@ func (rc *checkerCore) recordTestCase(s string) {
 }
 
 func (rc *checkerCore) shouldUpdateFixtures() bool {
-	return rc.updateExpectedFromActual
+	return rc.updateExpectedFromActual || updateFlagSet()
 }
 
 func (rc *checkerCore) updateFixtures(t *testing.T, actualOutput string, actualError string) {
@@ -4,6 +4,7 @@
 package frameworktestutil
 
 import (
+	"flag"
 	"path/filepath"
 	"testing"
 
@@ -66,3 +67,11 @@ func testProcessor() framework.ResourceListProcessor {
 		}),
 	}
 }
+
+func TestRegisterUpdateFlag(t *testing.T) {
+	require.Nil(t, flag.Lookup("update"), "the flag must not be registered on import")
+	require.False(t, updateFlagSet())
+	RegisterUpdateFlag()
+	require.NotNil(t, flag.Lookup("update"))
+	require.False(t, updateFlagSet())
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package frameworktestutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// Runner runs the function under test.  It reads a ResourceList from input
+// and writes the resulting ResourceList to output.
+type Runner interface {
+	Run(input io.Reader, output io.Writer) error
+}
+
+// RunnerFunc is a function implementing Runner.
+type RunnerFunc func(input io.Reader, output io.Writer) error
+
+// Run implements Runner.
+func (f RunnerFunc) Run(input io.Reader, output io.Writer) error {
+	return f(input, output)
+}
+
+// ProcessorRunner returns a Runner executing the processor in-process.
+func ProcessorRunner(processor func() framework.ResourceListProcessor) Runner {
+	return RunnerFunc(func(input io.Reader, output io.Writer) error {
+		return framework.Execute(processor(), &kio.ByteReadWriter{Reader: input, Writer: output})
+	})
+}
+
+// ExecRunner returns a Runner executing the function binary at path with args,
+// e.g. the function built by `go build`.  The binary is run in the current
+// working directory.
+func ExecRunner(path string, args ...string) Runner {
+	return RunnerFunc(func(input io.Reader, output io.Writer) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		stderr := &bytes.Buffer{}
+		f := &exec.Filter{Path: path, Args: args, WorkingDir: wd, Stderr: stderr}
+		return withStderr(f.Run(input, output), stderr)
+	})
+}
+
+// ContainerRunner returns a Runner executing the function in a container of
+// image, e.g. one built by `docker build`.  The container is run with the
+// named runtime, or the default runtime if runtime is empty.
+func ContainerRunner(image, runtime string) Runner {
+	return RunnerFunc(func(input io.Reader, output io.Writer) error {
+		r, err := container.GetRuntime(runtime)
+		if err != nil {
+			return err
+		}
+		stderr := &bytes.Buffer{}
+		f := container.NewContainer(runtimeutil.ContainerSpec{Image: image}, "nobody")
+		f.Runtime = r
+		f.Exec.Stderr = stderr
+		return withStderr(f.Run(input, output), stderr)
+	})
+}
+
+// withStderr adds the stderr of a failed function to err, since that is
+// where functions report their errors.
+func withStderr(err error, stderr *bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		return errors.Wrap(err)
+	}
+	return errors.WrapPrefixf(err, "%s", msg)
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package framework
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// SchemaFor returns the OpenAPI v3 schema of T, as it is decoded by
+// LoadFunctionConfig and DecodeResource.  It can be used to implement
+// ValidationSchemaProvider:
+//
+//	func (t MyType) Schema() (*spec.Schema, error) {
+//		return framework.SchemaFor[MyType](), nil
+//	}
+func SchemaFor[T any]() *spec.Schema {
+	return SchemaForType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// SchemaForType returns the OpenAPI v3 schema of values of type t.
+//
+// Struct fields are named after their json tag, or yaml tag if there is none.
+// Fields that are neither pointers nor omitempty are required.  Embedded
+// structs without a name are inlined.  Recursive types are not expanded.
+func SchemaForType(t reflect.Type) *spec.Schema {
+	return (&schemaBuilder{visiting: map[reflect.Type]bool{}}).schema(t)
+}
+
+// NewFunctionDefinition returns the KRMFunctionDefinition of a function
+// configured by the given gvk, with the schema of api.
+func NewFunctionDefinition(gvk resid.Gvk, api interface{}) *KRMFunctionDefinition {
+	name := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		name += "." + gvk.Group
+	}
+	return &KRMFunctionDefinition{
+		TypeMeta: yaml.TypeMeta{
+			APIVersion: FunctionDefinitionGroupVersion,
+			Kind:       FunctionDefinitionKind,
+		},
+		ObjectMeta: yaml.ObjectMeta{NameMeta: yaml.NameMeta{Name: name}},
+		Spec: KrmFunctionDefinitionSpec{
+			Group: gvk.Group,
+			Names: KRMFunctionNames{Kind: gvk.Kind},
+			Versions: []KRMFunctionVersion{{
+				Name: gvk.Version,
+				Schema: &KRMFunctionValidation{
+					OpenAPIV3Schema: SchemaForType(reflect.TypeOf(api)),
+				},
+			}},
+		},
+	}
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rnodeType   = reflect.TypeOf(yaml.RNode{})
+	nodeType    = reflect.TypeOf(yaml.Node{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+type schemaBuilder struct {
+	// visiting holds the struct types being expanded, to stop recursion
+	visiting map[reflect.Type]bool
+}
+
+func preserveUnknownFields() *spec.Schema {
+	s := &spec.Schema{}
+	s.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+	return s
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *spec.Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return spec.DateTimeProperty()
+	case rnodeType, nodeType, rawJSONType:
+		return preserveUnknownFields()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return spec.BoolProperty()
+	case reflect.String:
+		return spec.StringProperty()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return spec.Int32Property()
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return spec.Int64Property()
+	case reflect.Float32:
+		return spec.Float32Property()
+	case reflect.Float64:
+		return spec.Float64Property()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoded as base64 by encoding/json
+			return &spec.Schema{SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"string"}, Format: "byte"}}
+		}
+		return spec.ArrayProperty(b.schema(t.Elem()))
+	case reflect.Map:
+		return spec.MapProperty(b.schema(t.Elem()))
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		return preserveUnknownFields()
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *spec.Schema {
+	s := &spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}}
+	if b.visiting[t] {
+		return s
+	}
+	b.visiting[t] = true
+	defer delete(b.visiting, t)
+
+	b.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+// addFields adds the properties for the fields of struct t to s.
+func (b *schemaBuilder) addFields(s *spec.Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, skip := fieldName(f)
+		if skip {
+			continue
+		}
+		ft := f.Type
+		if name == "" && f.Anonymous {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if s.Properties == nil {
+			s.Properties = map[string]spec.Schema{}
+		}
+		s.Properties[name] = *b.schema(ft)
+		if !omitEmpty && ft.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// fieldName returns the serialized name of f from its json or yaml tag,
+// whether it is omitempty, and whether it is skipped entirely.
+func fieldName(f reflect.StructField) (string, bool, bool) {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		tag = f.Tag.Get("yaml")
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	var omitEmpty bool
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
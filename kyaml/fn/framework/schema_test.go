@@ -0,0 +1,115 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package framework_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+type tree struct {
+	Value    string  `json:"value"`
+	Children []*tree `json:"children,omitempty"`
+}
+
+type exampleConfig struct {
+	yaml.ResourceMeta `json:",inline"`
+	Spec              struct {
+		Replicas int               `json:"replicas"`
+		Image    *string           `json:"image"`
+		Ratio    float64           `json:"ratio,omitempty"`
+		Data     []byte            `json:"data,omitempty"`
+		Env      map[string]string `json:"env,omitempty"`
+		Tree     tree              `json:"tree,omitempty"`
+		Extra    interface{}       `json:"extra,omitempty"`
+		Internal string            `json:"-"`
+	} `json:"spec"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	s := framework.SchemaFor[exampleConfig]()
+	b, err := k8syaml.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `properties:
+  apiVersion:
+    type: string
+  kind:
+    type: string
+  metadata:
+    properties:
+      annotations:
+        additionalProperties:
+          type: string
+        type: object
+      labels:
+        additionalProperties:
+          type: string
+        type: object
+      name:
+        type: string
+      namespace:
+        type: string
+    type: object
+  spec:
+    properties:
+      data:
+        format: byte
+        type: string
+      env:
+        additionalProperties:
+          type: string
+        type: object
+      extra:
+        x-kubernetes-preserve-unknown-fields: true
+      image:
+        type: string
+      ratio:
+        format: double
+        type: number
+      replicas:
+        format: int64
+        type: integer
+      tree:
+        properties:
+          children:
+            items:
+              type: object
+            type: array
+          value:
+            type: string
+        required:
+        - value
+        type: object
+    required:
+    - replicas
+    type: object
+required:
+- spec
+type: object
+`, string(b))
+}
+
+func TestNewFunctionDefinition(t *testing.T) {
+	gvk := resid.NewGvk("example.com", "v1alpha1", "Example")
+	def := framework.NewFunctionDefinition(gvk, exampleConfig{})
+	b, err := k8syaml.Marshal(def)
+	require.NoError(t, err)
+
+	// the definition can be loaded back by SchemaFromFunctionDefinition
+	s, err := framework.SchemaFromFunctionDefinition(gvk, string(b))
+	require.NoError(t, err)
+	assert.Equal(t, framework.SchemaFor[exampleConfig](), s)
+
+	def2 := &framework.KRMFunctionDefinition{}
+	require.NoError(t, k8syaml.Unmarshal(b, def2))
+	assert.Equal(t, "example.example.com", def2.Name)
+	assert.Equal(t, framework.FunctionDefinitionKind, def2.Kind)
+	assert.Equal(t, "Example", def2.Spec.Names.Kind)
+}
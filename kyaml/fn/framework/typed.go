@@ -0,0 +1,178 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package framework
+
+import (
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/kustomize/kyaml/yaml/merge3"
+	"sigs.k8s.io/kustomize/kyaml/yaml/walk"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// DecodeResource decodes the resource into a new T.
+// Like LoadFunctionConfig, this uses sigs.k8s.io/yaml, so T may use json tags
+// and embed types from k8s.io/api. T only needs to declare the fields the
+// function works with, e.g.
+//
+//	type Deployment struct {
+//		yaml.ResourceMeta `json:",inline"`
+//		Spec struct {
+//			Replicas *int `json:"replicas,omitempty"`
+//		} `json:"spec,omitempty"`
+//	}
+func DecodeResource[T any](node *yaml.RNode) (*T, error) {
+	obj := new(T)
+	s, err := node.String()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if err := k8syaml.Unmarshal([]byte(s), obj); err != nil {
+		return nil, errors.WrapPrefixf(err, "decoding %s", resourceDescription(node))
+	}
+	return obj, nil
+}
+
+// MutateResource decodes the resource into a T, calls mutate with it, and writes
+// the changes made by mutate back to the resource.
+//
+// Only the fields changed by mutate are written back, using a three-way merge
+// between the resource, the decoded T before the mutation and the T after it.
+// Fields of the resource that T doesn't declare are left untouched, and the
+// comments and field order of the resource are preserved.
+func MutateResource[T any](node *yaml.RNode, mutate func(*T) error) error {
+	obj, err := DecodeResource[T](node)
+	if err != nil {
+		return err
+	}
+	original, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+	if err = mutate(obj); err != nil {
+		return err
+	}
+	updated, err := encodeResource(obj)
+	if err != nil {
+		return err
+	}
+	prior := node.Copy()
+	result, err := walk.Walker{
+		Visitor:               merge3.Visitor{},
+		VisitKeysAsScalars:    true,
+		InferAssociativeLists: true,
+		Sources:               []*yaml.RNode{node, original, updated},
+	}.Walk()
+	if err != nil {
+		return errors.WrapPrefixf(err, "updating %s", resourceDescription(node))
+	}
+	if result == nil {
+		return errors.Errorf("updating %s: resource was cleared", resourceDescription(node))
+	}
+	if err = clearEmptied(result, prior, original, updated); err != nil {
+		return err
+	}
+	if result.YNode() != node.YNode() {
+		*node.YNode() = *result.YNode()
+	}
+	return nil
+}
+
+// clearEmptied removes the empty maps and lists of dest that the mutation didn't
+// ask for: ones that were present in original but are missing from updated (e.g.
+// because they are omitempty in the typed struct), and ones that were added to
+// dest although they are unchanged between original and updated (e.g. because
+// they are not omitempty in the typed struct).  prior is dest before the merge.
+func clearEmptied(dest, prior, original, updated *yaml.RNode) error {
+	if dest.YNode().Kind != yaml.MappingNode || original == nil {
+		return nil
+	}
+	fields, err := dest.Fields()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, name := range fields {
+		o := fieldValue(original, name)
+		if o == nil {
+			continue
+		}
+		p, u := fieldValue(prior, name), fieldValue(updated, name)
+		value := dest.Field(name).Value
+		switch value.YNode().Kind {
+		case yaml.MappingNode, yaml.SequenceNode:
+			if len(value.YNode().Content) == 0 && (u == nil || p == nil) {
+				if err = dest.PipeE(yaml.Clear(name)); err != nil {
+					return errors.Wrap(err)
+				}
+				continue
+			}
+		}
+		if err = clearEmptied(value, p, o, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldValue returns the value of the field name of node, or nil if either is missing.
+func fieldValue(node *yaml.RNode, name string) *yaml.RNode {
+	if node == nil {
+		return nil
+	}
+	if f := node.Field(name); f != nil {
+		return f.Value
+	}
+	return nil
+}
+
+// encodeResource encodes obj into a new RNode.
+func encodeResource(obj interface{}) (*yaml.RNode, error) {
+	b, err := k8syaml.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return yaml.Parse(string(b))
+}
+
+func resourceDescription(node *yaml.RNode) string {
+	return node.GetKind() + " " + node.GetName()
+}
+
+// TypedMutator is a kio.Filter that decodes the items matched by Selector into a T,
+// invokes Mutate with it, and writes the changes back using MutateResource.
+//
+//	filter := framework.TypedMutator[Deployment]{
+//		Selector: &framework.Selector{Kinds: []string{"Deployment"}},
+//		Mutate: func(d *Deployment) error {
+//			d.Spec.Replicas = &replicas
+//			return nil
+//		},
+//	}
+type TypedMutator[T any] struct {
+	// Selector selects the items to mutate.  All items are mutated if it is nil.
+	Selector *Selector
+
+	// Mutate is called with each selected item.
+	Mutate func(*T) error
+}
+
+// Filter implements kio.Filter.
+func (m TypedMutator[T]) Filter(items []*yaml.RNode) ([]*yaml.RNode, error) {
+	if m.Mutate == nil {
+		return nil, errors.Errorf("TypedMutator requires a Mutate function")
+	}
+	selected := items
+	if m.Selector != nil {
+		var err error
+		if selected, err = m.Selector.Filter(items); err != nil {
+			return nil, err
+		}
+	}
+	for i := range selected {
+		if err := MutateResource(selected[i], m.Mutate); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
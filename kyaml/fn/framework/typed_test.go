@@ -0,0 +1,152 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package framework_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+type container struct {
+	Name  string `json:"name"`
+	Image string `json:"image,omitempty"`
+}
+
+type deployment struct {
+	yaml.ResourceMeta `json:",inline"`
+	Spec              struct {
+		Replicas *int `json:"replicas,omitempty"`
+		Template struct {
+			Spec struct {
+				Containers []container `json:"containers,omitempty"`
+			} `json:"spec,omitempty"`
+		} `json:"template,omitempty"`
+	} `json:"spec,omitempty"`
+}
+
+const typedInput = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo # the name
+  annotations:
+    a: b
+spec:
+  # the number of replicas
+  replicas: 1
+  strategy:
+    type: Recreate
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1 # the app image
+        ports:
+        - containerPort: 80
+      - name: sidecar
+        image: sidecar:v1
+`
+
+func TestMutateResource(t *testing.T) {
+	node := yaml.MustParse(typedInput)
+	err := framework.MutateResource(node, func(d *deployment) error {
+		r := 3
+		d.Spec.Replicas = &r
+		d.Spec.Template.Spec.Containers[1].Image = "sidecar:v2"
+		delete(d.Annotations, "a")
+		d.Labels = map[string]string{"app": "foo"}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo # the name
+  labels:
+    app: foo
+spec:
+  # the number of replicas
+  replicas: 3
+  strategy:
+    type: Recreate
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:v1 # the app image
+        ports:
+        - containerPort: 80
+      - name: sidecar
+        image: sidecar:v2
+`, node.MustString())
+}
+
+func TestMutateResource_noChange(t *testing.T) {
+	node := yaml.MustParse(typedInput)
+	err := framework.MutateResource(node, func(d *deployment) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, typedInput, node.MustString())
+}
+
+func TestMutateResource_notOmitEmpty(t *testing.T) {
+	type service struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Ports []map[string]int `json:"ports"`
+		} `json:"spec"`
+	}
+	node := yaml.MustParse("kind: Service\nmetadata:\n  name: foo\n")
+	err := framework.MutateResource(node, func(s *service) error {
+		s.Metadata.Name = "bar"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Service\nmetadata:\n  name: bar\n", node.MustString())
+}
+
+func TestDecodeResource(t *testing.T) {
+	d, err := framework.DecodeResource[deployment](yaml.MustParse(typedInput))
+	require.NoError(t, err)
+	assert.Equal(t, "foo", d.Name)
+	assert.Equal(t, 1, *d.Spec.Replicas)
+	assert.Equal(t, []container{{Name: "app", Image: "app:v1"}, {Name: "sidecar", Image: "sidecar:v1"}},
+		d.Spec.Template.Spec.Containers)
+
+	_, err = framework.DecodeResource[deployment](yaml.MustParse(`
+kind: Deployment
+metadata:
+  name: bad
+spec:
+  replicas: many
+`))
+	assert.ErrorContains(t, err, "decoding Deployment bad")
+}
+
+func TestTypedMutator(t *testing.T) {
+	items := []*yaml.RNode{
+		yaml.MustParse(typedInput),
+		yaml.MustParse("apiVersion: v1\nkind: Service\nmetadata:\n  name: foo\n"),
+	}
+	rl := framework.ResourceList{Items: items}
+	require.NoError(t, rl.Filter(framework.TypedMutator[deployment]{
+		Selector: &framework.Selector{Kinds: []string{"Deployment"}},
+		Mutate: func(d *deployment) error {
+			r := 5
+			d.Spec.Replicas = &r
+			return nil
+		},
+	}))
+	require.Len(t, rl.Items, 2)
+	d, err := framework.DecodeResource[deployment](rl.Items[0])
+	require.NoError(t, err)
+	assert.Equal(t, 5, *d.Spec.Replicas)
+	assert.Equal(t, "apiVersion: v1\nkind: Service\nmetadata:\n  name: foo\n", rl.Items[1].MustString())
+}
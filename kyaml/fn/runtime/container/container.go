@@ -5,6 +5,7 @@ package container
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -128,6 +129,10 @@ type Filter struct {
 	Exec runtimeexec.Filter
 
 	UIDGID string
+
+	// Runtime is the container runtime used to run the image.  If unset,
+	// it is selected by GetRuntime("").
+	Runtime Runtime `yaml:"-"`
 }
 
 func (c Filter) String() string {
@@ -147,6 +152,15 @@ func (c *Filter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	return c.Exec.Filter(nodes)
 }
 
+// Run runs the container with the ResourceList read from reader, and writes
+// the resulting ResourceList to writer.
+func (c *Filter) Run(reader io.Reader, writer io.Writer) error {
+	if err := c.setupExec(); err != nil {
+		return err
+	}
+	return c.Exec.Run(reader, writer)
+}
+
 func (c *Filter) setupExec() error {
 	// don't init 2x
 	if c.Exec.Path != "" {
@@ -161,6 +175,14 @@ func (c *Filter) setupExec() error {
 		c.Exec.WorkingDir = wd
 	}
 
+	if c.Runtime == nil {
+		r, err := GetRuntime("")
+		if err != nil {
+			return err
+		}
+		c.Runtime = r
+	}
+
 	path, args := c.getCommand()
 	c.Exec.Path = path
 	c.Exec.Args = args
@@ -173,31 +195,33 @@ func (c *Filter) getCommand() (string, []string) {
 	if c.ContainerSpec.Network {
 		network = runtimeutil.NetworkNameHost
 	}
-	// run the container using docker.  this is simpler than using the docker
-	// libraries, and ensures things like auth work the same as if the container
-	// was run from the cli.
+	// run the container using the runtime cli.  this is simpler than using the
+	// runtime libraries, and ensures things like auth work the same as if the
+	// container was run from the cli.
 	args := []string{"run",
-		"--rm",                                              // delete the container afterward
-		"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR", // attach stdin, stdout, stderr
-		"--network", string(network),
+		"--rm", // delete the container afterward
+	}
+	args = append(args, c.Runtime.AttachFlags()...) // attach stdin, stdout, stderr
+	args = append(args, c.Runtime.NetworkFlags(network)...)
 
-		// added security options
-		"--user", c.UIDGID,
+	// added security options
+	args = append(args, c.Runtime.UserFlags(c.UIDGID)...)
+	args = append(args,
 		"--security-opt=no-new-privileges", // don't allow the user to escalate privileges
 		// note: don't make fs readonly because things like heredoc rely on writing tmp files
-	}
+	)
 
 	for _, storageMount := range c.StorageMounts {
 		// convert declarative relative paths to absolute (otherwise docker will throw an error)
 		if !filepath.IsAbs(storageMount.Src) {
 			storageMount.Src = filepath.Join(c.Exec.WorkingDir, storageMount.Src)
 		}
-		args = append(args, "--mount", storageMount.String())
+		args = append(args, c.Runtime.MountFlags(storageMount)...)
 	}
 
-	args = append(args, runtimeutil.NewContainerEnvFromStringSlice(c.Env).GetDockerFlags()...)
+	args = append(args, c.Runtime.EnvFlags(runtimeutil.NewContainerEnvFromStringSlice(c.Env))...)
 	a := append(args, c.Image) //nolint:gocritic
-	return "docker", a
+	return c.Runtime.Command(), a
 }
 
 // NewContainer returns a new container filter
@@ -0,0 +1,166 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+// RuntimeEnvVar is the environment variable selecting the container runtime
+// when none is set explicitly.
+const RuntimeEnvVar = "KUSTOMIZE_FN_CONTAINER_RUNTIME"
+
+// Runtime translates a container function invocation into the command line
+// of a container runtime CLI.
+type Runtime interface {
+	// Command returns the name of the runtime executable.
+	Command() string
+
+	// AttachFlags returns the flags attaching stdin, stdout and stderr.
+	AttachFlags() []string
+
+	// NetworkFlags returns the flags running the container in network.
+	NetworkFlags(network runtimeutil.ContainerNetworkName) []string
+
+	// UserFlags returns the flags running the container as uidgid, which is
+	// either "nobody" or "uid:gid".
+	UserFlags(uidgid string) []string
+
+	// MountFlags returns the flags mounting m into the container.
+	MountFlags(m runtimeutil.StorageMount) []string
+
+	// EnvFlags returns the flags exposing env to the container.
+	EnvFlags(env *runtimeutil.ContainerEnv) []string
+}
+
+// runtimes are the supported runtimes by name.
+var runtimes = map[string]Runtime{
+	"docker":  Docker{},
+	"podman":  Podman{},
+	"nerdctl": Nerdctl{},
+}
+
+// RuntimeNames returns the names of the supported runtimes.
+func RuntimeNames() []string {
+	var names []string
+	for name := range runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetRuntime returns the runtime called name.  If name is empty the runtime
+// named by RuntimeEnvVar is returned, or Docker if that is unset too.
+func GetRuntime(name string) (Runtime, error) {
+	if name == "" {
+		name = os.Getenv(RuntimeEnvVar)
+	}
+	if name == "" {
+		return Docker{}, nil
+	}
+	r, ok := runtimes[name]
+	if !ok {
+		return nil, errors.Errorf(
+			"unsupported container runtime %q, must be one of %s",
+			name, strings.Join(RuntimeNames(), ", "))
+	}
+	return r, nil
+}
+
+// Docker runs container functions with the docker CLI.
+type Docker struct{}
+
+func (Docker) Command() string {
+	return "docker"
+}
+
+func (Docker) AttachFlags() []string {
+	return []string{"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR"}
+}
+
+func (Docker) NetworkFlags(network runtimeutil.ContainerNetworkName) []string {
+	return []string{"--network", string(network)}
+}
+
+func (Docker) UserFlags(uidgid string) []string {
+	return []string{"--user", uidgid}
+}
+
+func (Docker) MountFlags(m runtimeutil.StorageMount) []string {
+	return []string{"--mount", m.String()}
+}
+
+func (Docker) EnvFlags(env *runtimeutil.ContainerEnv) []string {
+	return env.GetDockerFlags()
+}
+
+// Podman runs container functions with the podman CLI.  When running as the
+// current user the user namespace is kept, so that files written to mounts
+// are owned by the user with rootless podman.
+type Podman struct{}
+
+func (Podman) Command() string {
+	return "podman"
+}
+
+func (Podman) AttachFlags() []string {
+	return Docker{}.AttachFlags()
+}
+
+func (Podman) NetworkFlags(network runtimeutil.ContainerNetworkName) []string {
+	return Docker{}.NetworkFlags(network)
+}
+
+func (Podman) UserFlags(uidgid string) []string {
+	if uidgid == "nobody" {
+		return []string{"--user", uidgid}
+	}
+	return []string{"--user", uidgid, "--userns=keep-id"}
+}
+
+func (Podman) MountFlags(m runtimeutil.StorageMount) []string {
+	opts := "type=" + m.MountType + ",source=" + m.Src + ",target=" + m.DstPath
+	if !m.ReadWriteMode {
+		opts += ",ro=true"
+	}
+	return []string{"--mount", opts}
+}
+
+func (Podman) EnvFlags(env *runtimeutil.ContainerEnv) []string {
+	return env.GetDockerFlags()
+}
+
+// Nerdctl runs container functions with the containerd nerdctl CLI, which
+// attaches the standard streams of a non-detached container by default.
+type Nerdctl struct{}
+
+func (Nerdctl) Command() string {
+	return "nerdctl"
+}
+
+func (Nerdctl) AttachFlags() []string {
+	return []string{"-i"}
+}
+
+func (Nerdctl) NetworkFlags(network runtimeutil.ContainerNetworkName) []string {
+	return Docker{}.NetworkFlags(network)
+}
+
+func (Nerdctl) UserFlags(uidgid string) []string {
+	return Docker{}.UserFlags(uidgid)
+}
+
+func (Nerdctl) MountFlags(m runtimeutil.StorageMount) []string {
+	return Docker{}.MountFlags(m)
+}
+
+func (Nerdctl) EnvFlags(env *runtimeutil.ContainerEnv) []string {
+	return env.GetDockerFlags()
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package container
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+func TestGetRuntime(t *testing.T) {
+	t.Setenv(RuntimeEnvVar, "")
+	r, err := GetRuntime("")
+	require.NoError(t, err)
+	assert.Equal(t, Docker{}, r)
+
+	r, err = GetRuntime("nerdctl")
+	require.NoError(t, err)
+	assert.Equal(t, Nerdctl{}, r)
+
+	t.Setenv(RuntimeEnvVar, "podman")
+	r, err = GetRuntime("")
+	require.NoError(t, err)
+	assert.Equal(t, Podman{}, r)
+
+	// an explicit name takes precedence over the environment
+	r, err = GetRuntime("docker")
+	require.NoError(t, err)
+	assert.Equal(t, Docker{}, r)
+
+	_, err = GetRuntime("rkt")
+	assert.EqualError(t, err,
+		`unsupported container runtime "rkt", must be one of docker, nerdctl, podman`)
+}
+
+func TestFilter_getCommandRuntimes(t *testing.T) {
+	// use getAbsFilePath for Windows filepath handling
+	ro := getAbsFilePath(string(filepath.Separator), "mount", "path")
+	rw := getAbsFilePath(string(filepath.Separator), "mount", "pathrw")
+	spec := runtimeutil.ContainerSpec{
+		Image:   "example.com:version",
+		Network: true,
+		StorageMounts: []runtimeutil.StorageMount{
+			{MountType: "bind", Src: ro, DstPath: "/local/"},
+			{MountType: "bind", Src: rw, DstPath: "/localrw/", ReadWriteMode: true},
+		},
+		Env: []string{"FOO=bar"},
+	}
+	var tests = []struct {
+		name         string
+		runtime      Runtime
+		uidgid       string
+		expectedPath string
+		expectedArgs []string
+	}{
+		{
+			name:         "docker",
+			runtime:      Docker{},
+			uidgid:       "1:2",
+			expectedPath: "docker",
+			expectedArgs: []string{
+				"run", "--rm",
+				"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR",
+				"--network", "host",
+				"--user", "1:2",
+				"--security-opt=no-new-privileges",
+				"--mount", "type=bind,source=" + ro + ",target=/local/,readonly",
+				"--mount", "type=bind,source=" + rw + ",target=/localrw/",
+				"-e", "FOO=bar", "-e", "LOG_TO_STDERR=true", "-e", "STRUCTURED_RESULTS=true",
+				"example.com:version",
+			},
+		},
+		{
+			name:         "podman",
+			runtime:      Podman{},
+			uidgid:       "1:2",
+			expectedPath: "podman",
+			expectedArgs: []string{
+				"run", "--rm",
+				"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR",
+				"--network", "host",
+				"--user", "1:2", "--userns=keep-id",
+				"--security-opt=no-new-privileges",
+				"--mount", "type=bind,source=" + ro + ",target=/local/,ro=true",
+				"--mount", "type=bind,source=" + rw + ",target=/localrw/",
+				"-e", "FOO=bar", "-e", "LOG_TO_STDERR=true", "-e", "STRUCTURED_RESULTS=true",
+				"example.com:version",
+			},
+		},
+		{
+			name:         "podman nobody",
+			runtime:      Podman{},
+			uidgid:       "nobody",
+			expectedPath: "podman",
+			expectedArgs: []string{
+				"run", "--rm",
+				"-i", "-a", "STDIN", "-a", "STDOUT", "-a", "STDERR",
+				"--network", "host",
+				"--user", "nobody",
+				"--security-opt=no-new-privileges",
+				"--mount", "type=bind,source=" + ro + ",target=/local/,ro=true",
+				"--mount", "type=bind,source=" + rw + ",target=/localrw/",
+				"-e", "FOO=bar", "-e", "LOG_TO_STDERR=true", "-e", "STRUCTURED_RESULTS=true",
+				"example.com:version",
+			},
+		},
+		{
+			name:         "nerdctl",
+			runtime:      Nerdctl{},
+			uidgid:       "nobody",
+			expectedPath: "nerdctl",
+			expectedArgs: []string{
+				"run", "--rm",
+				"-i",
+				"--network", "host",
+				"--user", "nobody",
+				"--security-opt=no-new-privileges",
+				"--mount", "type=bind,source=" + ro + ",target=/local/,readonly",
+				"--mount", "type=bind,source=" + rw + ",target=/localrw/",
+				"-e", "FOO=bar", "-e", "LOG_TO_STDERR=true", "-e", "STRUCTURED_RESULTS=true",
+				"example.com:version",
+			},
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			instance := NewContainer(spec, tt.uidgid)
+			instance.Runtime = tt.runtime
+			instance.Exec.WorkingDir = "/wd"
+			require.NoError(t, instance.setupExec())
+			assert.Equal(t, tt.expectedPath, instance.Exec.Path)
+			assert.Equal(t, tt.expectedArgs, instance.Exec.Args)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -25,6 +26,12 @@ type Filter struct {
 	// should run in
 	WorkingDir string
 
+	// Sandbox, if set, runs the executable in restricted mode.
+	Sandbox *sandbox.Config `yaml:"-"`
+
+	// Stderr receives the stderr of the executable.  Defaults to os.Stderr.
+	Stderr io.Writer `yaml:"-"`
+
 	runtimeutil.FunctionFilter
 }
 
@@ -37,7 +44,10 @@ func (c *Filter) Run(reader io.Reader, writer io.Writer) error {
 	cmd := exec.Command(c.Path, c.Args...) //nolint:gosec
 	cmd.Stdin = reader
 	cmd.Stdout = writer
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = c.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
 	if c.WorkingDir == "" {
 		return errors.Errorf("no working directory set for exec function")
 	}
@@ -50,5 +60,8 @@ func (c *Filter) Run(reader io.Reader, writer io.Writer) error {
 			"root working directory '/' not allowed")
 	}
 	cmd.Dir = c.WorkingDir
+	if c.Sandbox != nil {
+		return c.Sandbox.Run(cmd)
+	}
 	return cmd.Run()
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -61,6 +62,43 @@ metadata:
 				Args:       []string{"s/Deployment/StatefulSet/g"},
 				WorkingDir: wd,
 			},
+		}, {
+			name: "exec_sed_sandboxed",
+			input: []string{
+				`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment-foo`,
+				`apiVersion: v1
+kind: Service
+metadata:
+  name: service-foo`,
+			},
+			expectedOutput: []string{
+				`apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: deployment-foo
+  annotations:
+    internal.config.kubernetes.io/path: 'statefulset_deployment-foo.yaml'
+    config.kubernetes.io/path: 'statefulset_deployment-foo.yaml'
+`,
+				`apiVersion: v1
+kind: Service
+metadata:
+  name: service-foo
+  annotations:
+    internal.config.kubernetes.io/path: 'service_service-foo.yaml'
+    config.kubernetes.io/path: 'service_service-foo.yaml'
+`,
+			},
+			expectedError: "",
+			instance: exec.Filter{
+				Path:       "sed",
+				Args:       []string{"s/Deployment/StatefulSet/g"},
+				WorkingDir: wd,
+				Sandbox:    sandbox.NewConfig(),
+			},
 		},
 	}
 
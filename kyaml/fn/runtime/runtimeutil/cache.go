@@ -0,0 +1,113 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package runtimeutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// FunctionCache stores the output of function invocations keyed by a
+// digest of their input, so that a function whose input has not changed
+// does not need to be executed again.
+type FunctionCache interface {
+	// Get returns the cached output for key, and whether it was found.
+	Get(key string) ([]byte, bool, error)
+
+	// Put stores output for key.
+	Put(key string, output []byte) error
+}
+
+// DiskCache is a FunctionCache that stores each entry as a file in Dir.
+type DiskCache struct {
+	// Dir is the directory holding the cache entries. It is created on
+	// first write if it doesn't exist.
+	Dir string
+}
+
+var _ FunctionCache = DiskCache{}
+
+func (d DiskCache) entryPath(key string) string {
+	return filepath.Join(d.Dir, key+".yaml")
+}
+
+// Get implements FunctionCache.
+func (d DiskCache) Get(key string) ([]byte, bool, error) {
+	b, err := os.ReadFile(d.entryPath(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	return b, true, nil
+}
+
+// Put implements FunctionCache. The entry is written to a temporary file
+// and renamed into place so concurrent readers never see a partial entry.
+func (d DiskCache) Put(key string, output []byte) error {
+	if err := os.MkdirAll(d.Dir, 0700); err != nil {
+		return errors.Wrap(err)
+	}
+	f, err := os.CreateTemp(d.Dir, key+".tmp-*")
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if _, err = f.Write(output); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return errors.Wrap(err)
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return errors.Wrap(err)
+	}
+	if err = os.Rename(f.Name(), d.entryPath(key)); err != nil {
+		_ = os.Remove(f.Name())
+		return errors.Wrap(err)
+	}
+	return nil
+}
+
+// cacheKeyVersion is mixed into every key so that entries written by an
+// incompatible version of this package are never replayed.
+const cacheKeyVersion = "v1"
+
+// CacheKey returns the digest identifying a function invocation.
+// The function is identified by id (e.g. the container image or the
+// executable path).  The functionConfig and items are hashed in a canonical
+// form that ignores map key ordering, comments and formatting.
+func CacheKey(id string, functionConfig *yaml.RNode, items []*yaml.RNode) (string, error) {
+	h := sha256.New()
+	write := func(b []byte) {
+		// length-prefix every component so that boundaries are unambiguous
+		_, _ = h.Write([]byte{
+			byte(len(b) >> 24), byte(len(b) >> 16), byte(len(b) >> 8), byte(len(b))})
+		_, _ = h.Write(b)
+	}
+	write([]byte(cacheKeyVersion))
+	write([]byte(id))
+	if functionConfig == nil {
+		write(nil)
+	} else {
+		b, err := functionConfig.MarshalJSON()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		write(b)
+	}
+	for i := range items {
+		b, err := items[i].MarshalJSON()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
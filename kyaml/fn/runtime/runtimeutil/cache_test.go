@@ -0,0 +1,121 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package runtimeutil
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestDiskCache(t *testing.T) {
+	c := DiskCache{Dir: t.TempDir() + "/cache"}
+
+	_, found, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, c.Put("abc", []byte("output")))
+	b, found, err := c.Get("abc")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "output", string(b))
+}
+
+func TestCacheKey(t *testing.T) {
+	fc := yaml.MustParse(`
+kind: Config
+metadata:
+  name: a
+spec: {replicas: 3}
+`)
+	item := yaml.MustParse(`
+kind: Deployment
+metadata:
+  name: foo
+`)
+	key, err := CacheKey("image:v1", fc, []*yaml.RNode{item})
+	require.NoError(t, err)
+
+	// field order, formatting and comments don't change the key
+	reordered := yaml.MustParse(`
+# comment
+spec:
+  replicas: 3
+metadata: {name: a}
+kind: Config
+`)
+	other, err := CacheKey("image:v1", reordered, []*yaml.RNode{item})
+	require.NoError(t, err)
+	assert.Equal(t, key, other)
+
+	// the function, its config and its items do
+	other, err = CacheKey("image:v2", fc, []*yaml.RNode{item})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	other, err = CacheKey("image:v1", item, []*yaml.RNode{item})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	other, err = CacheKey("image:v1", fc, []*yaml.RNode{item, item})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestFunctionFilter_Cache(t *testing.T) {
+	output := `
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: generated
+`
+	var calls int
+	run := func(err error) func(io.Reader, io.Writer) error {
+		return func(_ io.Reader, w io.Writer) error {
+			calls++
+			_, _ = w.Write([]byte(output))
+			return err
+		}
+	}
+	cache := DiskCache{Dir: t.TempDir()}
+	newFilter := func(err error) *FunctionFilter {
+		return &FunctionFilter{
+			Run:            run(err),
+			FunctionConfig: yaml.MustParse("kind: Config\nmetadata:\n  name: fn\n"),
+			Cache:          cache,
+			CacheID:        "fn",
+		}
+	}
+	input := func() []*yaml.RNode {
+		return []*yaml.RNode{yaml.MustParse("kind: Deployment\nmetadata:\n  name: foo\n")}
+	}
+
+	// a failed run is not cached
+	_, err := newFilter(fmt.Errorf("failed")).Filter(input())
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	first, err := newFilter(nil).Filter(input())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	second, err := newFilter(nil).Filter(input())
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "function should not run on a cache hit")
+	require.Len(t, second, 1)
+	assert.Equal(t, first[0].MustString(), second[0].MustString())
+
+	// different input runs the function again
+	_, err = newFilter(nil).Filter(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
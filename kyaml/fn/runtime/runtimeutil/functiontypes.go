@@ -129,6 +129,11 @@ func NewContainerEnvFromStringSlice(envStr []string) *ContainerEnv {
 type FunctionSpec struct {
 	DeferFailure bool `json:"deferFailure,omitempty" yaml:"deferFailure,omitempty"`
 
+	// DisableCache opts the function out of output caching.  It should be set
+	// for functions that are not deterministic, e.g. ones that read the time or
+	// make network calls.
+	DisableCache bool `json:"disableCache,omitempty" yaml:"disableCache,omitempty"`
+
 	// Container is the spec for running a function as a container
 	Container ContainerSpec `json:"container,omitempty" yaml:"container,omitempty"`
 
@@ -42,6 +42,15 @@ type FunctionFilter struct {
 	// The Run error will be available through GetExit().
 	DeferFailure bool
 
+	// Cache, if set, is consulted before invoking Run.  If an entry exists for the
+	// same function, functionConfig and input items, its output is replayed instead
+	// of running the function.  Successful invocations are stored in the Cache.
+	Cache FunctionCache
+
+	// CacheID identifies the function in the cache keys, e.g. its image or
+	// executable path.  Caching is disabled if it is empty.
+	CacheID string
+
 	// results saves the results emitted from Run
 	Results *yaml.RNode
 
@@ -173,7 +182,9 @@ func (c *FunctionFilter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	r := &kio.ByteReader{Reader: out}
 
 	// don't exit immediately if the function fails -- write out the validation
-	c.exit = c.Run(in, out)
+	if err = c.runCached(input, in, out); err != nil {
+		return nil, err
+	}
 
 	output, err := r.Read()
 	if err != nil {
@@ -203,6 +214,34 @@ func (c *FunctionFilter) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	return append(output, saved...), nil
 }
 
+// runCached invokes Run and saves its error to c.exit, replaying the output
+// from c.Cache instead if the same input has been seen before.  The returned
+// error is an error accessing the cache.
+func (c *FunctionFilter) runCached(input []*yaml.RNode, in io.Reader, out *bytes.Buffer) error {
+	if c.Cache == nil || c.CacheID == "" {
+		c.exit = c.Run(in, out)
+		return nil
+	}
+	key, err := CacheKey(c.CacheID, c.FunctionConfig, input)
+	if err != nil {
+		return err
+	}
+	cached, found, err := c.Cache.Get(key)
+	if err != nil {
+		return err
+	}
+	if found {
+		c.exit = nil
+		out.Write(cached)
+		return nil
+	}
+	// never cache failures, they may be transient
+	if c.exit = c.Run(in, out); c.exit != nil {
+		return nil
+	}
+	return c.Cache.Put(key, out.Bytes())
+}
+
 func (c *FunctionFilter) setIds(nodes []*yaml.RNode) error {
 	// set the id on each node to map inputs to outputs
 	var id int
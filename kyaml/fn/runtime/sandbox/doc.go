@@ -0,0 +1,13 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sandbox runs exec functions and exec plugins in a restricted
+// mode: a cleared environment, an optional scratch working directory,
+// an optional read-only file system, resource limits and a wall-clock
+// timeout.
+//
+// Resource limits and the read-only file system are only supported on
+// Linux.  The resource limits are set by a shell with ulimit before it
+// executes the process, so they apply from its start.  The file system
+// is made read-only with Landlock, by the thread starting the process.
+package sandbox
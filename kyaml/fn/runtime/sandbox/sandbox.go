@@ -0,0 +1,223 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// Config restricts a process started for an exec function or exec plugin.
+// Zero values mean "no restriction", except for the environment which is
+// always cleared down to EnvAllowList.
+type Config struct {
+	// EnvAllowList names the variables of the caller's environment that are
+	// passed to the process.  All others are removed.
+	EnvAllowList []string
+
+	// ScratchDir runs the process in a new empty temporary directory, removed
+	// after the process exits, instead of the caller's working directory.
+	ScratchDir bool
+
+	// ReadOnlyWorkDir makes the file system, and so the working directory,
+	// read-only for the process, except for its scratch directory and for a
+	// new temporary directory set as its TMPDIR and removed after it exits,
+	// and for /dev.  It's enforced with Landlock, on Linux 5.13 and later.
+	ReadOnlyWorkDir bool
+
+	// CPUTime limits the CPU time the process may consume (RLIMIT_CPU).
+	CPUTime time.Duration
+
+	// MemoryBytes limits the address space of the process (RLIMIT_AS).
+	// Exceeding it makes allocations fail, which the process reports itself.
+	MemoryBytes uint64
+
+	// MaxOutputBytes limits how much the process may write to stdout.
+	MaxOutputBytes int64
+
+	// Timeout limits the wall-clock time the process may run.
+	Timeout time.Duration
+}
+
+// waitDelay bounds how long Run waits for the output of a process to be
+// closed after the process exited.
+const waitDelay = 5 * time.Second
+
+// DefaultEnvAllowList is the allow-list used by NewConfig.
+var DefaultEnvAllowList = []string{"PATH", "HOME", "TMPDIR", "LANG", "LC_ALL"}
+
+// NewConfig returns a Config with the default allow-list and no limits.
+func NewConfig() *Config {
+	return &Config{EnvAllowList: append([]string{}, DefaultEnvAllowList...)}
+}
+
+// Violation identifies the restriction a process violated.
+type Violation string
+
+const (
+	ViolationTimeout     Violation = "Timeout"
+	ViolationCPUTime     Violation = "CPUTime"
+	ViolationOutputLimit Violation = "OutputLimit"
+)
+
+// ViolationError is returned by Run when a process is terminated for
+// exceeding one of the limits of the Config.
+type ViolationError struct {
+	// Path is the executable that was run.
+	Path string
+
+	// Violation is the restriction that was violated.
+	Violation Violation
+
+	// Limit is the configured value of the violated restriction.
+	Limit string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("%s terminated by sandbox: %s limit of %s exceeded",
+		e.Path, e.Violation, e.Limit)
+}
+
+// Environ returns the entries of the caller's environment that are allowed
+// by c.EnvAllowList, followed by extra.
+func (c *Config) Environ(extra ...string) []string {
+	allowed := make(map[string]bool, len(c.EnvAllowList))
+	for _, k := range c.EnvAllowList {
+		allowed[k] = true
+	}
+	var env []string
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if allowed[k] {
+			env = append(env, kv)
+		}
+	}
+	return append(env, extra...)
+}
+
+// Run runs cmd with the restrictions of c, and waits for it to exit.
+// If cmd.Env is nil it is set to c.Environ(); callers that need to pass
+// additional variables should set it to c.Environ(vars...) themselves.
+// A *ViolationError is returned if the process exceeded a limit.
+func (c *Config) Run(cmd *exec.Cmd) error {
+	if err := checkSupported(c); err != nil {
+		return err
+	}
+	if cmd.Env == nil {
+		cmd.Env = c.Environ()
+	}
+	path := cmd.Path
+	var writable []string
+	if c.ScratchDir {
+		dir, err := os.MkdirTemp("", "kustomize-sandbox-")
+		if err != nil {
+			return errors.WrapPrefixf(err, "creating sandbox scratch dir")
+		}
+		defer os.RemoveAll(dir)
+		cmd.Dir = dir
+		writable = append(writable, dir)
+	}
+	if c.ReadOnlyWorkDir {
+		dir, err := os.MkdirTemp("", "kustomize-sandbox-tmp-")
+		if err != nil {
+			return errors.WrapPrefixf(err, "creating sandbox temporary dir")
+		}
+		defer os.RemoveAll(dir)
+		cmd.Env = append(cmd.Env, "TMPDIR="+dir)
+		writable = append(writable, dir, "/dev")
+	}
+
+	var lw *limitWriter
+	if c.MaxOutputBytes > 0 {
+		w := cmd.Stdout
+		if w == nil {
+			w = io.Discard
+		}
+		lw = &limitWriter{w: w, remaining: c.MaxOutputBytes, kill: func() { kill(cmd) }}
+		cmd.Stdout = lw
+	}
+
+	if cmd.WaitDelay == 0 {
+		// don't wait forever on output held open by orphaned children
+		// once the process itself has been killed
+		cmd.WaitDelay = waitDelay
+	}
+	setRlimits(c, cmd)
+	if err := start(c, cmd, writable); err != nil {
+		return err
+	}
+
+	var timedOut bool
+	var mu sync.Mutex
+	if c.Timeout > 0 {
+		t := time.AfterFunc(c.Timeout, func() {
+			mu.Lock()
+			timedOut = true
+			mu.Unlock()
+			kill(cmd)
+		})
+		defer t.Stop()
+	}
+
+	err := cmd.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	switch {
+	case timedOut:
+		return violation(path, ViolationTimeout, c.Timeout.String())
+	case lw != nil && lw.exceeded():
+		return violation(path, ViolationOutputLimit, fmt.Sprintf("%d bytes", c.MaxOutputBytes))
+	case err != nil && exceededCPUTime(c, cmd.ProcessState):
+		return violation(path, ViolationCPUTime, c.CPUTime.String())
+	}
+	return err
+}
+
+func violation(path string, v Violation, limit string) error {
+	return &ViolationError{Path: path, Violation: v, Limit: limit}
+}
+
+func kill(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// limitWriter forwards writes to w until remaining is exhausted, then
+// kills the process and fails all further writes.
+type limitWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	remaining int64
+	over      bool
+	kill      func()
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if int64(len(p)) > l.remaining {
+		if !l.over {
+			l.over = true
+			l.kill()
+		}
+		return 0, fmt.Errorf("output limit exceeded")
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
+
+func (l *limitWriter) exceeded() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.over
+}
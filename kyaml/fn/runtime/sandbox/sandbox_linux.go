@@ -0,0 +1,153 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+// +build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+// shell sets the resource limits before executing the process.
+const shell = "/bin/sh"
+
+// kib is the unit of the memory limit of ulimit.
+const kib = 1024
+
+func checkSupported(*Config) error {
+	return nil
+}
+
+// setRlimits makes cmd run through a shell setting the resource limits
+// of c with ulimit, then executing the process, so that the limits apply
+// before the process runs anything.
+func setRlimits(c *Config, cmd *exec.Cmd) {
+	var script []string
+	if c.CPUTime > 0 {
+		secs := uint64(c.CPUTime.Seconds())
+		if secs == 0 {
+			secs = 1
+		}
+		// the soft limit delivers SIGXCPU, the hard limit SIGKILL
+		script = append(script,
+			fmt.Sprintf("ulimit -S -t %d", secs), fmt.Sprintf("ulimit -H -t %d", secs+1))
+	}
+	if c.MemoryBytes > 0 {
+		kbytes := c.MemoryBytes / kib
+		if kbytes == 0 {
+			kbytes = 1
+		}
+		script = append(script, fmt.Sprintf("ulimit -v %d", kbytes))
+	}
+	if len(script) == 0 {
+		return
+	}
+	script = append(script, `exec "$0" "$@"`)
+	cmd.Args = append(
+		[]string{shell, "-c", strings.Join(script, " && "), cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = shell
+}
+
+// start starts cmd, from a thread restricted by Landlock to writing
+// the writable paths if c.ReadOnlyWorkDir is set.  The process inherits
+// the restriction; the thread is never unlocked, so that it exits with
+// its goroutine instead of running other goroutines.
+func start(c *Config, cmd *exec.Cmd, writable []string) error {
+	if !c.ReadOnlyWorkDir {
+		return cmd.Start()
+	}
+	errs := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := restrictWrites(writable); err != nil {
+			errs <- err
+			return
+		}
+		errs <- cmd.Start()
+	}()
+	return <-errs
+}
+
+// landlockWriteAccess are the Landlock file system accesses
+// modifying files, of the first version of Landlock.
+const landlockWriteAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+	unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// restrictWrites restricts the calling thread to modifying files
+// beneath the writable paths.
+func restrictWrites(writable []string) error {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return errors.WrapPrefixf(errno,
+			"sandbox read-only working directory requires Landlock")
+	}
+	access := uint64(landlockWriteAccess)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	attr := unix.LandlockRulesetAttr{Access_fs: access}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return errors.WrapPrefixf(errno, "creating sandbox landlock ruleset")
+	}
+	defer unix.Close(int(fd))
+	for _, path := range writable {
+		pfd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return errors.WrapPrefixf(err, "opening sandbox writable path %s", path)
+		}
+		rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(pfd)}
+		_, _, errno = unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, fd,
+			unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+		unix.Close(pfd)
+		if errno != 0 {
+			return errors.WrapPrefixf(errno, "allowing sandbox writes to %s", path)
+		}
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return errors.WrapPrefixf(err, "setting sandbox no_new_privs")
+	}
+	if _, _, errno = unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return errors.WrapPrefixf(errno, "restricting sandbox writes")
+	}
+	return nil
+}
+
+// exceededCPUTime returns true if the process was killed by the kernel
+// for exceeding its cpu limit.
+func exceededCPUTime(c *Config, ps *os.ProcessState) bool {
+	if c.CPUTime <= 0 || ps == nil {
+		return false
+	}
+	ws, ok := ps.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return false
+	}
+	sig := ws.Signal()
+	return sig == syscall.SIGXCPU ||
+		(sig == syscall.SIGKILL && ps.UserTime()+ps.SystemTime() >= c.CPUTime)
+}
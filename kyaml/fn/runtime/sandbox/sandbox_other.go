@@ -0,0 +1,37 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+// +build !linux
+
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+)
+
+func checkSupported(c *Config) error {
+	if c.CPUTime > 0 || c.MemoryBytes > 0 {
+		return errors.Errorf(
+			"sandbox cpu and memory limits are not supported on %s", runtime.GOOS)
+	}
+	if c.ReadOnlyWorkDir {
+		return errors.Errorf(
+			"sandbox read-only working directory is not supported on %s", runtime.GOOS)
+	}
+	return nil
+}
+
+func setRlimits(*Config, *exec.Cmd) {}
+
+func start(_ *Config, cmd *exec.Cmd, _ []string) error {
+	return cmd.Start()
+}
+
+func exceededCPUTime(*Config, *os.ProcessState) bool {
+	return false
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func skipIfNoShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires sh")
+	}
+}
+
+func TestRun_Env(t *testing.T) {
+	skipIfNoShell(t)
+	t.Setenv("SANDBOX_ALLOWED", "yes")
+	t.Setenv("SANDBOX_SECRET", "no")
+	c := &Config{EnvAllowList: []string{"PATH", "SANDBOX_ALLOWED"}}
+
+	var out bytes.Buffer
+	cmd := exec.Command("sh", "-c", "env")
+	cmd.Env = c.Environ("SANDBOX_EXTRA=1")
+	cmd.Stdout = &out
+	require.NoError(t, c.Run(cmd))
+	assert.Contains(t, out.String(), "SANDBOX_ALLOWED=yes")
+	assert.Contains(t, out.String(), "SANDBOX_EXTRA=1")
+	assert.NotContains(t, out.String(), "SANDBOX_SECRET")
+}
+
+func TestRun_ScratchDir(t *testing.T) {
+	skipIfNoShell(t)
+	c := NewConfig()
+	c.ScratchDir = true
+
+	var out bytes.Buffer
+	cmd := exec.Command("sh", "-c", "pwd; ls -A")
+	cmd.Dir = t.TempDir()
+	cmd.Stdout = &out
+	require.NoError(t, c.Run(cmd))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 1, "scratch dir should be empty")
+	assert.Contains(t, lines[0], "kustomize-sandbox-")
+}
+
+func TestRun_Timeout(t *testing.T) {
+	skipIfNoShell(t)
+	c := NewConfig()
+	c.Timeout = 100 * time.Millisecond
+
+	err := c.Run(exec.Command("sh", "-c", "sleep 10"))
+	var v *ViolationError
+	require.True(t, errors.As(err, &v), "unexpected error %v", err)
+	assert.Equal(t, ViolationTimeout, v.Violation)
+	assert.Equal(t, "100ms", v.Limit)
+}
+
+func TestRun_OutputLimit(t *testing.T) {
+	skipIfNoShell(t)
+	c := NewConfig()
+	c.MaxOutputBytes = 10
+
+	var out bytes.Buffer
+	cmd := exec.Command("sh", "-c", "while :; do echo aaaaaaaaaaaaaaaaaaaa; done")
+	cmd.Stdout = &out
+	err := c.Run(cmd)
+	var v *ViolationError
+	require.True(t, errors.As(err, &v), "unexpected error %v", err)
+	assert.Equal(t, ViolationOutputLimit, v.Violation)
+	assert.LessOrEqual(t, out.Len(), 10)
+
+	// output within the limit is passed through
+	out.Reset()
+	cmd = exec.Command("sh", "-c", "echo hello")
+	cmd.Stdout = &out
+	require.NoError(t, c.Run(cmd))
+	assert.Equal(t, "hello\n", out.String())
+}
+
+func TestRun_CPUTime(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cpu limits are only supported on linux")
+	}
+	c := NewConfig()
+	c.CPUTime = time.Second
+	c.Timeout = time.Minute
+
+	err := c.Run(exec.Command("sh", "-c", "while :; do :; done"))
+	var v *ViolationError
+	require.True(t, errors.As(err, &v), "unexpected error %v", err)
+	assert.Equal(t, ViolationCPUTime, v.Violation)
+}
+
+func TestRun_ExitError(t *testing.T) {
+	skipIfNoShell(t)
+	c := NewConfig()
+	c.Timeout = time.Minute
+
+	err := c.Run(exec.Command("sh", "-c", "exit 3"))
+	var v *ViolationError
+	assert.False(t, errors.As(err, &v))
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+func TestRun_MemoryLimitBeforeStart(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory limits are only supported on linux")
+	}
+	c := NewConfig()
+	c.MemoryBytes = 64 << 20
+
+	// the limit applies from the start of the process
+	var out bytes.Buffer
+	cmd := exec.Command("sh", "-c", "ulimit -v")
+	cmd.Stdout = &out
+	require.NoError(t, c.Run(cmd))
+	assert.Equal(t, "65536\n", out.String())
+}
+
+func TestRun_ReadOnlyWorkDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("read-only working directories are only supported on linux")
+	}
+	c := NewConfig()
+	c.ReadOnlyWorkDir = true
+	dir := t.TempDir()
+
+	cmd := exec.Command("sh", "-c", "touch written")
+	cmd.Dir = dir
+	err := c.Run(cmd)
+	if err != nil && strings.Contains(err.Error(), "requires Landlock") {
+		t.Skip(err)
+	}
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "written"))
+
+	// the process may write to its TMPDIR
+	cmd = exec.Command("sh", "-c", `touch "$TMPDIR/written" && echo ok >/dev/null`)
+	cmd.Dir = dir
+	require.NoError(t, c.Run(cmd))
+
+	// the caller isn't restricted
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "written"), nil, 0o600))
+}
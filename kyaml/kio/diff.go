@@ -0,0 +1,112 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package kio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// diffContext is the number of unchanged lines around the
+// changes of a ResourceChange diff.
+const diffContext = 3
+
+// ResourceChange is a resource that differs between two lists of
+// resources.
+type ResourceChange struct {
+	// ID identifies the resource by its group, version, kind,
+	// namespace and name.
+	ID string
+
+	// Before is the resource in the first list, empty if it's
+	// only in the second one.
+	Before string
+
+	// After is the resource in the second list, empty if it's
+	// only in the first one.
+	After string
+}
+
+// UnifiedDiff returns a unified diff of the resource, labelling
+// Before and After as from and to.
+func (c ResourceChange) UnifiedDiff(from, to string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(c.Before),
+		B:        splitLines(c.After),
+		FromFile: from,
+		ToFile:   to,
+		Context:  diffContext,
+	})
+}
+
+// splitLines splits s into lines ending with a newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	last := len(lines) - 1
+	if lines[last] == "" {
+		return lines[:last]
+	}
+	lines[last] += "\n"
+	return lines
+}
+
+// DiffResources compares before and after resource by resource,
+// matching resources by their group, version, kind, namespace and
+// name, and returns the resources that differ: those of before, in
+// order, followed by those only in after, in order.  The order of
+// the resources isn't compared.  Resources with the same id are
+// matched by their position among them.
+func DiffResources(before, after []*yaml.RNode) ([]ResourceChange, error) {
+	beforeByID, beforeIDs, err := indexResources(before)
+	if err != nil {
+		return nil, err
+	}
+	afterByID, afterIDs, err := indexResources(after)
+	if err != nil {
+		return nil, err
+	}
+	var changes []ResourceChange
+	for _, id := range beforeIDs {
+		if b, a := beforeByID[id], afterByID[id]; b != a {
+			changes = append(changes, ResourceChange{ID: id, Before: b, After: a})
+		}
+	}
+	for _, id := range afterIDs {
+		if _, found := beforeByID[id]; !found {
+			changes = append(changes, ResourceChange{ID: id, After: afterByID[id]})
+		}
+	}
+	return changes, nil
+}
+
+// indexResources returns the serialized resources by id, and the ids
+// in order.  Resources with the same id are disambiguated by their
+// position.
+func indexResources(nodes []*yaml.RNode) (map[string]string, []string, error) {
+	byID := map[string]string{}
+	var ids []string
+	for _, node := range nodes {
+		s, err := node.String()
+		if err != nil {
+			return nil, nil, err
+		}
+		id := resid.FromRNode(node).String()
+		for i := 2; ; i++ {
+			if _, found := byID[id]; !found {
+				break
+			}
+			id = fmt.Sprintf("%s#%d", resid.FromRNode(node), i)
+		}
+		byID[id] = s
+		ids = append(ids, id)
+	}
+	return byID, ids, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package kio_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	. "sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestDiffResources(t *testing.T) {
+	parse := func(s string) []*yaml.RNode {
+		t.Helper()
+		nodes, err := (&ByteReader{
+			Reader:                strings.NewReader(s),
+			OmitReaderAnnotations: true,
+		}).Read()
+		require.NoError(t, err)
+		return nodes
+	}
+	before := parse(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: ClusterIP
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+`)
+
+	changes, err := DiffResources(before, parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: ClusterIP
+`))
+	require.NoError(t, err)
+	assert.Empty(t, changes, "order should not matter")
+
+	changes, err = DiffResources(before, parse(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: NodePort
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: c
+`))
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "Service.v1.[noGrp]/a.[noNs]", changes[0].ID)
+	diff, err := changes[0].UnifiedDiff("before", "after")
+	require.NoError(t, err)
+	assert.Equal(t, `--- before
++++ after
+@@ -3,4 +3,4 @@
+ metadata:
+   name: a
+ spec:
+-  type: ClusterIP
++  type: NodePort
+`, diff)
+	assert.Equal(t, ResourceChange{
+		ID:     "ConfigMap.v1.[noGrp]/b.[noNs]",
+		Before: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+	}, changes[1])
+	assert.Equal(t, ResourceChange{
+		ID:    "Secret.v1.[noGrp]/c.[noNs]",
+		After: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: c\n",
+	}, changes[2])
+}
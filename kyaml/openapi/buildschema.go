@@ -0,0 +1,237 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/openapi/kubernetesapi"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// buildSchemas coordinates the BuildSchemas holding the global schema.
+var buildSchemas = struct { //nolint:gochecknoglobals
+	mu   sync.Mutex
+	cond *sync.Cond
+	// key is the key of the BuildSchemas holding the global schema
+	key string
+	// holders is the number of BuildSchemas holding the global schema
+	holders int
+	// onDefault is whether the global schema was set on top of the
+	// default builtin schema, see BuildSchema.onDefault
+	onDefault bool
+}{}
+
+func init() { //nolint:gochecknoinits
+	buildSchemas.cond = sync.NewCond(&buildSchemas.mu)
+}
+
+// BuildSchema is the schema of a build: the builtin or custom schema set by
+// the openapi field of its kustomization, and the definitions of the custom
+// resources declared by its CRDs.  A build holds its BuildSchema while it
+// runs, from AcquireBuildSchema to Release, and the global schema is its
+// schema meanwhile.  The builds of the same schema can hold it at once; a
+// build of a different schema waits until they're done, so that concurrent
+// builds, e.g. of the targets of a multi-target build, don't change each
+// other's schema.  The definitions of the CRDs are removed from the global
+// schema when the last build holding them releases it.
+//
+// A nil BuildSchema changes the global schema directly, as SetSchema and
+// AddCRDDefinitions do.
+type BuildSchema struct {
+	openAPIField map[string]string
+	schema       []byte
+	crds         []*yaml.RNode
+	// definitions are the names of the definitions of the crds
+	definitions map[string]bool
+	// onDefault is whether the schema was set by a kustomization referred
+	// to by the one of the build, after the build started with the default
+	// builtin schema.  The schema is then added on top of the default one,
+	// as SetSchema does.
+	onDefault bool
+	held      bool
+}
+
+// AcquireBuildSchema waits until the global schema can be set to the schema
+// of the openapi field and the custom schema, as SetSchema does, and
+// returns the BuildSchema holding it.
+func AcquireBuildSchema(openAPIField map[string]string, schema []byte) (*BuildSchema, error) {
+	bs := &BuildSchema{openAPIField: openAPIField, schema: schema}
+	if err := bs.acquire(); err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// Release releases the global schema.  It's a no-op if bs doesn't hold it.
+func (bs *BuildSchema) Release() {
+	if bs == nil || !bs.held {
+		return
+	}
+	bs.held = false
+	buildSchemas.mu.Lock()
+	defer buildSchemas.mu.Unlock()
+	buildSchemas.holders--
+	if buildSchemas.holders > 0 {
+		return
+	}
+	schemaLock.Lock()
+	removeCRDDefinitions()
+	schemaLock.Unlock()
+	buildSchemas.key = ""
+	buildSchemas.cond.Broadcast()
+}
+
+// SetSchemaIfUnset sets the schema of the build to the schema of the
+// openapi field and the custom schema, unless the build already has one,
+// as SetSchema does without reset.
+func (bs *BuildSchema) SetSchemaIfUnset(openAPIField map[string]string, schema []byte) error {
+	if bs == nil {
+		return SetSchema(openAPIField, schema, false)
+	}
+	if bs.openAPIField["version"] != "" || bs.schema != nil {
+		return nil
+	}
+	if openAPIField["version"] == "" && schema == nil {
+		return nil
+	}
+	bs.Release()
+	bs.openAPIField, bs.schema, bs.onDefault = openAPIField, schema, true
+	return bs.acquire()
+}
+
+// AddCRDDefinitions adds the definitions of the custom resources declared by
+// the CustomResourceDefinition crd to the schema of the build, see
+// DefinitionsFromCRD.  A CRD declaring only definitions the build already
+// has, e.g. the same CRD seen again by a kustomization referring to the
+// one declaring it, leaves the schema unchanged.
+func (bs *BuildSchema) AddCRDDefinitions(crd *yaml.RNode) error {
+	if bs == nil {
+		return AddCRDDefinitions(crd)
+	}
+	definitions, err := DefinitionsFromCRD(crd)
+	if err != nil {
+		return err
+	}
+	added := false
+	for name := range definitions {
+		if !bs.definitions[name] {
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	if bs.definitions == nil {
+		bs.definitions = map[string]bool{}
+	}
+	for name := range definitions {
+		bs.definitions[name] = true
+	}
+	bs.Release()
+	bs.crds = append(bs.crds, crd.Copy())
+	return bs.acquire()
+}
+
+// acquire waits until no other schema holds the global schema, sets it
+// to bs unless bs already holds it, and holds it.
+func (bs *BuildSchema) acquire() error {
+	if err := checkBuildSchema(bs.openAPIField, bs.schema); err != nil {
+		return err
+	}
+	key, err := bs.key()
+	if err != nil {
+		return err
+	}
+	buildSchemas.mu.Lock()
+	defer buildSchemas.mu.Unlock()
+	for buildSchemas.holders > 0 && buildSchemas.key != key {
+		buildSchemas.cond.Wait()
+	}
+	if buildSchemas.holders == 0 {
+		if err = bs.apply(); err != nil {
+			return err
+		}
+		buildSchemas.key = key
+	}
+	buildSchemas.holders++
+	bs.held = true
+	return nil
+}
+
+// apply sets the global schema to bs.
+func (bs *BuildSchema) apply() error {
+	setBuildSchema(bs.openAPIField, bs.schema, bs.onDefault)
+	schemaLock.Lock()
+	removeCRDDefinitions()
+	schemaLock.Unlock()
+	for _, crd := range bs.crds {
+		if err := AddCRDDefinitions(crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key returns a key identifying the schema of bs.
+func (bs *BuildSchema) key() (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "version=%s\n", bs.openAPIField["version"])
+	fmt.Fprintf(h, "onDefault=%t\n", bs.onDefault)
+	fmt.Fprintf(h, "schema=%x\n", sha256.Sum256(bs.schema))
+	for _, crd := range bs.crds {
+		s, err := crd.String()
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		fmt.Fprintf(h, "crd=%x\n", sha256.Sum256([]byte(s)))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// checkBuildSchema returns an error if the openapi field and the custom
+// schema don't make a schema, as SetSchema does.
+func checkBuildSchema(openAPIField map[string]string, schema []byte) error {
+	version, versionProvided := openAPIField["version"]
+	if schema != nil && versionProvided {
+		return fmt.Errorf("builtin version and custom schema provided, cannot use both")
+	}
+	if schema == nil && version != "" {
+		if _, ok := kubernetesapi.OpenAPIMustAsset[version]; !ok {
+			return fmt.Errorf("the specified OpenAPI version is not built in")
+		}
+	}
+	return nil
+}
+
+// setBuildSchema sets the global schema to the schema of the openapi field
+// and the custom schema as SetSchema does with reset, on top of the default
+// builtin schema if onDefault, and drops the definitions of the previous
+// schema unless it's the same.  The schema must be checked by
+// checkBuildSchema, and buildSchemas.mu must be held.
+func setBuildSchema(openAPIField map[string]string, schema []byte, onDefault bool) {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	version := openAPIField["version"]
+	if schema != nil {
+		version = "custom"
+	}
+	if (customSchema == nil) == (schema == nil) && bytes.Equal(customSchema, schema) &&
+		kubernetesOpenAPIVersion == version && buildSchemas.onDefault == onDefault {
+		return
+	}
+	globalSchema = openapiData{noUseBuiltInSchema: globalSchema.noUseBuiltInSchema}
+	if onDefault {
+		parseBuiltinSchema(kubernetesOpenAPIDefaultVersion)
+		globalSchema.defaultBuiltInSchemaParseStatus = schemaParsed
+	}
+	customSchema = schema
+	kubernetesOpenAPIVersion = version
+	buildSchemas.onDefault = onDefault
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+var backendV1 = yaml.TypeMeta{APIVersion: "example.com/v1", Kind: "Backend"}
+
+var frontendV1 = yaml.TypeMeta{APIVersion: "example.com/v1", Kind: "Frontend"}
+
+func TestBuildSchema_CRDDefinitionsAreDroppedOnRelease(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	bs, err := AcquireBuildSchema(nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, bs.AddCRDDefinitions(yaml.MustParse(crdWithListTypes)))
+	assert.NotNil(t, SchemaForResourceType(backendV1))
+	bs.Release()
+	assert.Nil(t, SchemaForResourceType(backendV1))
+
+	// releasing twice is a no-op
+	bs.Release()
+	other, err := AcquireBuildSchema(nil, nil)
+	require.NoError(t, err)
+	defer other.Release()
+	assert.Nil(t, SchemaForResourceType(backendV1))
+}
+
+func TestBuildSchema_SameSchemaIsShared(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	version := map[string]string{"version": "v1.21.2"}
+	a, err := AcquireBuildSchema(version, nil)
+	require.NoError(t, err)
+	b, err := AcquireBuildSchema(version, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.21.2", GetSchemaVersion())
+	a.Release()
+	b.Release()
+}
+
+func TestBuildSchema_DifferentSchemaWaits(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	a, err := AcquireBuildSchema(nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddCRDDefinitions(yaml.MustParse(crdWithListTypes)))
+
+	acquired := make(chan *BuildSchema)
+	go func() {
+		b, err := AcquireBuildSchema(map[string]string{"version": "v1.21.2"}, nil)
+		assert.NoError(t, err)
+		acquired <- b
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("a different schema was acquired while held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NotNil(t, SchemaForResourceType(backendV1))
+
+	a.Release()
+	b := <-acquired
+	defer b.Release()
+	assert.Equal(t, "v1.21.2", GetSchemaVersion())
+	assert.Nil(t, SchemaForResourceType(backendV1))
+}
+
+func TestBuildSchema_SetSchemaIfUnset(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	bs, err := AcquireBuildSchema(nil, nil)
+	require.NoError(t, err)
+	defer bs.Release()
+	require.NoError(t, bs.SetSchemaIfUnset(map[string]string{"version": "v1.21.2"}, nil))
+	assert.Equal(t, "v1.21.2", GetSchemaVersion())
+
+	// the schema is set, so it's kept
+	require.NoError(t, bs.SetSchemaIfUnset(nil, []byte(`{}`)))
+	assert.Equal(t, "v1.21.2", GetSchemaVersion())
+
+	_, err = AcquireBuildSchema(map[string]string{"version": "v0.0.0"}, nil)
+	require.Error(t, err)
+}
+
+func TestBuildSchema_ConcurrentBuildsDontLeak(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	frontendCRD := strings.NewReplacer(
+		"backends", "frontends", "Backend", "Frontend").Replace(crdWithListTypes)
+	builds := []struct {
+		schema  []byte
+		crd     string
+		own     yaml.TypeMeta
+		other   yaml.TypeMeta
+		version string
+	}{
+		{crd: crdWithListTypes, own: backendV1, other: frontendV1,
+			version: kubernetesOpenAPIDefaultVersion},
+		{schema: []byte(`{}`), crd: frontendCRD, own: frontendV1, other: backendV1,
+			version: "using custom schema from file provided"},
+	}
+
+	var wg sync.WaitGroup
+	for _, b := range builds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				bs, err := AcquireBuildSchema(nil, nil)
+				if !assert.NoError(t, err) {
+					return
+				}
+				// the schema is set by a kustomization of the build, and
+				// the CRD is found while building, both mid-build
+				if !assert.NoError(t, bs.SetSchemaIfUnset(nil, b.schema)) ||
+					!assert.NoError(t, bs.AddCRDDefinitions(yaml.MustParse(b.crd))) {
+					bs.Release()
+					return
+				}
+				assert.NotNil(t, SchemaForResourceType(b.own))
+				assert.Nil(t, SchemaForResourceType(b.other))
+				assert.Equal(t, b.version, GetSchemaVersion())
+				bs.Release()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Nil(t, SchemaForResourceType(backendV1))
+	assert.Nil(t, SchemaForResourceType(frontendV1))
+}
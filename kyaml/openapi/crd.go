@@ -0,0 +1,218 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// kubernetesListTypeExtensionKey is the key to lookup the list type extension
+	// of the schemas of custom resources -- the extension is one of atomic, set or map
+	kubernetesListTypeExtensionKey = "x-kubernetes-list-type"
+)
+
+// AddCRDDefinitions adds the definitions of the custom resources declared by
+// the CustomResourceDefinition crd to the global schema, see DefinitionsFromCRD.
+// Resource types already having a schema, e.g. from a custom schema file,
+// are left unchanged.  The definitions are kept until removed by a reset of
+// the schema, or by the release of the BuildSchema they were added for.
+func AddCRDDefinitions(crd *yaml.RNode) error {
+	definitions, err := DefinitionsFromCRD(crd)
+	if err != nil {
+		return err
+	}
+	initSchema()
+	for name, d := range definitions {
+		if _, found := globalSchema.schema.Definitions[name]; found {
+			delete(definitions, name)
+			continue
+		}
+		for _, typeMeta := range definitionTypes(d) {
+			if _, found := globalSchema.schemaByResourceType[typeMeta]; found {
+				delete(definitions, name)
+			}
+		}
+	}
+	AddDefinitions(definitions)
+	for name, d := range definitions {
+		globalSchema.crdDefinitions = append(globalSchema.crdDefinitions, name)
+		globalSchema.crdResourceTypes = append(globalSchema.crdResourceTypes, definitionTypes(d)...)
+	}
+	return nil
+}
+
+// removeCRDDefinitions removes the definitions added by AddCRDDefinitions
+// from the global schema.
+func removeCRDDefinitions() {
+	for _, name := range globalSchema.crdDefinitions {
+		delete(globalSchema.schema.Definitions, name)
+	}
+	for _, typeMeta := range globalSchema.crdResourceTypes {
+		delete(globalSchema.schemaByResourceType, typeMeta)
+	}
+	globalSchema.crdDefinitions = nil
+	globalSchema.crdResourceTypes = nil
+}
+
+// definitionTypes returns the resource types of the definition d.
+func definitionTypes(d spec.Schema) []yaml.TypeMeta {
+	var result []yaml.TypeMeta
+	exts, _ := d.Extensions[kubernetesGVKExtensionKey].([]interface{})
+	for i := range exts {
+		if typeMeta, ok := toTypeMeta(exts[i]); ok {
+			result = append(result, typeMeta)
+		}
+	}
+	return result
+}
+
+// DefinitionsFromCRD returns the definitions of the versions of the custom
+// resource declared by the CustomResourceDefinition crd, for merging
+// strategic merge patches into them.
+//
+// The lists of the openAPIV3Schema of a version are merged as declared by
+// their x-kubernetes-list-type extension: lists of type map are merged by
+// their x-kubernetes-list-map-keys, lists of type set are merged by value,
+// and atomic lists are replaced.  The x-kubernetes-patch-strategy and
+// x-kubernetes-patch-merge-key extensions of built-in types are honored too.
+// Only the fields leading to such lists are kept in the definitions, so that
+// the other fields are merged as if the resource had no schema.
+func DefinitionsFromCRD(crd *yaml.RNode) (spec.Definitions, error) {
+	// a CRD missing its group or kind declares no resource type
+	group, err := crd.GetString("spec.group")
+	if err != nil {
+		return nil, nil
+	}
+	kind, err := crd.GetString("spec.names.kind")
+	if err != nil {
+		return nil, nil
+	}
+	schemas, err := crdVersionSchemas(crd)
+	if err != nil {
+		return nil, err
+	}
+	definitions := spec.Definitions{}
+	for version, node := range schemas {
+		b, err := node.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		var s spec.Schema
+		if err = json.Unmarshal(b, &s); err != nil {
+			return nil, errors.WrapPrefixf(err, "parsing the schema of version %s", version)
+		}
+		d, ok := patchSchema(s)
+		if !ok {
+			continue
+		}
+		d.AddExtension(kubernetesGVKExtensionKey, []interface{}{
+			map[string]interface{}{
+				groupKey:   group,
+				versionKey: version,
+				kindKey:    kind,
+			},
+		})
+		definitions[fmt.Sprintf("%s.%s.%s", group, version, kind)] = d
+	}
+	return definitions, nil
+}
+
+// crdVersionSchemas returns the openAPIV3Schema of each version of crd.
+// In apiextensions/v1beta1, the schema of spec.validation applies to the
+// versions having none.
+func crdVersionSchemas(crd *yaml.RNode) (map[string]*yaml.RNode, error) {
+	common, err := crd.Pipe(yaml.Lookup("spec", "validation", "openAPIV3Schema"))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	schemas := make(map[string]*yaml.RNode)
+	if version, err := crd.GetString("spec.version"); err == nil && common != nil {
+		schemas[version] = common
+	}
+	versions, err := crd.Pipe(yaml.Lookup("spec", "versions"))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if versions == nil {
+		return schemas, nil
+	}
+	elements, err := versions.Elements()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	for _, v := range elements {
+		name, err := v.GetString("name")
+		if err != nil {
+			return nil, errors.WrapPrefixf(err, "CRD version has no name")
+		}
+		s, err := v.Pipe(yaml.Lookup("schema", "openAPIV3Schema"))
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if s == nil {
+			s = common
+		}
+		if s != nil {
+			schemas[name] = s
+		}
+	}
+	return schemas, nil
+}
+
+// patchSchema returns the parts of s declaring how its lists are merged,
+// with their x-kubernetes-list-type translated into the extensions
+// used by the schemas of built-in types.  It returns false if s
+// declares none.
+func patchSchema(s spec.Schema) (spec.Schema, bool) {
+	var result spec.Schema
+	result.Type = s.Type
+	found := false
+	if listType, ok := s.Extensions.GetString(kubernetesListTypeExtensionKey); ok {
+		switch listType {
+		case "map":
+			result.AddExtension(kubernetesPatchStrategyExtensionKey, "merge")
+			if keys, ok := s.Extensions[kubernetesMergeKeyMapList]; ok {
+				result.AddExtension(kubernetesMergeKeyMapList, keys)
+			}
+		case "set":
+			result.AddExtension(kubernetesPatchStrategyExtensionKey, "merge")
+		}
+		// atomic lists are replaced, as any list having a schema without a merge strategy
+		found = true
+	}
+	for _, key := range []string{kubernetesPatchStrategyExtensionKey, kubernetesMergeKeyExtensionKey} {
+		if v, ok := s.Extensions[key]; ok {
+			result.AddExtension(key, v)
+			found = true
+		}
+	}
+	for name, property := range s.Properties {
+		if ps, ok := patchSchema(property); ok {
+			if result.Properties == nil {
+				result.Properties = make(map[string]spec.Schema)
+			}
+			result.Properties[name] = ps
+			found = true
+		}
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		if ps, ok := patchSchema(*s.Items.Schema); ok {
+			result.Items = &spec.SchemaOrArray{Schema: &ps}
+			found = true
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		if ps, ok := patchSchema(*s.AdditionalProperties.Schema); ok {
+			result.AdditionalProperties = &spec.SchemaOrBool{Allows: true, Schema: &ps}
+			found = true
+		}
+	}
+	return result, found
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const crdWithListTypes = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: backends.example.com
+spec:
+  group: example.com
+  names:
+    kind: Backend
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              replicas:
+                type: integer
+              servers:
+                type: array
+                x-kubernetes-list-type: map
+                x-kubernetes-list-map-keys:
+                - host
+                - port
+                items:
+                  type: object
+                  properties:
+                    host:
+                      type: string
+                    port:
+                      type: integer
+              tags:
+                type: array
+                x-kubernetes-list-type: set
+                items:
+                  type: string
+              args:
+                type: array
+                x-kubernetes-list-type: atomic
+                items:
+                  type: string
+  - name: v2
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              replicas:
+                type: integer
+`
+
+func TestDefinitionsFromCRD(t *testing.T) {
+	definitions, err := DefinitionsFromCRD(yaml.MustParse(crdWithListTypes))
+	require.NoError(t, err)
+
+	// v2 declares no list
+	require.Len(t, definitions, 1)
+	d, found := definitions["example.com.v1.Backend"]
+	require.True(t, found)
+
+	rs := &ResourceSchema{Schema: &d}
+	assert.Nil(t, rs.Lookup("spec", "replicas"))
+
+	strategy, keys := rs.Lookup("spec", "servers").PatchStrategyAndKeyList()
+	assert.Equal(t, "merge", strategy)
+	assert.Equal(t, []string{"host", "port"}, keys)
+
+	strategy, keys = rs.Lookup("spec", "tags").PatchStrategyAndKeyList()
+	assert.Equal(t, "merge", strategy)
+	assert.Empty(t, keys)
+
+	args := rs.Lookup("spec", "args")
+	require.NotNil(t, args)
+	strategy, _ = args.PatchStrategyAndKeyList()
+	assert.Empty(t, strategy)
+}
+
+func TestAddCRDDefinitions(t *testing.T) {
+	ResetOpenAPI()
+	defer ResetOpenAPI()
+
+	require.NoError(t, AddCRDDefinitions(yaml.MustParse(crdWithListTypes)))
+	s := SchemaForResourceType(yaml.TypeMeta{APIVersion: "example.com/v1", Kind: "Backend"})
+	require.NotNil(t, s)
+	strategy, keys := s.Lookup("spec", "servers").PatchStrategyAndKeyList()
+	assert.Equal(t, "merge", strategy)
+	assert.Equal(t, []string{"host", "port"}, keys)
+	assert.Nil(t, SchemaForResourceType(yaml.TypeMeta{APIVersion: "example.com/v2", Kind: "Backend"}))
+
+	// built-in types keep their schema
+	require.NoError(t, AddCRDDefinitions(yaml.MustParse(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: deployments.apps
+spec:
+  group: apps
+  names:
+    kind: Deployment
+  versions:
+  - name: v1
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              tags:
+                type: array
+                x-kubernetes-list-type: set
+`)))
+	s = SchemaForResourceType(yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"})
+	require.NotNil(t, s)
+	assert.Nil(t, s.Lookup("spec", "tags"))
+	assert.NotNil(t, s.Lookup("spec", "template"))
+}
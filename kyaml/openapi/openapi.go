@@ -78,6 +78,12 @@ type openapiData struct {
 	// defaultBuiltInSchemaParseStatus stores the parse status of the default
 	// built-in schema.
 	defaultBuiltInSchemaParseStatus schemaParseStatus
+
+	// crdDefinitions and crdResourceTypes store the names and the resource
+	// types of the definitions added by AddCRDDefinitions, so that they can
+	// be removed
+	crdDefinitions   []string
+	crdResourceTypes []yaml.TypeMeta
 }
 
 type format string
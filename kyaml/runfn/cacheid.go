@@ -0,0 +1,133 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package runfn
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+)
+
+// containerCacheID identifies a container function for caching.  The network,
+// runtime, env and mounts are included since they may change the function
+// output: the env vars exported from the host with their host values, and
+// bind mounts with the digest of their content.  It returns "" if the
+// function can't be cached, because its image isn't pinned by digest, so
+// that its tag may be moved, or because it mounts a volume.
+func containerCacheID(spec runtimeutil.ContainerSpec, rt container.Runtime) (string, error) {
+	if !strings.Contains(spec.Image, "@sha256:") {
+		return "", nil
+	}
+	id := []string{
+		spec.Image,
+		fmt.Sprintf("network=%t", spec.Network),
+		"runtime=" + rt.Command(),
+	}
+	for _, e := range spec.Env {
+		if !strings.Contains(e, "=") {
+			if v, found := os.LookupEnv(e); found {
+				e += "=" + v
+			}
+		}
+		id = append(id, e)
+	}
+	for i := range spec.StorageMounts {
+		m := &spec.StorageMounts[i]
+		switch m.MountType {
+		case "bind":
+			digest, err := pathDigest(m.Src)
+			if err != nil {
+				return "", errors.WrapPrefixf(err, "computing the cache id of mount %s", m)
+			}
+			id = append(id, m.String()+",sha256="+digest)
+		case "tmpfs":
+			id = append(id, m.String())
+		default:
+			return "", nil
+		}
+	}
+	return strings.Join(id, "\n"), nil
+}
+
+// execCacheID identifies an exec function for caching, by its path and the
+// digest of the executable.  Relative paths are resolved as exec.Filter does.
+func execCacheID(path, workingDir string) (string, error) {
+	resolved := path
+	switch {
+	case !strings.ContainsRune(path, filepath.Separator):
+		lp, err := exec.LookPath(path)
+		if err != nil {
+			return "", errors.WrapPrefixf(err, "computing the cache id of exec function %s", path)
+		}
+		resolved = lp
+	case !filepath.IsAbs(path):
+		resolved = filepath.Join(workingDir, path)
+	}
+	digest, err := fileDigest(resolved)
+	if err != nil {
+		return "", errors.WrapPrefixf(err, "computing the cache id of exec function %s", path)
+	}
+	return path + "\nsha256=" + digest, nil
+}
+
+// fileDigest returns the hex sha256 digest of the content of the file at path.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", errors.Wrap(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pathDigest returns the hex sha256 digest of the file at path, or of the
+// names, link targets and file contents of the tree rooted at the directory
+// at path.
+func pathDigest(path string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			fmt.Fprintf(h, "dir %q\n", rel)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link %q %q\n", rel, target)
+		default:
+			digest, err := fileDigest(p)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "file %q %s\n", rel, digest)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -16,9 +16,11 @@ import (
 	"sync/atomic"
 
 	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/sandbox"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
@@ -63,9 +65,17 @@ type RunFns struct {
 	// EnableExec will enable exec functions
 	EnableExec bool
 
+	// ExecSandbox, if set, runs exec functions in restricted mode.
+	ExecSandbox *sandbox.Config
+
 	// DisableContainers will disable functions run as containers
 	DisableContainers bool
 
+	// ContainerRuntime is the name of the runtime used to run container
+	// functions, e.g. docker or podman.  If unset, it is read from the
+	// environment variable container.RuntimeEnvVar and defaults to docker.
+	ContainerRuntime string
+
 	// ResultsDir is where to write each functions results
 	ResultsDir string
 
@@ -100,6 +110,16 @@ type RunFns struct {
 
 	// WorkingDir specifies which working directory an exec function should run in.
 	WorkingDir string
+
+	// CacheDir, if set, enables caching of function outputs in this directory.
+	// A function is not executed again if its functionConfig and input are
+	// unchanged, unless its spec sets disableCache.  Container functions are
+	// only cached if their image is pinned by digest and they mount no volumes.
+	CacheDir string
+
+	// FileSystem is the file system the package at Path is read from and
+	// written to, the disk by default.
+	FileSystem filesys.FileSystemOrOnDisk
 }
 
 // Execute runs the command
@@ -129,7 +149,11 @@ func (r RunFns) getNodesAndFilters() (
 	// the same one for reading must be used for writing if deleting Resources
 	var outputPkg *kio.LocalPackageReadWriter
 	if r.Path != "" {
-		outputPkg = &kio.LocalPackageReadWriter{PackagePath: r.Path, MatchFilesGlob: kio.MatchAll}
+		outputPkg = &kio.LocalPackageReadWriter{
+			PackagePath:    r.Path,
+			MatchFilesGlob: kio.MatchAll,
+			FileSystem:     r.FileSystem,
+		}
 	}
 
 	if r.Input == nil {
@@ -484,10 +508,25 @@ func (r *RunFns) ffp(spec runtimeutil.FunctionSpec, api *yaml.RNode, currentUser
 			uidgid,
 		)
 		cf := &c
+		// The runtime is resolved here for the cache id; the filter
+		// resolves the default runtime itself otherwise.
+		rt, err := container.GetRuntime(r.ContainerRuntime)
+		if err != nil {
+			return nil, err
+		}
+		if r.ContainerRuntime != "" {
+			cf.Runtime = rt
+		}
 		cf.Exec.FunctionConfig = api
 		cf.Exec.GlobalScope = r.GlobalScope
 		cf.Exec.ResultsFile = resultsFile
 		cf.Exec.DeferFailure = spec.DeferFailure
+		err = r.setCache(&cf.Exec.FunctionFilter, spec, func() (string, error) {
+			return containerCacheID(cf.ContainerSpec, rt)
+		})
+		if err != nil {
+			return nil, err
+		}
 		return cf, nil
 	}
 
@@ -495,14 +534,37 @@ func (r *RunFns) ffp(spec runtimeutil.FunctionSpec, api *yaml.RNode, currentUser
 		ef := &exec.Filter{
 			Path:       spec.Exec.Path,
 			WorkingDir: r.WorkingDir,
+			Sandbox:    r.ExecSandbox,
 		}
 
 		ef.FunctionConfig = api
 		ef.GlobalScope = r.GlobalScope
 		ef.ResultsFile = resultsFile
 		ef.DeferFailure = spec.DeferFailure
+		err := r.setCache(&ef.FunctionFilter, spec, func() (string, error) {
+			return execCacheID(ef.Path, ef.WorkingDir)
+		})
+		if err != nil {
+			return nil, err
+		}
 		return ef, nil
 	}
 
 	return nil, nil
 }
+
+// setCache configures output caching on f if r.CacheDir is set, the
+// function hasn't opted out, and cacheID identifies it.
+func (r *RunFns) setCache(f *runtimeutil.FunctionFilter, spec runtimeutil.FunctionSpec,
+	cacheID func() (string, error)) error {
+	if r.CacheDir == "" || spec.DisableCache {
+		return nil
+	}
+	id, err := cacheID()
+	if err != nil || id == "" {
+		return err
+	}
+	f.Cache = runtimeutil.DiskCache{Dir: r.CacheDir}
+	f.CacheID = id
+	return nil
+}
@@ -14,10 +14,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/kyaml/copyutil"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
 	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
 	"sigs.k8s.io/kustomize/kyaml/kio"
 	"sigs.k8s.io/kustomize/kyaml/kio/filters"
@@ -135,6 +137,121 @@ kind:
 	assert.Equal(t, cf, filter)
 }
 
+func TestRunFns_initCacheDir(t *testing.T) {
+	t.Setenv(container.RuntimeEnvVar, "")
+	t.Setenv("HOST_VAR", "host")
+	instance := RunFns{CacheDir: "/tmp/cache"}
+	instance.init()
+	api, err := yaml.Parse(`apiVersion: apps/v1
+kind: 
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	image := "example.com@sha256:" + strings.Repeat("0", 64)
+	spec := runtimeutil.FunctionSpec{
+		Container: runtimeutil.ContainerSpec{
+			Image: image,
+			Env:   []string{"FOO=bar", "HOST_VAR"},
+		},
+	}
+	filter, _ := instance.functionFilterProvider(spec, api, currentUser)
+	c := container.NewContainer(spec.Container, "nobody")
+	cf := &c
+	cf.Exec.FunctionConfig = api
+	cf.Exec.Cache = runtimeutil.DiskCache{Dir: "/tmp/cache"}
+	cf.Exec.CacheID = image + "\nnetwork=false\nruntime=docker\nFOO=bar\nHOST_VAR=host"
+	assert.Equal(t, cf, filter)
+
+	// the network and the runtime are part of the cache id
+	spec.Container.Network = true
+	instance.ContainerRuntime = "podman"
+	filter, _ = instance.functionFilterProvider(spec, api, currentUser)
+	assert.Equal(t, image+"\nnetwork=true\nruntime=podman\nFOO=bar\nHOST_VAR=host",
+		filter.(*container.Filter).Exec.CacheID)
+	spec.Container.Network = false
+	instance.ContainerRuntime = ""
+
+	// functions can opt out of caching
+	spec.DisableCache = true
+	filter, _ = instance.functionFilterProvider(spec, api, currentUser)
+	cf.Exec.Cache = nil
+	cf.Exec.CacheID = ""
+	assert.Equal(t, cf, filter)
+	spec.DisableCache = false
+
+	// images that aren't pinned by digest, and volumes, aren't cached
+	for _, c := range []runtimeutil.ContainerSpec{
+		{Image: "example.com:latest"},
+		{Image: image, StorageMounts: []runtimeutil.StorageMount{
+			{MountType: "volume", Src: "data", DstPath: "/data"},
+		}},
+	} {
+		spec.Container = c
+		filter, err = instance.functionFilterProvider(spec, api, currentUser)
+		require.NoError(t, err)
+		assert.Nil(t, filter.(*container.Filter).Exec.Cache)
+	}
+}
+
+func TestRunFns_cacheIDMountedContent(t *testing.T) {
+	instance := RunFns{CacheDir: t.TempDir()}
+	instance.init()
+	api, err := yaml.Parse(`apiVersion: apps/v1
+kind: 
+`)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("a: 1\n"), 0600))
+	spec := runtimeutil.FunctionSpec{
+		Container: runtimeutil.ContainerSpec{
+			Image: "example.com@sha256:" + strings.Repeat("0", 64),
+			StorageMounts: []runtimeutil.StorageMount{
+				{MountType: "bind", Src: dir, DstPath: "/values"},
+			},
+		},
+	}
+	cacheID := func() string {
+		filter, err := instance.functionFilterProvider(spec, api, currentUser)
+		require.NoError(t, err)
+		return filter.(*container.Filter).Exec.CacheID
+	}
+	id := cacheID()
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, cacheID())
+
+	// a change to a mounted file changes the cache id
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("a: 2\n"), 0600))
+	assert.NotEqual(t, id, cacheID())
+}
+
+func TestRunFns_cacheIDExecutable(t *testing.T) {
+	dir := t.TempDir()
+	instance := RunFns{CacheDir: t.TempDir(), EnableExec: true, WorkingDir: dir}
+	instance.init()
+	api, err := yaml.Parse(`apiVersion: apps/v1
+kind: 
+`)
+	require.NoError(t, err)
+
+	fn := filepath.Join(dir, "fn")
+	require.NoError(t, os.WriteFile(fn, []byte("#!/bin/sh\ncat\n"), 0700))
+	spec := runtimeutil.FunctionSpec{Exec: runtimeutil.ExecSpec{Path: "./fn"}}
+	cacheID := func() string {
+		filter, err := instance.functionFilterProvider(spec, api, currentUser)
+		require.NoError(t, err)
+		return filter.(*exec.Filter).CacheID
+	}
+	id := cacheID()
+	assert.True(t, strings.HasPrefix(id, "./fn\nsha256="), id)
+
+	// a change to the executable changes the cache id
+	require.NoError(t, os.WriteFile(fn, []byte("#!/bin/sh\ncat -\n"), 0700))
+	assert.NotEqual(t, id, cacheID())
+}
+
 func TestRunFns_Execute__initDefault(t *testing.T) {
 	b := &bytes.Buffer{}
 	var tests = []struct {
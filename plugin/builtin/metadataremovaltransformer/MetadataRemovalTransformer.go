@@ -0,0 +1,67 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sigs.k8s.io/kustomize/api/filters/keyremoval"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Remove the selected labels and annotations from the given field
+// specifications, which default to the metadata of the resources.
+type plugin struct {
+	Labels      *types.KeyRemoval `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations *types.KeyRemoval `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+var KustomizePlugin plugin //nolint:gochecknoglobals
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Labels = nil
+	p.Annotations = nil
+	if err = yaml.Unmarshal(c, p); err != nil {
+		return err
+	}
+	if p.Labels != nil {
+		if err = keyremoval.ValidateRemoval(*p.Labels); err != nil {
+			return errors.WrapPrefixf(err, "invalid labels")
+		}
+	}
+	if p.Annotations != nil {
+		if err = keyremoval.ValidateRemoval(*p.Annotations); err != nil {
+			return errors.WrapPrefixf(err, "invalid annotations")
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	if !p.Labels.IsEmpty() {
+		if err := m.ApplyFilter(keyremoval.Filter{
+			Removal: *p.Labels,
+			FsSlice: fieldSpecsOrDefault(p.Labels.FieldSpecs, "metadata/labels"),
+		}); err != nil {
+			return err
+		}
+	}
+	if !p.Annotations.IsEmpty() {
+		return m.ApplyFilter(keyremoval.Filter{
+			Removal: *p.Annotations,
+			FsSlice: fieldSpecsOrDefault(p.Annotations.FieldSpecs, "metadata/annotations"),
+		})
+	}
+	return nil
+}
+
+func fieldSpecsOrDefault(fss []types.FieldSpec, path string) types.FsSlice {
+	if len(fss) == 0 {
+		return types.FsSlice{{Path: path}}
+	}
+	return fss
+}
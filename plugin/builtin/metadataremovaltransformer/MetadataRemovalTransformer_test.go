@@ -0,0 +1,122 @@
+// Copyright 2024 Nho Luong DevOps.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestMetadataRemovalTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataRemovalTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: MetadataRemovalTransformer
+metadata:
+  name: notImportantHere
+labels:
+  keys:
+  - app.kubernetes.io/managed-by
+  prefixes:
+  - helm.sh/
+  fields:
+  - path: metadata/labels
+  - path: spec/selector
+    kind: Service
+annotations:
+  regexes:
+  - meta\.helm\.sh/.*
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+  labels:
+    app: web
+    app.kubernetes.io/managed-by: Helm
+    helm.sh/chart: web-1.0.0
+  annotations:
+    meta.helm.sh/release-name: web
+    team: frontend
+spec:
+  selector:
+    app: web
+    helm.sh/chart: web-1.0.0
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    team: frontend
+  labels:
+    app: web
+  name: myService
+spec:
+  selector:
+    app: web
+`)
+}
+
+func TestMetadataRemovalTransformer_DefaultFieldSpecs(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataRemovalTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: MetadataRemovalTransformer
+metadata:
+  name: notImportantHere
+labels:
+  prefixes:
+  - helm.sh/
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+  labels:
+    helm.sh/chart: web-1.0.0
+spec:
+  selector:
+    helm.sh/chart: web-1.0.0
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+spec:
+  selector:
+    helm.sh/chart: web-1.0.0
+`)
+}
+
+func TestMetadataRemovalTransformer_InvalidRegex(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataRemovalTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: MetadataRemovalTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  regexes:
+  - "("
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`, func(t *testing.T, err error) {
+		t.Helper()
+		assert.ErrorContains(t, err, `invalid annotations: invalid regex "("`)
+	})
+}
@@ -6,9 +6,11 @@ package main
 
 import (
 	"fmt"
+	"slices"
 
 	"sigs.k8s.io/kustomize/api/filters/namespace"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/errors"
 	"sigs.k8s.io/yaml"
@@ -20,6 +22,9 @@ type plugin struct {
 	FieldSpecs             []types.FieldSpec                `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
 	UnsetOnly              bool                             `json:"unsetOnly" yaml:"unsetOnly"`
 	SetRoleBindingSubjects namespace.RoleBindingSubjectMode `json:"setRoleBindingSubjects" yaml:"setRoleBindingSubjects"`
+	Mapping                []namespace.Mapping              `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+	Select                 []*types.Selector                `json:"select,omitempty" yaml:"select,omitempty"`
+	Reject                 []*types.Selector                `json:"reject,omitempty" yaml:"reject,omitempty"`
 }
 
 var KustomizePlugin plugin //nolint:gochecknoglobals
@@ -28,9 +33,18 @@ func (p *plugin) Config(
 	_ *resmap.PluginHelpers, c []byte) (err error) {
 	p.Namespace = ""
 	p.FieldSpecs = nil
+	p.Mapping = nil
+	p.Select = nil
+	p.Reject = nil
 	if err := yaml.Unmarshal(c, p); err != nil {
 		return errors.WrapPrefixf(err, "unmarshalling NamespaceTransformer config")
 	}
+	if len(p.Mapping) > 0 && len(p.Namespace) > 0 {
+		return errors.Errorf("NamespaceTransformer cannot set both a namespace and a mapping")
+	}
+	if err := namespace.ValidateMapping(p.Mapping); err != nil {
+		return err
+	}
 	switch p.SetRoleBindingSubjects {
 	case namespace.AllServiceAccountSubjects, namespace.DefaultSubjectsOnly, namespace.NoSubjects:
 		// valid
@@ -46,10 +60,14 @@ func (p *plugin) Config(
 }
 
 func (p *plugin) Transform(m resmap.ResMap) error {
-	if len(p.Namespace) == 0 {
+	if len(p.Namespace) == 0 && len(p.Mapping) == 0 {
 		return nil
 	}
-	for _, r := range m.Resources() {
+	resources, err := p.selectedResources(m)
+	if err != nil {
+		return err
+	}
+	for _, r := range resources {
 		if r.IsNilOrEmpty() {
 			// Don't mutate empty objects?
 			continue
@@ -60,6 +78,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 			FsSlice:                p.FieldSpecs,
 			SetRoleBindingSubjects: p.SetRoleBindingSubjects,
 			UnsetOnly:              p.UnsetOnly,
+			Mapping:                p.Mapping,
 		}); err != nil {
 			return err
 		}
@@ -71,3 +90,43 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	}
 	return nil
 }
+
+// selectedResources returns the resources of m matching any of the Select
+// selectors, or all resources if there are none, except those matching any
+// of the Reject selectors.
+func (p *plugin) selectedResources(m resmap.ResMap) ([]*resource.Resource, error) {
+	resources := m.Resources()
+	if len(p.Select) > 0 {
+		selected, err := matchingResources(m, p.Select)
+		if err != nil {
+			return nil, err
+		}
+		resources = slices.DeleteFunc(resources, func(r *resource.Resource) bool {
+			return !selected[r]
+		})
+	}
+	if len(p.Reject) > 0 {
+		rejected, err := matchingResources(m, p.Reject)
+		if err != nil {
+			return nil, err
+		}
+		resources = slices.DeleteFunc(resources, func(r *resource.Resource) bool {
+			return rejected[r]
+		})
+	}
+	return resources, nil
+}
+
+func matchingResources(m resmap.ResMap, selectors []*types.Selector) (map[*resource.Resource]bool, error) {
+	result := map[*resource.Resource]bool{}
+	for _, s := range selectors {
+		matches, err := m.Select(*s)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range matches {
+			result[r] = true
+		}
+	}
+	return result, nil
+}
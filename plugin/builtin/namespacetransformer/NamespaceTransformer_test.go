@@ -762,3 +762,187 @@ subjects:
 `)
 		})
 }
+
+func TestNamespaceTransformer_Mapping(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceTransformer")
+	defer th.Reset()
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: NamespaceTransformer
+metadata:
+  name: notImportantHere
+mapping:
+- from: a
+  to: b
+reject:
+- labelSelector: pinned=true
+`+defaultFieldSpecs, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: moved
+  namespace: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pinned
+  namespace: a
+  labels:
+    pinned: "true"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: c
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unset
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: rb
+  namespace: c
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: a
+- kind: ServiceAccount
+  name: default
+  namespace: c
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: moved
+  namespace: b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    pinned: "true"
+  name: pinned
+  namespace: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: c
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unset
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: rb
+  namespace: c
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: b
+- kind: ServiceAccount
+  name: default
+  namespace: c
+`)
+}
+
+func TestNamespaceTransformer_Select(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceTransformer")
+	defer th.Reset()
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: NamespaceTransformer
+metadata:
+  name: notImportantHere
+  namespace: test
+select:
+- kind: ConfigMap
+reject:
+- name: cm2
+`+defaultFieldSpecs, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: test
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+`)
+}
+
+func TestNamespaceTransformer_InvalidMapping(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceTransformer")
+	defer th.Reset()
+	for config, expected := range map[string]string{
+		`
+metadata:
+  name: notImportantHere
+  namespace: test
+mapping:
+- from: a
+  to: b
+`: "NamespaceTransformer cannot set both a namespace and a mapping",
+		`
+metadata:
+  name: notImportantHere
+mapping:
+- from: a
+  to: b
+- from: a
+  to: c
+`: `namespace "a" is mapped more than once`,
+		`
+metadata:
+  name: notImportantHere
+mapping:
+- from: a
+`: `namespace mapping "a" -> "" must set both from and to`,
+	} {
+		th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: NamespaceTransformer`+config, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+`, func(t *testing.T, err error) {
+			t.Helper()
+			assert.ErrorContains(t, err, expected)
+		})
+	}
+}
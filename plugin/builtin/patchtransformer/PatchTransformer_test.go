@@ -987,6 +987,80 @@ spec:
 `)
 }
 
+func TestPatchTransformerJsonSkipOnFailedTest(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("PatchTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: PatchTransformer
+metadata:
+  name: notImportantHere
+patch: |-
+  - op: test
+    path: /spec/template/spec/containers/[name=nginx]/image
+    value: nginx
+  - op: replace
+    path: /spec/template/spec/containers/[name=nginx]/image
+    value: nginx:1.27
+target:
+  kind: Deployment
+options:
+  skipOnFailedTest: true
+`, someDeploymentResources, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    old-label: old-value
+  name: myDeploy
+spec:
+  replica: 2
+  template:
+    metadata:
+      labels:
+        old-label: old-value
+    spec:
+      containers:
+      - image: nginx:1.27
+        name: nginx
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    new-label: new-value
+  name: yourDeploy
+spec:
+  replica: 1
+  template:
+    metadata:
+      labels:
+        new-label: new-value
+    spec:
+      containers:
+      - image: nginx:1.7.9
+        name: nginx
+---
+apiVersion: apps/v1
+kind: MyKind
+metadata:
+  label:
+    old-label: old-value
+  name: myDeploy
+spec:
+  template:
+    metadata:
+      labels:
+        old-label: old-value
+    spec:
+      containers:
+      - image: nginx
+        name: nginx
+`)
+}
+
 // test for https://github.com/nholuongut/kustomize/issues/2767
 // currently documents broken state.  resulting ports: should have both
 // take-over-the-world and disappearing-act on 8080
@@ -6,6 +6,7 @@ package main_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
 	"sigs.k8s.io/kustomize/api/types"
 )
@@ -222,3 +223,360 @@ spec:
 		t.Fatalf("Unexpected error: %v", err)
 	}
 }
+
+func TestSelector(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplicaCountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  selector:
+    labelSelector: tier=web
+    fieldSelector: spec.replicas!=0
+  count: 3
+fieldSpecs:
+- path: spec/replicas
+  create: true
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+  labels:
+    tier: web
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: paused
+  labels:
+    tier: web
+spec:
+  replicas: 0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 1
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: web
+  name: frontend
+spec:
+  replicas: 3
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: web
+  name: paused
+spec:
+  replicas: 0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: backend
+spec:
+  replicas: 1
+`)
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  selector:
+    labelSelector: tier=db
+  count: 3
+fieldSpecs:
+- path: spec/replicas
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+`)
+	if err == nil {
+		t.Fatalf("No match should return an error")
+	}
+	if err.Error() != "resources selected by [noKind].[noVer].[noGrp]/[noName].[noNs]:a=:l=tier=db "+
+		"do not match a config with the following GVK [Deployment.[noVer].[noGrp]]" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplicaCountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  selector:
+    labelSelector: tier=web
+  adjust: "*2"
+fieldSpecs:
+- path: spec/replicas
+  create: true
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+  labels:
+    tier: web
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unscaled
+  labels:
+    tier: web
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: frontend
+  labels:
+    tier: web
+spec:
+  minReplicas: 2
+  maxReplicas: 5
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: web
+  name: frontend
+spec:
+  replicas: 4
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: web
+  name: unscaled
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  labels:
+    tier: web
+  name: frontend
+spec:
+  maxReplicas: 10
+  minReplicas: 4
+`)
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: frontend
+  adjust: "-3"
+fieldSpecs:
+- path: spec/replicas
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 2
+`)
+	require.EqualError(t, err,
+		"considering field 'spec/replicas' of object Deployment.v1.apps/frontend.[noNs]: "+
+			"adjusting the replica count 2 by -3 gives a negative count")
+}
+
+func TestAutoscalerCounts(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplicaCountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: frontend
+  minCount: 3
+  maxCount: 6
+fieldSpecs:
+- path: spec/replicas
+  create: true
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 2
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: frontend
+spec:
+  minReplicas: 1
+  maxReplicas: 2
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 2
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: frontend
+spec:
+  maxReplicas: 6
+  minReplicas: 3
+`)
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: frontend
+  minCount: 3
+`, `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: frontend
+spec:
+  minReplicas: 1
+  maxReplicas: 2
+`)
+	require.EqualError(t, err,
+		"HorizontalPodAutoscaler.v2.autoscaling/frontend.[noNs] has minReplicas 3, more than its maxReplicas 2")
+}
+
+func TestFieldPaths(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplicaCountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: cache
+  count: 3
+  fieldPaths:
+  - spec/size
+fieldSpecs:
+- path: spec/replicas
+  create: true
+  kind: Deployment
+`, `
+apiVersion: cache.example.com/v1
+kind: Redis
+metadata:
+  name: cache
+spec:
+  size: 1
+`, `
+apiVersion: cache.example.com/v1
+kind: Redis
+metadata:
+  name: cache
+spec:
+  size: 3
+`)
+}
+
+func TestDisruptionBudget(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ReplicaCountTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ReplicaCountTransformer
+metadata:
+  name: notImportantHere
+replica:
+  name: frontend
+  count: 1
+fieldSpecs:
+- path: spec/replicas
+  create: true
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: frontend
+spec:
+  replicas: 3
+  template:
+    metadata:
+      labels:
+        app: frontend
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: frontend
+spec:
+  minAvailable: 2
+  selector:
+    matchLabels:
+      app: frontend
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: other
+spec:
+  minAvailable: 5
+  selector:
+    matchExpressions:
+    - key: app
+      operator: In
+      values: [backend]
+`)
+	require.EqualError(t, err,
+		"PodDisruptionBudget.v1.policy/frontend.[noNs] requires 2 available pods, "+
+			"more than the 1 replicas of Deployment.v1.apps/frontend.[noNs]")
+}